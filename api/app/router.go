@@ -4,13 +4,14 @@ import (
 	"github.com/gin-gonic/gin"
 
 	v1 "api-server/api/app/v1"
+	admindomain "api-server/domain/admin"
 )
 
 // RegisterRoutes 在 /api 下挂载各版本路由
-func RegisterRoutes(api *gin.RouterGroup) {
+func RegisterRoutes(api *gin.RouterGroup, services admindomain.Services) {
 	if api == nil {
 		return
 	}
 	v1Group := api.Group("/v1")
-	v1.RegisterRoutes(v1Group)
+	v1.RegisterRoutes(v1Group, services)
 }
@@ -0,0 +1,150 @@
+package diagnostics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/api/middleware"
+	"api-server/api/response"
+	domain "api-server/domain/diagnostics"
+)
+
+// GetBuild 返回构建版本信息
+// GET /api/v1/private/admin/platform/system/runtime/build
+func GetBuild(c *gin.Context) {
+	info := domain.GetBuildInfo()
+	response.ReturnData(c, BuildDTO{
+		Version:   info.Version,
+		BuildTime: info.BuildTime,
+		GitCommit: info.GitCommit,
+		GoVersion: info.GoVersion,
+	})
+}
+
+// GetConfig 返回当前生效配置（敏感项已脱敏）
+// GET /api/v1/private/admin/platform/system/runtime/config
+func GetConfig(c *gin.Context) {
+	response.ReturnData(c, domain.GetConfig())
+}
+
+// GetPendingRestart 返回启动后已修改、需重启服务才能生效的配置键（如端口、TLS 模式）
+// GET /api/v1/private/admin/platform/system/runtime/config/pending-restart
+func GetPendingRestart(c *gin.Context) {
+	keys := domain.PendingRestart()
+	if keys == nil {
+		keys = []string{}
+	}
+	response.ReturnDataWithTotal(c, len(keys), keys)
+}
+
+// GetDatabase 返回 Postgres 连接池统计
+// GET /api/v1/private/admin/platform/system/runtime/db
+func GetDatabase(c *gin.Context) {
+	stats, err := domain.GetDatabaseStats()
+	if err != nil {
+		ReturnDomainError(c, err, "获取数据库连接池信息失败")
+		return
+	}
+	response.ReturnData(c, DatabaseStatsDTO{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	})
+}
+
+// GetRedis 返回 Redis 连接池统计
+// GET /api/v1/private/admin/platform/system/runtime/redis
+func GetRedis(c *gin.Context) {
+	stats, err := domain.GetRedisStats()
+	if err != nil {
+		ReturnDomainError(c, err, "获取 Redis 连接池信息失败")
+		return
+	}
+	response.ReturnData(c, RedisStatsDTO{
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+	})
+}
+
+// GetProcess 返回 goroutine 数量与内存统计
+// GET /api/v1/private/admin/platform/system/runtime/process
+func GetProcess(c *gin.Context) {
+	info := domain.GetProcessInfo()
+	response.ReturnData(c, ProcessDTO{
+		Goroutines: info.Goroutines,
+		NumCPU:     info.NumCPU,
+		GOMAXPROCS: info.GOMAXPROCS,
+		Uptime:     info.Uptime.String(),
+		Memory: MemoryDTO{
+			Alloc:        info.Memory.Alloc,
+			TotalAlloc:   info.Memory.TotalAlloc,
+			Sys:          info.Memory.Sys,
+			HeapAlloc:    info.Memory.HeapAlloc,
+			HeapInuse:    info.Memory.HeapInuse,
+			HeapObjects:  info.Memory.HeapObjects,
+			StackInuse:   info.Memory.StackInuse,
+			NextGCTarget: info.Memory.NextGCTarget,
+			NumGC:        info.Memory.NumGC,
+			PauseTotal:   info.Memory.PauseTotal.String(),
+			LastGC:       unixOrZero(info.Memory.LastGC),
+		},
+	})
+}
+
+// GetCronJobs 返回已注册的定时任务及其上次/下次执行时间
+// GET /api/v1/private/admin/platform/system/runtime/cron
+func GetCronJobs(c *gin.Context) {
+	jobs := domain.ListCronJobs()
+	items := make([]CronJobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		tags := job.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		items = append(items, CronJobDTO{
+			ID:      job.ID,
+			Name:    job.Name,
+			Tags:    tags,
+			NextRun: unixOrZero(job.NextRun),
+			LastRun: unixOrZero(job.LastRun),
+		})
+	}
+	response.ReturnDataWithTotal(c, len(items), items)
+}
+
+// GetRateLimiters 返回所有限流器统计
+// GET /api/v1/private/admin/platform/system/runtime/rate-limit
+func GetRateLimiters(c *gin.Context) {
+	stats := middleware.GetAllStats()
+	items := make([]RateLimiterDTO, 0, len(stats))
+	for key, s := range stats {
+		items = append(items, RateLimiterDTO{
+			Key:           key,
+			TotalLimiters: s.TotalLimiters,
+			Rate:          float64(s.Rate),
+			Burst:         s.Burst,
+			TTL:           s.TTL.String(),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	response.ReturnDataWithTotal(c, len(items), items)
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,84 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	domain "api-server/domain/diagnostics"
+)
+
+func setupTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestGetBuild(t *testing.T) {
+	domain.SetBuildInfo("v1.2.3", "2025-01-01_00:00:00", "abc1234")
+
+	router := setupTestRouter()
+	router.GET("/build", GetBuild)
+
+	req, _ := http.NewRequest(http.MethodGet, "/build", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp struct {
+		Code int      `json:"code"`
+		Data BuildDTO `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Code != 200 {
+		t.Fatalf("Code = %d, want 200", resp.Code)
+	}
+	if resp.Data.Version != "v1.2.3" || resp.Data.GitCommit != "abc1234" {
+		t.Errorf("unexpected build info: %+v", resp.Data)
+	}
+	if resp.Data.GoVersion == "" {
+		t.Errorf("GoVersion should not be empty")
+	}
+}
+
+func TestGetProcess(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/process", GetProcess)
+
+	req, _ := http.NewRequest(http.MethodGet, "/process", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp struct {
+		Code int        `json:"code"`
+		Data ProcessDTO `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Data.Goroutines <= 0 {
+		t.Errorf("Goroutines = %d, want > 0", resp.Data.Goroutines)
+	}
+	if resp.Data.Memory.Sys == 0 {
+		t.Errorf("Memory.Sys should not be zero")
+	}
+}
+
+func TestPprofIndex(t *testing.T) {
+	router := setupTestRouter()
+	RegisterRoutes(router.Group("/runtime"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/runtime/pprof/goroutine?debug=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("HTTP status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.Len() == 0 {
+		t.Errorf("goroutine profile should not be empty")
+	}
+}
@@ -0,0 +1,90 @@
+package diagnostics
+
+// BuildDTO 构建版本信息
+type BuildDTO struct {
+	Version   string `json:"version"`
+	BuildTime string `json:"build_time"`
+	GitCommit string `json:"git_commit"`
+	GoVersion string `json:"go_version"`
+}
+
+// DatabaseStatsDTO Postgres 连接池统计（来源于 sql.DB.Stats）
+type DatabaseStatsDTO struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// RedisStatsDTO Redis 连接池统计
+type RedisStatsDTO struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+// MemoryDTO 内存与 GC 统计（单位：字节）
+type MemoryDTO struct {
+	Alloc        uint64 `json:"alloc"`
+	TotalAlloc   uint64 `json:"total_alloc"`
+	Sys          uint64 `json:"sys"`
+	HeapAlloc    uint64 `json:"heap_alloc"`
+	HeapInuse    uint64 `json:"heap_inuse"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	StackInuse   uint64 `json:"stack_inuse"`
+	NextGCTarget uint64 `json:"next_gc_target"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotal   string `json:"pause_total"`
+	LastGC       int64  `json:"last_gc"`
+}
+
+// ProcessDTO 进程运行状态
+type ProcessDTO struct {
+	Goroutines int       `json:"goroutines"`
+	NumCPU     int       `json:"num_cpu"`
+	GOMAXPROCS int       `json:"gomaxprocs"`
+	Uptime     string    `json:"uptime"`
+	Memory     MemoryDTO `json:"memory"`
+}
+
+// CronJobDTO 定时任务信息，时间为 Unix 秒（0 表示尚未执行/未排期）
+type CronJobDTO struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+	NextRun int64    `json:"next_run"`
+	LastRun int64    `json:"last_run"`
+}
+
+// RateLimiterDTO 限流器统计
+type RateLimiterDTO struct {
+	Key           string  `json:"key"`
+	TotalLimiters int     `json:"total_limiters"`
+	Rate          float64 `json:"rate"`
+	Burst         int     `json:"burst"`
+	TTL           string  `json:"ttl"`
+}
+
+// LogLevelDTO 具名 logger 级别状态
+type LogLevelDTO struct {
+	Name            string `json:"name"`
+	Level           string `json:"level"`
+	ConfiguredLevel string `json:"configured_level"`
+	OverrideUntil   int64  `json:"override_until"` // Unix 秒，0 表示未临时调整
+}
+
+// LogSinkDTO 远程日志输出统计
+type LogSinkDTO struct {
+	Name    string `json:"name"`
+	Queued  int    `json:"queued"`  // 队列中待发送条数
+	Dropped uint64 `json:"dropped"` // 队列满被丢弃的条数
+	Failed  uint64 `json:"failed"`  // 发送失败的条数
+}
@@ -0,0 +1,32 @@
+package diagnostics
+
+import (
+	"errors"
+
+	"api-server/api/response"
+	domain "api-server/domain/diagnostics"
+	"api-server/util/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReturnDomainError 将 domain 层错误映射为统一的接口错误响应。
+func ReturnDomainError(c *gin.Context, err error, fallback string) {
+	log.WithRequest(c).Error("运行时诊断领域错误", zap.Error(err))
+
+	switch {
+	case errors.Is(err, domain.ErrDatabaseUnavailable):
+		response.ReturnError(c, response.UNAVAILABLE, "数据库连接不可用")
+	case errors.Is(err, domain.ErrRedisUnavailable):
+		response.ReturnError(c, response.UNAVAILABLE, "Redis 连接不可用")
+	case errors.Is(err, domain.ErrUnknownLogger):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "不支持的 logger 名称")
+	case errors.Is(err, domain.ErrInvalidLogLevel):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "日志级别无效，可选 debug/info/warn/error")
+	case errors.Is(err, domain.ErrInvalidOverrideDuration):
+		response.ReturnError(c, response.OUT_OF_RANGE, "临时调整时长需在 1 分钟到 24 小时之间")
+	default:
+		response.ReturnError(c, response.INTERNAL, fallback)
+	}
+}
@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"api-server/api/middleware"
+	"api-server/api/response"
+	domain "api-server/domain/diagnostics"
+	"api-server/util/log"
+)
+
+// GetLogLevels 返回各具名 logger 的级别状态
+// GET /api/v1/private/admin/platform/system/runtime/log-level
+func GetLogLevels(c *gin.Context) {
+	levels := domain.ListLogLevels()
+	items := make([]LogLevelDTO, 0, len(levels))
+	for _, item := range levels {
+		items = append(items, toLogLevelDTO(item))
+	}
+	response.ReturnDataWithTotal(c, len(items), items)
+}
+
+// UpdateLogLevel 临时调整 logger 级别，到期自动恢复
+// PUT /api/v1/private/admin/platform/system/runtime/log-level
+func UpdateLogLevel(c *gin.Context) {
+	params := &struct {
+		Name    string `json:"name" form:"name" binding:"required"`
+		Level   string `json:"level" form:"level" binding:"required"`
+		Minutes int    `json:"minutes" form:"minutes" binding:"required"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+	status, err := domain.SetLogLevel(params.Name, params.Level, params.Minutes)
+	if err != nil {
+		ReturnDomainError(c, err, "调整日志级别失败")
+		return
+	}
+	log.WithRequest(c).Warn("平台管理员临时调整日志级别")
+	response.ReturnData(c, toLogLevelDTO(status))
+}
+
+// ResetLogLevel 立即恢复 logger 为配置级别
+// DELETE /api/v1/private/admin/platform/system/runtime/log-level
+func ResetLogLevel(c *gin.Context) {
+	params := &struct {
+		Name string `json:"name" form:"name" binding:"required"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+	status, err := domain.ResetLogLevel(params.Name)
+	if err != nil {
+		ReturnDomainError(c, err, "恢复日志级别失败")
+		return
+	}
+	response.ReturnData(c, toLogLevelDTO(status))
+}
+
+func toLogLevelDTO(item log.LevelStatus) LogLevelDTO {
+	return LogLevelDTO{
+		Name:            item.Name,
+		Level:           item.Level.String(),
+		ConfiguredLevel: item.ConfiguredLevel.String(),
+		OverrideUntil:   unixOrZero(item.OverrideUntil),
+	}
+}
+
+// GetLogSinks 返回远程日志输出（syslog、HTTP）的队列与丢弃统计
+// GET /api/v1/private/admin/platform/system/runtime/log-sinks
+func GetLogSinks(c *gin.Context) {
+	stats := log.SinkStatsList()
+	items := make([]LogSinkDTO, 0, len(stats))
+	for _, s := range stats {
+		items = append(items, LogSinkDTO{
+			Name:    s.Name,
+			Queued:  s.Queued,
+			Dropped: s.Dropped,
+			Failed:  s.Failed,
+		})
+	}
+	response.ReturnDataWithTotal(c, len(items), items)
+}
@@ -0,0 +1,22 @@
+package diagnostics
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPprofRoutes 挂载 net/http/pprof（Go 性能剖析）接口。
+// 与标准库默认的 /debug/pprof 不同，这里挂在受超级管理员保护的分组下，
+// 具名 profile（heap、goroutine、allocs 等）通过 pprof.Handler 按名称分发。
+func registerPprofRoutes(group *gin.RouterGroup) {
+	group.GET("/", gin.WrapF(pprof.Index))
+	group.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/profile", gin.WrapF(pprof.Profile))
+	group.GET("/symbol", gin.WrapF(pprof.Symbol))
+	group.POST("/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/trace", gin.WrapF(pprof.Trace))
+	group.GET("/:name", func(c *gin.Context) {
+		pprof.Handler(c.Param("name")).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -0,0 +1,24 @@
+package diagnostics
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes 注册运行时诊断接口，调用方负责挂载超级管理员鉴权中间件
+// /api/v1/private/admin/platform/system/runtime
+func RegisterRoutes(group *gin.RouterGroup) {
+	if group == nil {
+		return
+	}
+	group.GET("/build", GetBuild)
+	group.GET("/config", GetConfig)
+	group.GET("/config/pending-restart", GetPendingRestart)
+	group.GET("/db", GetDatabase)
+	group.GET("/redis", GetRedis)
+	group.GET("/process", GetProcess)
+	group.GET("/cron", GetCronJobs)
+	group.GET("/rate-limit", GetRateLimiters)
+	group.GET("/log-level", GetLogLevels)
+	group.PUT("/log-level", UpdateLogLevel)
+	group.DELETE("/log-level", ResetLogLevel)
+	group.GET("/log-sinks", GetLogSinks)
+	registerPprofRoutes(group.Group("/pprof"))
+}
@@ -0,0 +1,15 @@
+package ipaccess
+
+import domain "api-server/domain/ipaccess"
+
+// RulesDTO 单个作用域的允许、拒绝名单
+type RulesDTO struct {
+	Scope    string   `json:"scope"`
+	TenantID uint     `json:"tenant_id"`
+	Allow    []string `json:"allow"`
+	Deny     []string `json:"deny"`
+}
+
+func toRulesDTO(r domain.Rules) RulesDTO {
+	return RulesDTO{Scope: r.Scope, TenantID: r.TenantID, Allow: r.Allow, Deny: r.Deny}
+}
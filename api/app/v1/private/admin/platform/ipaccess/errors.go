@@ -0,0 +1,32 @@
+package ipaccess
+
+import (
+	"errors"
+
+	"api-server/api/response"
+	domain "api-server/domain/ipaccess"
+	"api-server/util/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReturnDomainError 将 domain 层错误映射为统一的接口错误响应。
+func ReturnDomainError(c *gin.Context, err error, fallback string) {
+	log.WithRequest(c).Error("IP 访问控制领域错误", zap.Error(err))
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidScope):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "作用域无效，可选 global/tenant/platform")
+	case errors.Is(err, domain.ErrTenantRequired):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "作用域为 tenant 时需指定 tenant_id")
+	case errors.Is(err, domain.ErrInvalidCIDR):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "网段格式无效，应形如 203.0.113.0/24 或单个 IP")
+	case errors.Is(err, domain.ErrTenantNotFound):
+		response.ReturnError(c, response.NOT_FOUND, "租户不存在")
+	case errors.Is(err, domain.ErrSelfLockout):
+		response.ReturnError(c, response.FAILED_PRECONDITION, "保存后当前网络将无法访问平台管理接口，请先将当前 IP 加入允许名单")
+	default:
+		response.ReturnError(c, response.DATA_LOSS, fallback)
+	}
+}
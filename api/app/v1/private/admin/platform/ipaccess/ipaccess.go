@@ -0,0 +1,70 @@
+package ipaccess
+
+import (
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"api-server/api/middleware"
+	"api-server/api/response"
+	domain "api-server/domain/ipaccess"
+	"api-server/util/log"
+)
+
+// GetRules 查询 IP 访问控制名单；携带 scope 时只返回该作用域（tenant 作用域需同时携带 tenant_id）
+// GET /api/v1/private/admin/platform/ip-access
+func GetRules(c *gin.Context) {
+	params := &struct {
+		Scope    string `json:"scope" form:"scope"`
+		TenantID uint   `json:"tenant_id" form:"tenant_id"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	rules, err := domain.ListRules(c.Request.Context(), params.Scope, params.TenantID)
+	if err != nil {
+		ReturnDomainError(c, err, "查询 IP 访问控制名单失败")
+		return
+	}
+	items := make([]RulesDTO, 0, len(rules))
+	for _, r := range rules {
+		items = append(items, toRulesDTO(r))
+	}
+	response.ReturnDataWithTotal(c, len(items), items)
+}
+
+// SaveRules 覆盖某个作用域的允许、拒绝名单，传入空数组表示清空
+// PUT /api/v1/private/admin/platform/ip-access
+func SaveRules(c *gin.Context) {
+	params := &struct {
+		Scope    string   `json:"scope" form:"scope" binding:"required"`
+		TenantID uint     `json:"tenant_id" form:"tenant_id"`
+		Allow    []string `json:"allow" form:"allow"`
+		Deny     []string `json:"deny" form:"deny"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	caller := domain.Caller{TenantID: middleware.GetTenantID(c)}
+	caller.IP, _ = netip.ParseAddr(c.ClientIP())
+	saved, err := domain.SaveRules(c.Request.Context(), domain.Rules{
+		Scope:    params.Scope,
+		TenantID: params.TenantID,
+		Allow:    params.Allow,
+		Deny:     params.Deny,
+	}, caller)
+	if err != nil {
+		ReturnDomainError(c, err, "保存 IP 访问控制名单失败")
+		return
+	}
+	log.WithRequest(c).Info("平台管理员更新 IP 访问控制名单",
+		zap.String("scope", saved.Scope),
+		zap.Uint("rule_tenant_id", saved.TenantID),
+		zap.Strings("allow", saved.Allow),
+		zap.Strings("deny", saved.Deny),
+	)
+	response.ReturnData(c, toRulesDTO(saved))
+}
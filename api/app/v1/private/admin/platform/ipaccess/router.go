@@ -0,0 +1,13 @@
+package ipaccess
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes 注册 IP 访问控制名单管理接口，调用方负责挂载超级管理员鉴权中间件
+// /api/v1/private/admin/platform/ip-access
+func RegisterRoutes(group *gin.RouterGroup) {
+	if group == nil {
+		return
+	}
+	group.GET("", GetRules)
+	group.PUT("", SaveRules)
+}
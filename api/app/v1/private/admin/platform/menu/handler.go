@@ -0,0 +1,16 @@
+package menu
+
+import (
+	menudomain "api-server/domain/admin/menu"
+)
+
+// Handler 平台菜单管理接口，依赖的领域服务在启动时注入
+type Handler struct {
+	menus *menudomain.Service
+}
+
+func NewHandler(menus *menudomain.Service) *Handler {
+	return &Handler{
+		menus: menus,
+	}
+}
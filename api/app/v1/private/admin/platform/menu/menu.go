@@ -14,8 +14,8 @@ import (
 
 // GetMenuList 获取平台菜单定义（不带租户 hasPermission 标记）。
 // GET /api/v1/admin/platform/menu
-func GetMenuList(c *gin.Context) {
-	menuTree, err := menudomain.GetPlatformMenuTree()
+func (h *Handler) GetMenuList(c *gin.Context) {
+	menuTree, err := h.menus.GetPlatformMenuTree(c.Request.Context())
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "查询菜单失败")
 		return
@@ -23,7 +23,7 @@ func GetMenuList(c *gin.Context) {
 	response.ReturnData(c, menuTree)
 }
 
-func AddMenu(c *gin.Context) {
+func (h *Handler) AddMenu(c *gin.Context) {
 	params := &struct {
 		Path          string `json:"path" form:"path" binding:"required"`
 		Name          string `json:"name" form:"name" binding:"required"`
@@ -46,7 +46,7 @@ func AddMenu(c *gin.Context) {
 		return
 	}
 
-	menuEntity, err := menudomain.AddMenu(menudomain.AddMenuInput{
+	menuEntity, err := h.menus.AddMenu(c.Request.Context(), menudomain.AddMenuInput{
 		Path:          params.Path,
 		Name:          params.Name,
 		Component:     params.Component,
@@ -73,7 +73,7 @@ func AddMenu(c *gin.Context) {
 
 // UpdateMenu 更新平台菜单定义
 // PUT /api/v1/admin/platform/menu
-func UpdateMenu(c *gin.Context) {
+func (h *Handler) UpdateMenu(c *gin.Context) {
 	// 菜单定义更新
 	params := &struct {
 		ID            uint   `json:"id" form:"id" binding:"required"`
@@ -98,7 +98,7 @@ func UpdateMenu(c *gin.Context) {
 		return
 	}
 
-	menuEntity, err := menudomain.UpdateMenu(menudomain.UpdateMenuInput{
+	menuEntity, err := h.menus.UpdateMenu(c.Request.Context(), menudomain.UpdateMenuInput{
 		ID:            params.ID,
 		Path:          params.Path,
 		Name:          params.Name,
@@ -124,14 +124,14 @@ func UpdateMenu(c *gin.Context) {
 	response.ReturnData(c, menuEntity)
 }
 
-func DeleteMenu(c *gin.Context) {
+func (h *Handler) DeleteMenu(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	menuEntity, err := menudomain.DeleteMenu(params.ID)
+	menuEntity, err := h.menus.DeleteMenu(c.Request.Context(), params.ID)
 	if err != nil {
 		ReturnDomainError(c, err, "删除菜单失败")
 		return
@@ -139,14 +139,14 @@ func DeleteMenu(c *gin.Context) {
 	response.ReturnData(c, menuEntity)
 }
 
-func GetMenuAuthList(c *gin.Context) {
+func (h *Handler) GetMenuAuthList(c *gin.Context) {
 	params := &struct {
 		MenuID uint `json:"menu_id" form:"menu_id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	auths, err := menudomain.GetMenuAuthList(params.MenuID)
+	auths, err := h.menus.GetMenuAuthList(c.Request.Context(), params.MenuID)
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "查询菜单权限失败")
 		return
@@ -154,7 +154,7 @@ func GetMenuAuthList(c *gin.Context) {
 	response.ReturnData(c, auths)
 }
 
-func AddMenuAuth(c *gin.Context) {
+func (h *Handler) AddMenuAuth(c *gin.Context) {
 	params := &struct {
 		MenuID uint   `json:"menu_id"`
 		Mark   string `json:"mark"`
@@ -164,7 +164,7 @@ func AddMenuAuth(c *gin.Context) {
 		return
 	}
 
-	auth, err := menudomain.AddMenuAuth(menudomain.AddMenuAuthInput{
+	auth, err := h.menus.AddMenuAuth(c.Request.Context(), menudomain.AddMenuAuthInput{
 		MenuID: params.MenuID,
 		Mark:   params.Mark,
 		Title:  params.Title,
@@ -176,7 +176,7 @@ func AddMenuAuth(c *gin.Context) {
 	response.ReturnData(c, auth)
 }
 
-func UpdateMenuAuth(c *gin.Context) {
+func (h *Handler) UpdateMenuAuth(c *gin.Context) {
 	params := &struct {
 		ID     uint   `json:"id"`
 		Title  string `json:"title"`
@@ -187,7 +187,7 @@ func UpdateMenuAuth(c *gin.Context) {
 		return
 	}
 
-	auth, err := menudomain.UpdateMenuAuth(menudomain.UpdateMenuAuthInput{
+	auth, err := h.menus.UpdateMenuAuth(c.Request.Context(), menudomain.UpdateMenuAuthInput{
 		ID:     params.ID,
 		Title:  params.Title,
 		Mark:   params.Mark,
@@ -200,14 +200,14 @@ func UpdateMenuAuth(c *gin.Context) {
 	response.ReturnData(c, auth)
 }
 
-func DeleteMenuAuth(c *gin.Context) {
+func (h *Handler) DeleteMenuAuth(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	auth, err := menudomain.DeleteMenuAuth(params.ID)
+	auth, err := h.menus.DeleteMenuAuth(c.Request.Context(), params.ID)
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "删除菜单权限失败")
 		return
@@ -217,7 +217,7 @@ func DeleteMenuAuth(c *gin.Context) {
 
 // GetTenantMenu 获取指定租户的菜单范围（带菜单与按钮权限标记）
 // GET /api/v1/admin/platform/menu/tenant?tenant_id={id}
-func GetTenantMenu(c *gin.Context) {
+func (h *Handler) GetTenantMenu(c *gin.Context) {
 	tenantIDParam := c.Query("tenant_id")
 	if tenantIDParam == "" {
 		response.ReturnError(c, response.INVALID_ARGUMENT, "tenant_id 为必填参数")
@@ -228,7 +228,7 @@ func GetTenantMenu(c *gin.Context) {
 		response.ReturnError(c, response.INVALID_ARGUMENT, "tenant_id 参数无效")
 		return
 	}
-	tree, err := menudomain.GetTenantMenuTree(uint(tenantIDValue))
+	tree, err := h.menus.GetTenantMenuTree(c.Request.Context(), uint(tenantIDValue))
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "获取租户菜单范围失败")
 		return
@@ -238,7 +238,7 @@ func GetTenantMenu(c *gin.Context) {
 
 // UpdateTenantMenu 更新指定租户的菜单范围与按钮权限范围
 // PUT /api/v1/admin/platform/menu/tenant
-func UpdateTenantMenu(c *gin.Context) {
+func (h *Handler) UpdateTenantMenu(c *gin.Context) {
 	if !middleware.IsSuperAdmin(c) {
 		response.ReturnError(c, response.PERMISSION_DENIED, "仅平台管理员可以调整租户菜单范围")
 		return
@@ -257,7 +257,7 @@ func UpdateTenantMenu(c *gin.Context) {
 		return
 	}
 	// 从全量树中直接提取被勾选的菜单与按钮权限
-	tree, err := menudomain.UpdateTenantMenuScope(req.TenantID, menuData)
+	tree, err := h.menus.UpdateTenantMenuScope(c.Request.Context(), req.TenantID, menuData)
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "更新租户菜单范围失败")
 		return
@@ -28,7 +28,7 @@ func TestUpdateTenantMenu_NotSuperAdmin(t *testing.T) {
 		c.Set("user_id", uint(2))
 		c.Next()
 	})
-	router.PUT("/tenant", UpdateTenantMenu)
+	router.PUT("/tenant", NewHandler(nil).UpdateTenantMenu)
 
 	req, _ := http.NewRequest(http.MethodPut, "/tenant", nil)
 	w := httptest.NewRecorder()
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"api-server/config"
+	domain "api-server/domain/ratelimit"
+)
+
+// RuleDTO 单条限流规则；rate 为每秒放行数，0 表示不限流
+type RuleDTO struct {
+	Rate  float64 `json:"rate" form:"rate"`
+	Burst int     `json:"burst" form:"burst"`
+}
+
+// PolicyDTO 路由组限流策略
+type PolicyDTO struct {
+	TenantID   uint    `json:"tenant_id"`
+	RouteGroup string  `json:"route_group"`
+	Tenant     RuleDTO `json:"tenant"`
+	User       RuleDTO `json:"user"`
+	Overridden bool    `json:"overridden"` // true 表示来自平台覆盖，false 表示配置文件默认策略
+}
+
+func toRuleDTO(rule config.RateLimitRule) RuleDTO {
+	return RuleDTO{Rate: rule.Rate, Burst: rule.Burst}
+}
+
+func toPolicyDTO(item domain.Override) PolicyDTO {
+	return PolicyDTO{
+		TenantID:   item.TenantID,
+		RouteGroup: item.RouteGroup,
+		Tenant:     toRuleDTO(item.Policy.Tenant),
+		User:       toRuleDTO(item.Policy.User),
+		Overridden: true,
+	}
+}
@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"errors"
+
+	"api-server/api/response"
+	domain "api-server/domain/ratelimit"
+	"api-server/util/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReturnDomainError 将 domain 层错误映射为统一的接口错误响应。
+func ReturnDomainError(c *gin.Context, err error, fallback string) {
+	log.WithRequest(c).Error("限流策略领域错误", zap.Error(err))
+
+	switch {
+	case errors.Is(err, domain.ErrUnknownRouteGroup):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "路由组无效，可选 system/platform")
+	case errors.Is(err, domain.ErrInvalidRule):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "限流规则无效：速率不能为负，启用时突发容量至少为 1")
+	case errors.Is(err, domain.ErrTenantNotFound):
+		response.ReturnError(c, response.NOT_FOUND, "租户不存在")
+	case errors.Is(err, domain.ErrOverrideNotFound):
+		response.ReturnError(c, response.NOT_FOUND, "该租户未设置此路由组的限流覆盖")
+	default:
+		response.ReturnError(c, response.DATA_LOSS, fallback)
+	}
+}
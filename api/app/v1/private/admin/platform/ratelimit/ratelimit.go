@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"api-server/api/middleware"
+	"api-server/api/response"
+	"api-server/config"
+	domain "api-server/domain/ratelimit"
+	"api-server/util/log"
+)
+
+// GetOverrides 查询租户限流覆盖；携带 tenant_id 时返回该租户各路由组的生效策略
+// GET /api/v1/private/admin/platform/tenant/rate-limit
+func GetOverrides(c *gin.Context) {
+	params := &struct {
+		TenantID uint `json:"tenant_id" form:"tenant_id"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	if params.TenantID != 0 {
+		policies, err := domain.GetEffectivePolicies(c.Request.Context(), params.TenantID)
+		if err != nil {
+			ReturnDomainError(c, err, "查询租户限流策略失败")
+			return
+		}
+		items := make([]PolicyDTO, 0, len(policies))
+		for _, p := range policies {
+			items = append(items, PolicyDTO{
+				TenantID:   params.TenantID,
+				RouteGroup: p.RouteGroup,
+				Tenant:     toRuleDTO(p.Policy.Tenant),
+				User:       toRuleDTO(p.Policy.User),
+				Overridden: p.Overridden,
+			})
+		}
+		response.ReturnDataWithTotal(c, len(items), items)
+		return
+	}
+
+	overrides, err := domain.ListOverrides(c.Request.Context(), 0)
+	if err != nil {
+		ReturnDomainError(c, err, "查询租户限流策略失败")
+		return
+	}
+	items := make([]PolicyDTO, 0, len(overrides))
+	for _, item := range overrides {
+		items = append(items, toPolicyDTO(item))
+	}
+	response.ReturnDataWithTotal(c, len(items), items)
+}
+
+// SaveOverride 为租户设置路由组限流覆盖
+// PUT /api/v1/private/admin/platform/tenant/rate-limit
+func SaveOverride(c *gin.Context) {
+	params := &struct {
+		TenantID   uint    `json:"tenant_id" form:"tenant_id" binding:"required"`
+		RouteGroup string  `json:"route_group" form:"route_group" binding:"required"`
+		Tenant     RuleDTO `json:"tenant" form:"tenant"`
+		User       RuleDTO `json:"user" form:"user"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	err := domain.SaveOverride(c.Request.Context(), domain.Override{
+		TenantID:   params.TenantID,
+		RouteGroup: params.RouteGroup,
+		Policy: config.RateLimitPolicy{
+			Tenant: config.RateLimitRule{Rate: params.Tenant.Rate, Burst: params.Tenant.Burst},
+			User:   config.RateLimitRule{Rate: params.User.Rate, Burst: params.User.Burst},
+		},
+	})
+	if err != nil {
+		ReturnDomainError(c, err, "保存租户限流策略失败")
+		return
+	}
+	log.WithRequest(c).Info("平台管理员更新租户限流策略")
+	response.ReturnSuccess(c)
+}
+
+// DeleteOverride 删除租户路由组限流覆盖，恢复为配置文件默认策略
+// DELETE /api/v1/private/admin/platform/tenant/rate-limit
+func DeleteOverride(c *gin.Context) {
+	params := &struct {
+		TenantID   uint   `json:"tenant_id" form:"tenant_id" binding:"required"`
+		RouteGroup string `json:"route_group" form:"route_group" binding:"required"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+	if err := domain.DeleteOverride(c.Request.Context(), params.TenantID, params.RouteGroup); err != nil {
+		ReturnDomainError(c, err, "删除租户限流策略失败")
+		return
+	}
+	response.ReturnSuccess(c)
+}
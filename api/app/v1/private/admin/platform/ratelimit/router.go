@@ -0,0 +1,14 @@
+package ratelimit
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes 注册租户限流策略管理接口，调用方负责挂载超级管理员鉴权中间件
+// /api/v1/private/admin/platform/tenant/rate-limit
+func RegisterRoutes(group *gin.RouterGroup) {
+	if group == nil {
+		return
+	}
+	group.GET("", GetOverrides)
+	group.PUT("", SaveOverride)
+	group.DELETE("", DeleteOverride)
+}
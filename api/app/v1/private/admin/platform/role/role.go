@@ -10,7 +10,7 @@ import (
 	roledomain "api-server/domain/admin/role"
 )
 
-func GetRoleList(c *gin.Context) {
+func (h *Handler) GetRoleList(c *gin.Context) {
 	tenantIDParam := c.Query("tenant_id")
 	if tenantIDParam == "" {
 		response.ReturnError(c, response.INVALID_ARGUMENT, "tenant_id 为必填参数")
@@ -33,7 +33,7 @@ func GetRoleList(c *gin.Context) {
 	page := middleware.GetPage(c)
 	pageSize := middleware.GetPageSize(c)
 
-	roles, total, err := roledomain.FindRoleList(roledomain.FindListQuery{
+	roles, total, err := h.roles.FindRoleList(c.Request.Context(), roledomain.FindListQuery{
 		TenantID: uint(tenantIDValue),
 		Name:     params.Name,
 		Status:   params.Status,
@@ -45,7 +45,7 @@ func GetRoleList(c *gin.Context) {
 	response.ReturnDataWithTotal(c, int(total), roles)
 }
 
-func AddRole(c *gin.Context) {
+func (h *Handler) AddRole(c *gin.Context) {
 	params := &struct {
 		TenantID uint   `json:"tenant_id" binding:"required"`
 		Name     string `json:"name" form:"name" binding:"required"`
@@ -55,7 +55,7 @@ func AddRole(c *gin.Context) {
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	role, err := roledomain.AddRole(roledomain.AddInput{
+	role, err := h.roles.AddRole(c.Request.Context(), roledomain.AddInput{
 		TenantID: params.TenantID,
 		Name:     params.Name,
 		Status:   uint(params.Status),
@@ -68,7 +68,7 @@ func AddRole(c *gin.Context) {
 	response.ReturnData(c, role)
 }
 
-func UpdateRole(c *gin.Context) {
+func (h *Handler) UpdateRole(c *gin.Context) {
 	params := &struct {
 		ID       uint   `json:"id" form:"id" binding:"required"`
 		TenantID uint   `json:"tenant_id"`
@@ -79,13 +79,13 @@ func UpdateRole(c *gin.Context) {
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	role, err := roledomain.UpdateRole(roledomain.UpdateInput{
+	role, err := h.roles.UpdateRole(c.Request.Context(), roledomain.UpdateInput{
 		ID:       params.ID,
 		TenantID: params.TenantID,
 		Name:     params.Name,
 		Status:   uint(params.Status),
 		Desc:     params.Desc,
-	})
+	}, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
 	if err != nil {
 		ReturnDomainError(c, err, "更新角色失败")
 		return
@@ -93,19 +93,15 @@ func UpdateRole(c *gin.Context) {
 	response.ReturnData(c, role)
 }
 
-func DeleteRole(c *gin.Context) {
+func (h *Handler) DeleteRole(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	role, err := roledomain.GetRole(params.ID)
+	role, err := h.roles.DeleteRole(c.Request.Context(), params.ID, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
 	if err != nil {
-		ReturnDomainError(c, err, "角色不存在")
-		return
-	}
-	if err := roledomain.DeleteRole(params.ID); err != nil {
 		ReturnDomainError(c, err, "删除角色失败")
 		return
 	}
@@ -3,7 +3,10 @@ package admin
 import (
 	"github.com/gin-gonic/gin"
 
+	"api-server/api/app/v1/private/admin/platform/diagnostics"
+	platformIPAccess "api-server/api/app/v1/private/admin/platform/ipaccess"
 	platformMenu "api-server/api/app/v1/private/admin/platform/menu"
+	platformRateLimit "api-server/api/app/v1/private/admin/platform/ratelimit"
 	platformRole "api-server/api/app/v1/private/admin/platform/role"
 	"api-server/api/app/v1/private/admin/system/department"
 	"api-server/api/app/v1/private/admin/system/menu"
@@ -11,74 +14,96 @@ import (
 	"api-server/api/app/v1/private/admin/system/tenant"
 	"api-server/api/app/v1/private/admin/system/user"
 	"api-server/api/middleware"
+	admindomain "api-server/domain/admin"
+	"api-server/domain/ratelimit"
 )
 
 // RegisterRoutes 在 /api/v1/private/admin 下注册系统管理接口
-func RegisterRoutes(admin *gin.RouterGroup) {
+func RegisterRoutes(admin *gin.RouterGroup, services admindomain.Services) {
 	if admin == nil {
 		return
 	}
 
-	registerSystemRoutes(admin.Group("/system"))
-	registerPlatformRoutes(admin.Group("/platform"))
+	tenantHandler := tenant.NewHandler(services.Tenants)
+	registerSystemRoutes(admin.Group("/system"), services, tenantHandler)
+	registerPlatformRoutes(admin.Group("/platform"), services, tenantHandler)
 }
 
-func registerSystemRoutes(group *gin.RouterGroup) {
+func registerSystemRoutes(group *gin.RouterGroup, services admindomain.Services, tenantHandler *tenant.Handler) {
 	if group == nil {
 		return
 	}
 
-	group.GET("/user/login/captcha", middleware.LoginRateLimitMiddleware(), user.GetCaptcha)
-	group.POST("/user/login", middleware.LoginRateLimitMiddleware(), user.Login)
-	group.GET("/user/login/tenant", middleware.LoginRateLimitMiddleware(), user.SearchTenantCodeForLogin)
-	group.GET("/login/log", middleware.TokenVerify, user.FindLoginLogList)
-	group.GET("/user/info", middleware.TokenVerify, user.GetUserInfo)
-	group.PUT("/user/info", middleware.TokenVerify, user.UpdateUserInfo)
-	group.GET("/user/menu", middleware.TokenVerify, user.GetUserMenuList)
-	group.GET("/menu/role", middleware.TokenVerify, menu.GetMenuListByRoleID)
-	group.PUT("/menu/role", middleware.TokenVerify, menu.UpdateMenuListByRoleID)
-	group.GET("/department", middleware.TokenVerify, department.GetDepartmentList)
-	group.POST("/department", middleware.TokenVerify, department.AddDepartment)
-	group.PUT("/department", middleware.TokenVerify, department.UpdateDepartment)
-	group.DELETE("/department", middleware.TokenVerify, department.DeleteDepartment)
-	group.GET("/role", middleware.TokenVerify, role.GetRoleList)
-	group.POST("/role", middleware.TokenVerify, role.AddRole)
-	group.PUT("/role", middleware.TokenVerify, role.UpdateRole)
-	group.DELETE("/role", middleware.TokenVerify, role.DeleteRole)
-	group.GET("/user", middleware.TokenVerify, user.FindUser)
-	group.GET("/user/cache", middleware.TokenVerify, user.FindUserByCache)
-	group.POST("/user", middleware.TokenVerify, user.AddUser)
-	group.PUT("/user", middleware.TokenVerify, user.UpdateUser)
-	group.DELETE("/user", middleware.TokenVerify, user.DeleteUser)
-	group.GET("/tenant", middleware.TokenVerify, middleware.SuperAdminVerify, tenant.FindTenant)
-	group.POST("/tenant", middleware.TokenVerify, middleware.SuperAdminVerify, tenant.AddTenant)
-	group.PUT("/tenant", middleware.TokenVerify, middleware.SuperAdminVerify, tenant.UpdateTenant)
-	group.DELETE("/tenant", middleware.TokenVerify, middleware.SuperAdminVerify, tenant.DeleteTenant)
+	userHandler := user.NewHandler(services.Users, services.Menus)
+	menuHandler := menu.NewHandler(services.Menus)
+	departmentHandler := department.NewHandler(services.Departments)
+	roleHandler := role.NewHandler(services.Roles)
+	superAdmin := middleware.SuperAdminVerify(services.Users)
+
+	group.GET("/user/login/captcha", middleware.LoginRateLimitMiddleware(), userHandler.GetCaptcha)
+	group.POST("/user/login", middleware.LoginRateLimitMiddleware(), userHandler.Login)
+	group.GET("/user/login/tenant", middleware.LoginRateLimitMiddleware(), userHandler.SearchTenantCodeForLogin)
+
+	// 以下接口需登录，认证后校验 IP 访问控制名单并按租户/用户执行限流策略
+	authed := group.Group("", middleware.TokenVerify, middleware.IPAccess(), middleware.TenantRateLimit(ratelimit.GroupSystem))
+	authed.GET("/login/log", userHandler.FindLoginLogList)
+	authed.GET("/user/info", userHandler.GetUserInfo)
+	authed.PUT("/user/info", userHandler.UpdateUserInfo)
+	authed.GET("/user/menu", userHandler.GetUserMenuList)
+	authed.GET("/menu/role", menuHandler.GetMenuListByRoleID)
+	authed.PUT("/menu/role", menuHandler.UpdateMenuListByRoleID)
+	authed.GET("/department", departmentHandler.GetDepartmentList)
+	authed.POST("/department", departmentHandler.AddDepartment)
+	authed.PUT("/department", departmentHandler.UpdateDepartment)
+	authed.DELETE("/department", departmentHandler.DeleteDepartment)
+	authed.GET("/role", roleHandler.GetRoleList)
+	authed.POST("/role", roleHandler.AddRole)
+	authed.PUT("/role", roleHandler.UpdateRole)
+	authed.DELETE("/role", roleHandler.DeleteRole)
+	authed.GET("/user", userHandler.FindUser)
+	authed.GET("/user/cache", userHandler.FindUserByCache)
+	authed.POST("/user", userHandler.AddUser)
+	authed.PUT("/user", userHandler.UpdateUser)
+	authed.DELETE("/user", userHandler.DeleteUser)
+	authed.GET("/tenant", superAdmin, tenantHandler.FindTenant)
+	authed.POST("/tenant", superAdmin, tenantHandler.AddTenant)
+	authed.PUT("/tenant", superAdmin, tenantHandler.UpdateTenant)
+	authed.DELETE("/tenant", superAdmin, tenantHandler.DeleteTenant)
 }
 
-func registerPlatformRoutes(group *gin.RouterGroup) {
+func registerPlatformRoutes(group *gin.RouterGroup, services admindomain.Services, tenantHandler *tenant.Handler) {
 	if group == nil {
 		return
 	}
 
-	group.Use(middleware.TokenVerify, middleware.SuperAdminVerify)
-	group.GET("/menu", platformMenu.GetMenuList)
-	group.POST("/menu", platformMenu.AddMenu)
-	group.PUT("/menu", platformMenu.UpdateMenu)
-	group.DELETE("/menu", platformMenu.DeleteMenu)
-	group.GET("/menu/tenant", platformMenu.GetTenantMenu)
-	group.PUT("/menu/tenant", platformMenu.UpdateTenantMenu)
-	group.GET("/menu/auth", platformMenu.GetMenuAuthList)
-	group.POST("/menu/auth", platformMenu.AddMenuAuth)
-	group.PUT("/menu/auth", platformMenu.UpdateMenuAuth)
-	group.DELETE("/menu/auth", platformMenu.DeleteMenuAuth)
+	menuHandler := platformMenu.NewHandler(services.Menus)
+	roleHandler := platformRole.NewHandler(services.Roles)
+
+	// 平台管理接口在全局、租户名单之外还需通过更严格的平台名单
+	group.Use(middleware.TokenVerify, middleware.IPAccess(), middleware.PlatformIPAccess(), middleware.SuperAdminVerify(services.Users), middleware.TenantRateLimit(ratelimit.GroupPlatform))
+	group.GET("/menu", menuHandler.GetMenuList)
+	group.POST("/menu", menuHandler.AddMenu)
+	group.PUT("/menu", menuHandler.UpdateMenu)
+	group.DELETE("/menu", menuHandler.DeleteMenu)
+	group.GET("/menu/tenant", menuHandler.GetTenantMenu)
+	group.PUT("/menu/tenant", menuHandler.UpdateTenantMenu)
+	group.GET("/menu/auth", menuHandler.GetMenuAuthList)
+	group.POST("/menu/auth", menuHandler.AddMenuAuth)
+	group.PUT("/menu/auth", menuHandler.UpdateMenuAuth)
+	group.DELETE("/menu/auth", menuHandler.DeleteMenuAuth)
+
+	group.GET("/role", roleHandler.GetRoleList)
+	group.POST("/role", roleHandler.AddRole)
+	group.PUT("/role", roleHandler.UpdateRole)
+	group.DELETE("/role", roleHandler.DeleteRole)
+	group.GET("/tenant", tenantHandler.FindTenant)
+	group.POST("/tenant", tenantHandler.AddTenant)
+	group.PUT("/tenant", tenantHandler.UpdateTenant)
+	group.DELETE("/tenant", tenantHandler.DeleteTenant)
+	group.GET("/tenant/cors", tenantHandler.GetAllowedOrigins)
+	group.PUT("/tenant/cors", tenantHandler.UpdateAllowedOrigins)
 
-	group.GET("/role", platformRole.GetRoleList)
-	group.POST("/role", platformRole.AddRole)
-	group.PUT("/role", platformRole.UpdateRole)
-	group.DELETE("/role", platformRole.DeleteRole)
-	group.GET("/tenant", tenant.FindTenant)
-	group.POST("/tenant", tenant.AddTenant)
-	group.PUT("/tenant", tenant.UpdateTenant)
-	group.DELETE("/tenant", tenant.DeleteTenant)
+	platformRateLimit.RegisterRoutes(group.Group("/tenant/rate-limit"))
+	platformIPAccess.RegisterRoutes(group.Group("/ip-access"))
+	diagnostics.RegisterRoutes(group.Group("/system/runtime"))
 }
@@ -8,7 +8,7 @@ import (
 	departmentdomain "api-server/domain/admin/department"
 )
 
-func AddDepartment(c *gin.Context) {
+func (h *Handler) AddDepartment(c *gin.Context) {
 	params := &struct {
 		Name   string `json:"name" form:"name" binding:"required"`
 		Status int    `json:"status" form:"status" binding:"required"`
@@ -17,7 +17,7 @@ func AddDepartment(c *gin.Context) {
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	department, err := departmentdomain.AddDepartment(departmentdomain.AddInput{
+	department, err := h.departments.AddDepartment(c.Request.Context(), departmentdomain.AddInput{
 		Name:   params.Name,
 		Status: uint(params.Status),
 		Sort:   uint(params.Sort),
@@ -29,7 +29,7 @@ func AddDepartment(c *gin.Context) {
 	response.ReturnData(c, department)
 }
 
-func UpdateDepartment(c *gin.Context) {
+func (h *Handler) UpdateDepartment(c *gin.Context) {
 	params := &struct {
 		ID     uint   `json:"id" form:"id" binding:"required"`
 		Name   string `json:"name" form:"name" binding:"required"`
@@ -39,7 +39,7 @@ func UpdateDepartment(c *gin.Context) {
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	department, err := departmentdomain.UpdateDepartment(departmentdomain.UpdateInput{
+	department, err := h.departments.UpdateDepartment(c.Request.Context(), departmentdomain.UpdateInput{
 		ID:     params.ID,
 		Name:   params.Name,
 		Status: uint(params.Status),
@@ -52,7 +52,7 @@ func UpdateDepartment(c *gin.Context) {
 	response.ReturnData(c, department)
 }
 
-func GetDepartmentList(c *gin.Context) {
+func (h *Handler) GetDepartmentList(c *gin.Context) {
 	params := &struct {
 		Name   string `json:"name" form:"name"`
 		Status uint   `json:"status" form:"status"`
@@ -66,7 +66,7 @@ func GetDepartmentList(c *gin.Context) {
 	pageSize := middleware.GetPageSize(c)
 
 	// 调用带分页的查询函数
-	departments, total, err := departmentdomain.FindDepartmentList(departmentdomain.FindListQuery{
+	departments, total, err := h.departments.FindDepartmentList(c.Request.Context(), departmentdomain.FindListQuery{
 		Name:   params.Name,
 		Status: params.Status,
 	}, page, pageSize)
@@ -79,14 +79,14 @@ func GetDepartmentList(c *gin.Context) {
 	response.ReturnDataWithTotal(c, int(total), departments)
 }
 
-func DeleteDepartment(c *gin.Context) {
+func (h *Handler) DeleteDepartment(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	department, err := departmentdomain.DeleteDepartment(params.ID)
+	department, err := h.departments.DeleteDepartment(c.Request.Context(), params.ID)
 	if err != nil {
 		ReturnDomainError(c, err, "删除部门失败")
 		return
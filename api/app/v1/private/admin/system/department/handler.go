@@ -0,0 +1,16 @@
+package department
+
+import (
+	departmentdomain "api-server/domain/admin/department"
+)
+
+// Handler 部门管理接口，依赖的领域服务在启动时注入
+type Handler struct {
+	departments *departmentdomain.Service
+}
+
+func NewHandler(departments *departmentdomain.Service) *Handler {
+	return &Handler{
+		departments: departments,
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // GetMenuListByRoleID 根据角色ID获取菜单列表
-func GetMenuListByRoleID(c *gin.Context) {
+func (h *Handler) GetMenuListByRoleID(c *gin.Context) {
 	params := &struct {
 		RoleID uint `json:"role_id" form:"role_id" binding:"required"`
 	}{}
@@ -21,7 +21,7 @@ func GetMenuListByRoleID(c *gin.Context) {
 		return
 	}
 
-	menuTree, err := menudomain.GetRoleMenuTree(params.RoleID, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
+	menuTree, err := h.menus.GetRoleMenuTree(c.Request.Context(), params.RoleID, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
 	if err != nil {
 		if errors.Is(err, menudomain.ErrPermissionDenied) {
 			response.ReturnError(c, response.PERMISSION_DENIED, "无权查看该角色菜单")
@@ -33,7 +33,7 @@ func GetMenuListByRoleID(c *gin.Context) {
 	response.ReturnData(c, menuTree)
 }
 
-func UpdateMenuListByRoleID(c *gin.Context) {
+func (h *Handler) UpdateMenuListByRoleID(c *gin.Context) {
 	params := &struct {
 		RoleID   uint   `json:"role_id" form:"role_id" binding:"required"`
 		MenuData string `json:"menu_data" form:"menu_data" binding:"required"`
@@ -50,7 +50,7 @@ func UpdateMenuListByRoleID(c *gin.Context) {
 		return
 	}
 
-	if err := menudomain.UpdateRoleMenu(params.RoleID, menuData, middleware.GetTenantID(c), middleware.IsSuperAdmin(c)); err != nil {
+	if err := h.menus.UpdateRoleMenu(c.Request.Context(), params.RoleID, menuData, middleware.GetTenantID(c), middleware.IsSuperAdmin(c)); err != nil {
 		if errors.Is(err, menudomain.ErrPermissionDenied) {
 			response.ReturnError(c, response.PERMISSION_DENIED, "无权调整该角色菜单")
 			return
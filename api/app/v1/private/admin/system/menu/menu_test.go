@@ -25,7 +25,7 @@ func setupTestRouter() *gin.Engine {
 
 func TestGetMenuListByRoleID_MissingRoleID(t *testing.T) {
 	router := setupTestRouter()
-	router.GET("/menu", GetMenuListByRoleID)
+	router.GET("/menu", NewHandler(nil).GetMenuListByRoleID)
 
 	req, _ := http.NewRequest(http.MethodGet, "/menu", nil)
 	w := httptest.NewRecorder()
@@ -52,7 +52,7 @@ func TestGetMenuListByRoleID_MissingRoleID(t *testing.T) {
 
 func TestUpdateMenuListByRoleID_InvalidMenuData(t *testing.T) {
 	router := setupTestRouter()
-	router.POST("/menu", UpdateMenuListByRoleID)
+	router.POST("/menu", NewHandler(nil).UpdateMenuListByRoleID)
 
 	body := strings.NewReader(`{"role_id":1,"menu_data":"not-json"}`)
 	req, _ := http.NewRequest(http.MethodPost, "/menu", body)
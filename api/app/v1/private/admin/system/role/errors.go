@@ -18,6 +18,8 @@ func ReturnDomainError(c *gin.Context, err error, fallback string) {
 	switch {
 	case errors.Is(err, roledomain.ErrRoleNotFound):
 		response.ReturnError(c, response.DATA_LOSS, "角色不存在")
+	case errors.Is(err, roledomain.ErrPermissionDenied):
+		response.ReturnError(c, response.PERMISSION_DENIED, "无权操作该角色")
 	default:
 		response.ReturnError(c, response.DATA_LOSS, fallback)
 	}
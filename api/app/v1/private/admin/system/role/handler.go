@@ -0,0 +1,16 @@
+package role
+
+import (
+	roledomain "api-server/domain/admin/role"
+)
+
+// Handler 租户角色管理接口，依赖的领域服务在启动时注入
+type Handler struct {
+	roles *roledomain.Service
+}
+
+func NewHandler(roles *roledomain.Service) *Handler {
+	return &Handler{
+		roles: roles,
+	}
+}
@@ -10,7 +10,7 @@ import (
 	roledomain "api-server/domain/admin/role"
 )
 
-func GetRoleList(c *gin.Context) {
+func (h *Handler) GetRoleList(c *gin.Context) {
 	params := &struct {
 		Name   string `json:"name" form:"name"`
 		Status uint   `json:"status" form:"status"`
@@ -32,7 +32,7 @@ func GetRoleList(c *gin.Context) {
 	targetTenantID := currentTenantID
 
 	// 调用带分页的查询函数
-	roles, total, err := roledomain.FindRoleList(roledomain.FindListQuery{
+	roles, total, err := h.roles.FindRoleList(c.Request.Context(), roledomain.FindListQuery{
 		TenantID: targetTenantID,
 		Name:     params.Name,
 		Status:   params.Status,
@@ -46,7 +46,7 @@ func GetRoleList(c *gin.Context) {
 	response.ReturnDataWithTotal(c, int(total), roles)
 }
 
-func AddRole(c *gin.Context) {
+func (h *Handler) AddRole(c *gin.Context) {
 	params := &struct {
 		Name   string `json:"name" form:"name" binding:"required"`
 		Status int    `json:"status" form:"status" binding:"required"`
@@ -78,7 +78,7 @@ func AddRole(c *gin.Context) {
 		targetID = uint(idValue)
 	}
 
-	role, err := roledomain.AddRole(roledomain.AddInput{
+	role, err := h.roles.AddRole(c.Request.Context(), roledomain.AddInput{
 		TenantID: targetID,
 		Name:     params.Name,
 		Status:   uint(params.Status),
@@ -91,7 +91,7 @@ func AddRole(c *gin.Context) {
 	response.ReturnData(c, role)
 }
 
-func UpdateRole(c *gin.Context) {
+func (h *Handler) UpdateRole(c *gin.Context) {
 	params := &struct {
 		ID       uint   `json:"id" form:"id" binding:"required"`
 		TenantID uint   `json:"tenant_id"`
@@ -102,63 +102,30 @@ func UpdateRole(c *gin.Context) {
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	isSuperAdmin := middleware.IsSuperAdmin(c)
-	currentTenantID := middleware.GetTenantID(c)
-
-	originalRole, err := roledomain.GetRole(params.ID)
-	if err != nil {
-		ReturnDomainError(c, err, "角色不存在")
-		return
-	}
-
-	targetTenantID := originalRole.TenantID
-	if isSuperAdmin {
-		if params.TenantID != 0 && params.TenantID != originalRole.TenantID {
-			targetTenantID = params.TenantID
-		}
-	} else {
-		if currentTenantID == 0 || originalRole.TenantID != currentTenantID {
-			response.ReturnError(c, response.PERMISSION_DENIED, "无权操作该角色")
-			return
-		}
-		// 非超级管理员不允许调整角色归属租户
-		targetTenantID = originalRole.TenantID
-	}
-
-	updatedRole, err := roledomain.UpdateRole(roledomain.UpdateInput{
+	// 非超级管理员只能修改本租户角色，且不允许调整角色归属租户
+	updatedRole, err := h.roles.UpdateRole(c.Request.Context(), roledomain.UpdateInput{
 		ID:       params.ID,
-		TenantID: targetTenantID,
+		TenantID: params.TenantID,
 		Name:     params.Name,
 		Status:   uint(params.Status),
 		Desc:     params.Desc,
-	})
+	}, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
 	if err != nil {
-		response.ReturnError(c, response.DATA_LOSS, "更新角色失败")
+		ReturnDomainError(c, err, "更新角色失败")
 		return
 	}
 	response.ReturnData(c, updatedRole)
 }
 
-func DeleteRole(c *gin.Context) {
+func (h *Handler) DeleteRole(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	roleEntity, err := roledomain.GetRole(params.ID)
+	roleEntity, err := h.roles.DeleteRole(c.Request.Context(), params.ID, middleware.GetTenantID(c), middleware.IsSuperAdmin(c))
 	if err != nil {
-		ReturnDomainError(c, err, "角色不存在")
-		return
-	}
-	if !middleware.IsSuperAdmin(c) {
-		tenantID := middleware.GetTenantID(c)
-		if tenantID == 0 || roleEntity.TenantID != tenantID {
-			response.ReturnError(c, response.PERMISSION_DENIED, "无权删除该角色")
-			return
-		}
-	}
-	if err := roledomain.DeleteRole(params.ID); err != nil {
 		ReturnDomainError(c, err, "删除角色失败")
 		return
 	}
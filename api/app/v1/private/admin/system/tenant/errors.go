@@ -18,6 +18,8 @@ func ReturnDomainError(c *gin.Context, err error, fallback string) {
 	switch {
 	case errors.Is(err, tenantdomain.ErrTenantNotFound):
 		response.ReturnError(c, response.DATA_LOSS, "租户不存在")
+	case errors.Is(err, tenantdomain.ErrInvalidOrigin):
+		response.ReturnError(c, response.INVALID_ARGUMENT, "跨域来源格式无效，应形如 https://app.example.com 或 https://*.example.com")
 	default:
 		response.ReturnError(c, response.DATA_LOSS, fallback)
 	}
@@ -0,0 +1,16 @@
+package tenant
+
+import (
+	tenantdomain "api-server/domain/admin/tenant"
+)
+
+// Handler 租户管理接口，依赖的领域服务在启动时注入
+type Handler struct {
+	tenants *tenantdomain.Service
+}
+
+func NewHandler(tenants *tenantdomain.Service) *Handler {
+	return &Handler{
+		tenants: tenants,
+	}
+}
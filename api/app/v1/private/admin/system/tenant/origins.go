@@ -0,0 +1,44 @@
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"api-server/api/middleware"
+	"api-server/api/response"
+)
+
+// GetAllowedOrigins 查询租户前端允许跨域的来源
+// GET /api/v1/private/admin/platform/tenant/cors
+func (h *Handler) GetAllowedOrigins(c *gin.Context) {
+	params := &struct {
+		TenantID uint `json:"tenant_id" form:"tenant_id" binding:"required"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	origins, err := h.tenants.GetAllowedOrigins(c.Request.Context(), params.TenantID)
+	if err != nil {
+		ReturnDomainError(c, err, "查询租户跨域来源失败")
+		return
+	}
+	response.ReturnData(c, gin.H{"tenant_id": params.TenantID, "allowed_origins": origins})
+}
+
+// UpdateAllowedOrigins 覆盖租户前端允许跨域的来源，传入空数组表示清空
+// PUT /api/v1/private/admin/platform/tenant/cors
+func (h *Handler) UpdateAllowedOrigins(c *gin.Context) {
+	params := &struct {
+		TenantID       uint     `json:"tenant_id" form:"tenant_id" binding:"required"`
+		AllowedOrigins []string `json:"allowed_origins" form:"allowed_origins"`
+	}{}
+	if !middleware.CheckParam(params, c) {
+		return
+	}
+
+	if err := h.tenants.SetAllowedOrigins(c.Request.Context(), params.TenantID, params.AllowedOrigins); err != nil {
+		ReturnDomainError(c, err, "更新租户跨域来源失败")
+		return
+	}
+	response.ReturnData(c, nil)
+}
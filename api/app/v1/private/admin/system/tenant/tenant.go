@@ -9,7 +9,7 @@ import (
 )
 
 // FindTenant 查询租户列表
-func FindTenant(c *gin.Context) {
+func (h *Handler) FindTenant(c *gin.Context) {
 	params := &struct {
 		Code   string `json:"code" form:"code"`
 		Name   string `json:"name" form:"name"`
@@ -22,7 +22,7 @@ func FindTenant(c *gin.Context) {
 	page := middleware.GetPage(c)
 	pageSize := middleware.GetPageSize(c)
 
-	tenants, total, err := tenantdomain.FindTenantList(tenantdomain.FindListQuery{
+	tenants, total, err := h.tenants.FindTenantList(c.Request.Context(), tenantdomain.FindListQuery{
 		Code:   params.Code,
 		Name:   params.Name,
 		Status: params.Status,
@@ -36,7 +36,7 @@ func FindTenant(c *gin.Context) {
 }
 
 // AddTenant 添加租户
-func AddTenant(c *gin.Context) {
+func (h *Handler) AddTenant(c *gin.Context) {
 	params := &struct {
 		Code    string `json:"code" form:"code" binding:"required"`
 		Name    string `json:"name" form:"name" binding:"required"`
@@ -49,7 +49,7 @@ func AddTenant(c *gin.Context) {
 		return
 	}
 
-	_, err := tenantdomain.AddTenant(tenantdomain.AddTenantInput{
+	_, err := h.tenants.AddTenant(c.Request.Context(), tenantdomain.AddTenantInput{
 		Code:    params.Code,
 		Name:    params.Name,
 		Contact: params.Contact,
@@ -66,7 +66,7 @@ func AddTenant(c *gin.Context) {
 }
 
 // UpdateTenant 更新租户
-func UpdateTenant(c *gin.Context) {
+func (h *Handler) UpdateTenant(c *gin.Context) {
 	params := &struct {
 		ID      uint   `json:"id" form:"id" binding:"required"`
 		Code    string `json:"code" form:"code" binding:"required"`
@@ -80,7 +80,7 @@ func UpdateTenant(c *gin.Context) {
 		return
 	}
 
-	if err := tenantdomain.UpdateTenant(tenantdomain.UpdateTenantInput{
+	if err := h.tenants.UpdateTenant(c.Request.Context(), tenantdomain.UpdateTenantInput{
 		ID:      params.ID,
 		Code:    params.Code,
 		Name:    params.Name,
@@ -97,7 +97,7 @@ func UpdateTenant(c *gin.Context) {
 }
 
 // DeleteTenant 删除租户
-func DeleteTenant(c *gin.Context) {
+func (h *Handler) DeleteTenant(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
@@ -105,7 +105,7 @@ func DeleteTenant(c *gin.Context) {
 		return
 	}
 
-	if err := tenantdomain.DeleteTenant(params.ID); err != nil {
+	if err := h.tenants.DeleteTenant(c.Request.Context(), params.ID); err != nil {
 		ReturnDomainError(c, err, "删除租户失败")
 		return
 	}
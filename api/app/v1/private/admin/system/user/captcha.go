@@ -9,7 +9,7 @@ import (
 	userdomain "api-server/domain/admin/user"
 )
 
-func GetCaptcha(c *gin.Context) {
+func (h *Handler) GetCaptcha(c *gin.Context) {
 	params := &struct {
 		Width  int `json:"width" form:"width" binding:"required"`
 		Height int `json:"height" form:"height" binding:"required"`
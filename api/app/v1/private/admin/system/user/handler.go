@@ -0,0 +1,19 @@
+package user
+
+import (
+	menudomain "api-server/domain/admin/menu"
+	userdomain "api-server/domain/admin/user"
+)
+
+// Handler 登录、用户与个人信息接口，依赖的领域服务在启动时注入
+type Handler struct {
+	users *userdomain.Service
+	menus *menudomain.Service
+}
+
+func NewHandler(users *userdomain.Service, menus *menudomain.Service) *Handler {
+	return &Handler{
+		users: users,
+		menus: menus,
+	}
+}
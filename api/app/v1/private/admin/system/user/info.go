@@ -8,7 +8,7 @@ import (
 	userdomain "api-server/domain/admin/user"
 )
 
-func UpdateUserInfo(c *gin.Context) {
+func (h *Handler) UpdateUserInfo(c *gin.Context) {
 	params := &struct {
 		Password string `json:"password" form:"password"`
 		Username string `json:"username" form:"username" binding:"required"`
@@ -23,7 +23,7 @@ func UpdateUserInfo(c *gin.Context) {
 		response.ReturnError(c, response.UNAUTHENTICATED, "未携带 token")
 		return
 	}
-	if err := userdomain.UpdateUserProfile(userdomain.UpdateProfileInput{
+	if err := h.users.UpdateUserProfile(c.Request.Context(), userdomain.UpdateProfileInput{
 		UserID:   userID,
 		Password: params.Password,
 		Username: params.Username,
@@ -36,13 +36,13 @@ func UpdateUserInfo(c *gin.Context) {
 	response.ReturnData(c, "更新用户成功")
 }
 
-func GetUserInfo(c *gin.Context) {
+func (h *Handler) GetUserInfo(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
 	if userID == 0 {
 		response.ReturnError(c, response.UNAUTHENTICATED, "未携带 token")
 		return
 	}
-	user, err := userdomain.GetUserProfile(userID)
+	user, err := h.users.GetUserProfile(c.Request.Context(), userID)
 	if err != nil {
 		ReturnDomainError(c, err, "查询用户失败")
 		return
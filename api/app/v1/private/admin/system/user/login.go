@@ -12,7 +12,7 @@ import (
 	userdomain "api-server/domain/admin/user"
 )
 
-func Login(c *gin.Context) {
+func (h *Handler) Login(c *gin.Context) {
 	params := &struct {
 		TenantCode string `json:"tenant_code" form:"tenant_code" binding:"required"` // 企业编号
 		Account    string `json:"account" form:"account" binding:"required"`         // 登录账号
@@ -33,14 +33,14 @@ func Login(c *gin.Context) {
 	clientIP := c.ClientIP()
 
 	// 查询用户（多租户验证）
-	user, tenant, err := userdomain.VerifyLogin(userdomain.LoginInput{
+	user, tenant, err := h.users.VerifyLogin(c.Request.Context(), userdomain.LoginInput{
 		TenantCode: params.TenantCode,
 		Account:    params.Account,
 		Password:   params.Password,
 	})
 
 	if err != nil {
-		_ = userdomain.CreateLoginLogFromInput(userdomain.LoginLogInput{
+		_ = h.users.CreateLoginLogFromInput(c.Request.Context(), userdomain.LoginLogInput{
 			TenantCode:  params.TenantCode,
 			UserName:    params.Account,
 			IP:          clientIP,
@@ -60,7 +60,7 @@ func Login(c *gin.Context) {
 	}
 
 	// 记录登录成功日志
-	_ = userdomain.CreateLoginLogFromInput(userdomain.LoginLogInput{
+	_ = h.users.CreateLoginLogFromInput(c.Request.Context(), userdomain.LoginLogInput{
 		TenantCode:  params.TenantCode,
 		UserName:    params.Account,
 		IP:          clientIP,
@@ -89,7 +89,7 @@ func Login(c *gin.Context) {
 	})
 }
 
-func FindLoginLogList(c *gin.Context) {
+func (h *Handler) FindLoginLogList(c *gin.Context) {
 	params := &struct {
 		IP       string `json:"ip" form:"ip"`
 		Username string `json:"username" form:"username"`
@@ -100,7 +100,7 @@ func FindLoginLogList(c *gin.Context) {
 	// 获取分页参数
 	page := middleware.GetPage(c)
 	pageSize := middleware.GetPageSize(c)
-	logs, total, err := userdomain.FindLoginLogList(userdomain.FindLoginLogQuery{
+	logs, total, err := h.users.FindLoginLogList(c.Request.Context(), userdomain.FindLoginLogQuery{
 		IP:       params.IP,
 		Username: params.Username,
 	}, page, pageSize)
@@ -1,14 +1,16 @@
 package user
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 
 	"api-server/api/middleware"
 	"api-server/api/response"
-	menudomain "api-server/domain/admin/menu"
 )
 
-func GetUserMenuList(c *gin.Context) {
+func (h *Handler) GetUserMenuList(c *gin.Context) {
 	// 获取用户ID
 	userID := middleware.GetCurrentUserID(c)
 	if userID == 0 {
@@ -25,10 +27,36 @@ func GetUserMenuList(c *gin.Context) {
 	}
 
 	// 构建带权限标记的菜单树
-	menuTree, err := menudomain.GetUserMenuTree(userID, tenantID)
+	menuTree, err := h.menus.GetUserMenuTree(c.Request.Context(), userID, tenantID)
 	if err != nil {
 		response.ReturnError(c, response.DATA_LOSS, "查询用户菜单失败")
 		return
 	}
-	response.ReturnData(c, menuTree)
+
+	// 菜单树未变化时返回 304，客户端沿用本地缓存
+	if menuTree.ETag != "" {
+		c.Header("ETag", menuTree.ETag)
+		c.Header("Cache-Control", "private, no-cache")
+		if etagMatches(c.GetHeader("If-None-Match"), menuTree.ETag) {
+			c.Set(response.CodeKey, response.Success.Code)
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+	}
+	response.ReturnData(c, menuTree.Tree)
+}
+
+// etagMatches 按 If-None-Match 的弱比较规则判断是否命中（支持逗号分隔的多个值与 *）
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
 }
@@ -24,7 +24,7 @@ func setupTestRouter() *gin.Engine {
 
 func TestGetUserMenuList_MissingToken(t *testing.T) {
 	router := setupTestRouter()
-	router.GET("/menu", GetUserMenuList)
+	router.GET("/menu", NewHandler(nil, nil).GetUserMenuList)
 
 	req, _ := http.NewRequest(http.MethodGet, "/menu", nil)
 	w := httptest.NewRecorder()
@@ -55,7 +55,7 @@ func TestGetUserMenuList_MissingTenant(t *testing.T) {
 		c.Set("user_id", uint(2))
 		c.Next()
 	})
-	router.GET("/menu", GetUserMenuList)
+	router.GET("/menu", NewHandler(nil, nil).GetUserMenuList)
 
 	req, _ := http.NewRequest(http.MethodGet, "/menu", nil)
 	w := httptest.NewRecorder()
@@ -80,3 +80,23 @@ func TestGetUserMenuList_MissingTenant(t *testing.T) {
 	}
 }
 
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc123"`
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc123"`, true},
+		{`W/"abc123"`, true},
+		{`"other", "abc123"`, true},
+		{`"other"`, false},
+		{"*", true},
+	}
+	for _, tc := range cases {
+		if got := etagMatches(tc.header, etag); got != tc.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
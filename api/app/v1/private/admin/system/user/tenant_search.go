@@ -12,7 +12,7 @@ import (
 )
 
 // SearchTenantCodeForLogin 登录页用：根据输入模糊查询租户编码，返回最多10条
-func SearchTenantCodeForLogin(c *gin.Context) {
+func (h *Handler) SearchTenantCodeForLogin(c *gin.Context) {
 	params := &struct {
 		Code string `json:"code" form:"code" binding:"required"`
 	}{}
@@ -20,7 +20,7 @@ func SearchTenantCodeForLogin(c *gin.Context) {
 		return
 	}
 
-	result, err := userdomain.SuggestTenantForLogin(params.Code, 10)
+	result, err := h.users.SuggestTenantForLogin(c.Request.Context(), params.Code, 10)
 	if err != nil {
 		if errors.Is(err, userdomain.ErrTenantQueryTooShort) {
 			response.ReturnError(c, response.INVALID_ARGUMENT, "输入长度过短")
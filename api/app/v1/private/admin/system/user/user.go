@@ -10,7 +10,7 @@ import (
 	userdomain "api-server/domain/admin/user"
 )
 
-func FindUserByCache(c *gin.Context) {
+func (h *Handler) FindUserByCache(c *gin.Context) {
 	params := &struct {
 		Username string `json:"username" form:"username"` // 昵称
 		Name     string `json:"name" form:"name"`         // 姓名
@@ -20,10 +20,21 @@ func FindUserByCache(c *gin.Context) {
 		return
 	}
 
+	// 缓存按租户分区，只允许查询当前租户的用户
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == 0 {
+		response.ReturnError(c, response.UNAUTHENTICATED, "Invalid tenant context")
+		return
+	}
+
 	// 如果提供了ID，则获取单个用户信息
 	if params.ID > 0 {
-		userInfo, err := userdomain.GetUserFromCache(params.ID)
+		userInfo, err := userdomain.GetUserFromCache(c.Request.Context(), tenantID, params.ID)
 		if err != nil {
+			if errors.Is(err, userdomain.ErrUserNotFound) {
+				response.ReturnError(c, response.NOT_FOUND, "用户不存在")
+				return
+			}
 			response.ReturnError(c, response.DATA_LOSS, "获取用户缓存数据失败")
 			return
 		}
@@ -35,7 +46,7 @@ func FindUserByCache(c *gin.Context) {
 	page := middleware.GetPage(c)
 	pageSize := middleware.GetPageSize(c)
 
-	userList, total, err := userdomain.ListUsersFromCache(userdomain.CacheFilter{
+	userList, total, err := userdomain.ListUsersFromCache(c.Request.Context(), tenantID, userdomain.CacheFilter{
 		Username: params.Username,
 		Name:     params.Name,
 	}, page, pageSize)
@@ -46,7 +57,7 @@ func FindUserByCache(c *gin.Context) {
 	response.ReturnDataWithTotal(c, total, userList)
 }
 
-func FindUser(c *gin.Context) {
+func (h *Handler) FindUser(c *gin.Context) {
 	params := &struct {
 		Username     string `json:"username" form:"username"` // 昵称
 		Name         string `json:"name" form:"name"`         // 姓名
@@ -67,7 +78,7 @@ func FindUser(c *gin.Context) {
 
 	page := middleware.GetPage(c)
 	pageSize := middleware.GetPageSize(c)
-	usersWithRelations, total, err := userdomain.FindUserList(tenantID, userdomain.FindUserQuery{
+	usersWithRelations, total, err := h.users.FindUserList(c.Request.Context(), tenantID, userdomain.FindUserQuery{
 		Username:     params.Username,
 		Name:         params.Name,
 		Phone:        params.Phone,
@@ -121,7 +132,7 @@ func FindUser(c *gin.Context) {
 	response.ReturnDataWithTotal(c, int(total), items)
 }
 
-func AddUser(c *gin.Context) {
+func (h *Handler) AddUser(c *gin.Context) {
 	params := &struct {
 		Name         string `json:"name" form:"name" binding:"required"`         // 姓名
 		Username     string `json:"username" form:"username" binding:"required"` // 昵称
@@ -143,7 +154,7 @@ func AddUser(c *gin.Context) {
 		response.ReturnError(c, response.UNAUTHENTICATED, "Invalid tenant context")
 		return
 	}
-	if err := userdomain.AddUser(tenantID, userdomain.AddUserInput{
+	if err := h.users.AddUser(c.Request.Context(), tenantID, userdomain.AddUserInput{
 		Name:         params.Name,
 		Username:     params.Username,
 		Account:      params.Account,
@@ -164,7 +175,7 @@ func AddUser(c *gin.Context) {
 	response.ReturnData(c, nil)
 }
 
-func UpdateUser(c *gin.Context) {
+func (h *Handler) UpdateUser(c *gin.Context) {
 	params := &struct {
 		ID           uint   `json:"id" form:"id" binding:"required"`
 		Name         string `json:"name" form:"name" binding:"required"`         // 姓名
@@ -187,7 +198,7 @@ func UpdateUser(c *gin.Context) {
 		response.ReturnError(c, response.UNAUTHENTICATED, "Invalid tenant context")
 		return
 	}
-	if err := userdomain.UpdateUser(tenantID, userdomain.UpdateUserInput{
+	if err := h.users.UpdateUser(c.Request.Context(), tenantID, userdomain.UpdateUserInput{
 		ID:           params.ID,
 		Name:         params.Name,
 		Username:     params.Username,
@@ -199,6 +210,10 @@ func UpdateUser(c *gin.Context) {
 		RoleID:       params.RoleID,
 		DepartmentID: params.DepartmentID,
 	}); err != nil {
+		if errors.Is(err, userdomain.ErrUserNotFound) {
+			response.ReturnError(c, response.NOT_FOUND, "用户不存在")
+			return
+		}
 		if errors.Is(err, userdomain.ErrRoleNotInTenant) {
 			response.ReturnError(c, response.PERMISSION_DENIED, "角色不存在或不属于当前租户")
 			return
@@ -209,14 +224,23 @@ func UpdateUser(c *gin.Context) {
 	response.ReturnData(c, nil)
 }
 
-func DeleteUser(c *gin.Context) {
+func (h *Handler) DeleteUser(c *gin.Context) {
 	params := &struct {
 		ID uint `json:"id" form:"id" binding:"required"`
 	}{}
 	if !middleware.CheckParam(params, c) {
 		return
 	}
-	if err := userdomain.DeleteUser(params.ID); err != nil {
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == 0 {
+		response.ReturnError(c, response.UNAUTHENTICATED, "Invalid tenant context")
+		return
+	}
+	if err := h.users.DeleteUser(c.Request.Context(), tenantID, params.ID); err != nil {
+		if errors.Is(err, userdomain.ErrUserNotFound) {
+			response.ReturnError(c, response.NOT_FOUND, "用户不存在")
+			return
+		}
 		if errors.Is(err, userdomain.ErrCannotDeleteSuperAdmin) {
 			response.ReturnError(c, response.DATA_LOSS, "不能删除超级管理员")
 			return
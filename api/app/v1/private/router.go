@@ -4,14 +4,15 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"api-server/api/app/v1/private/admin"
+	admindomain "api-server/domain/admin"
 )
 
 // RegisterRoutes 将所有内部业务接口挂载到 /api/v1/private
-func RegisterRoutes(private *gin.RouterGroup) {
+func RegisterRoutes(private *gin.RouterGroup, services admindomain.Services) {
 	if private == nil {
 		return
 	}
 
 	adminGroup := private.Group("/admin")
-	admin.RegisterRoutes(adminGroup)
+	admin.RegisterRoutes(adminGroup, services)
 }
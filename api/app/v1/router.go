@@ -5,10 +5,11 @@ import (
 
 	"api-server/api/app/v1/open"
 	"api-server/api/app/v1/private"
+	admindomain "api-server/domain/admin"
 )
 
 // RegisterRoutes 在 /api/v1 下注册 open/private 等分组
-func RegisterRoutes(v1 *gin.RouterGroup) {
+func RegisterRoutes(v1 *gin.RouterGroup, services admindomain.Services) {
 	if v1 == nil {
 		return
 	}
@@ -17,5 +18,5 @@ func RegisterRoutes(v1 *gin.RouterGroup) {
 	open.RegisterRoutes(openGroup)
 
 	privateGroup := v1.Group("/private")
-	private.RegisterRoutes(privateGroup)
+	private.RegisterRoutes(privateGroup, services)
 }
@@ -22,7 +22,7 @@ type MultiTenantClaims struct {
 // JWTIssue 签发多租户JWT token
 func JWTIssue(userID, tenantID uint, account string) (string, error) {
 	nt := time.Now()
-	exp := nt.Add(config.JWTExpiration)
+	exp := nt.Add(config.Current().JWTExpiration)
 	claims := MultiTenantClaims{
 		UserID:   userID,
 		TenantID: tenantID,
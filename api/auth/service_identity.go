@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/x509"
+	"strings"
+
+	"api-server/config"
+)
+
+// MatchServiceIdentity 按证书主题或 SAN 查找服务身份，证书须已通过 CA 校验。
+// 主题规则为完整 DN（按 RFC 2253 顺序，如 CN=ci-bot,OU=Automation,O=Example）或仅 CN（如 CN=ci-bot），
+// SAN 规则与证书中的 DNS、URI、邮箱、IP 任一取值相同即命中；比较均忽略大小写，按配置顺序取第一个命中的身份。
+func MatchServiceIdentity(cert *x509.Certificate, identities []config.ServiceIdentity) (config.ServiceIdentity, bool) {
+	if cert == nil {
+		return config.ServiceIdentity{}, false
+	}
+	subject := cert.Subject.String()
+	sans := certSANs(cert)
+	for _, id := range identities {
+		for _, s := range id.Subjects {
+			s = strings.TrimSpace(s)
+			if strings.EqualFold(s, subject) ||
+				(!strings.Contains(s, ",") && cert.Subject.CommonName != "" && strings.EqualFold(s, "CN="+cert.Subject.CommonName)) {
+				return id, true
+			}
+		}
+		for _, want := range id.SANs {
+			for _, got := range sans {
+				if strings.EqualFold(strings.TrimSpace(want), got) {
+					return id, true
+				}
+			}
+		}
+	}
+	return config.ServiceIdentity{}, false
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.URIs)+len(cert.EmailAddresses)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// ServiceAllowed 判断服务身份是否可访问指定接口，权限格式见 config.ServiceIdentity.Permissions
+func ServiceAllowed(id config.ServiceIdentity, method, path string) bool {
+	for _, p := range id.Permissions {
+		m, pattern, ok := strings.Cut(strings.TrimSpace(p), " ")
+		if !ok {
+			continue
+		}
+		if m != "*" && !strings.EqualFold(m, method) {
+			continue
+		}
+		pattern = strings.TrimSpace(pattern)
+		if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
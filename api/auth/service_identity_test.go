@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"api-server/config"
+)
+
+func TestMatchServiceIdentity(t *testing.T) {
+	identities := []config.ServiceIdentity{
+		{Name: "full-dn", Subjects: []string{"CN=deployer,OU=Automation,O=Example"}},
+		{Name: "cn-only", Subjects: []string{"cn=CI-Bot"}},
+		{Name: "by-ip", SANs: []string{"10.0.0.8"}},
+		{Name: "by-dns", SANs: []string{"Backup.Internal.Example.com"}},
+	}
+	tests := []struct {
+		cert *x509.Certificate
+		want string
+	}{
+		{&x509.Certificate{Subject: pkix.Name{CommonName: "deployer", OrganizationalUnit: []string{"Automation"}, Organization: []string{"Example"}}}, "full-dn"},
+		{&x509.Certificate{Subject: pkix.Name{CommonName: "deployer", Organization: []string{"Other"}}}, ""},
+		{&x509.Certificate{Subject: pkix.Name{CommonName: "ci-bot", Organization: []string{"Anything"}}}, "cn-only"},
+		{&x509.Certificate{IPAddresses: []net.IP{net.ParseIP("10.0.0.8")}}, "by-ip"},
+		{&x509.Certificate{DNSNames: []string{"backup.internal.example.com"}}, "by-dns"},
+		{&x509.Certificate{DNSNames: []string{"evil.example.com"}}, ""},
+	}
+	for _, tt := range tests {
+		id, ok := MatchServiceIdentity(tt.cert, identities)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("MatchServiceIdentity(%s) = %s, want no match", tt.cert.Subject, id.Name)
+			}
+			continue
+		}
+		if !ok || id.Name != tt.want {
+			t.Errorf("MatchServiceIdentity(%s) = %q, %v, want %q", tt.cert.Subject, id.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestServiceAllowed(t *testing.T) {
+	id := config.ServiceIdentity{Permissions: []string{
+		"GET /api/v1/private/admin/platform/tenant",
+		"* /api/v1/private/admin/platform/menu*",
+	}}
+	tests := []struct {
+		method, path string
+		want         bool
+	}{
+		{"GET", "/api/v1/private/admin/platform/tenant", true},
+		{"get", "/api/v1/private/admin/platform/tenant", true},
+		{"PUT", "/api/v1/private/admin/platform/tenant", false},
+		{"GET", "/api/v1/private/admin/platform/tenant/cors", false},
+		{"DELETE", "/api/v1/private/admin/platform/menu/auth", true},
+		{"POST", "/api/v1/private/admin/platform/role", false},
+	}
+	for _, tt := range tests {
+		if got := ServiceAllowed(id, tt.method, tt.path); got != tt.want {
+			t.Errorf("ServiceAllowed(%s %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
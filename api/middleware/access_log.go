@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"api-server/api/response"
+	"api-server/config"
+	httplog "api-server/util/log"
+)
+
+// AccessLogOptions 访问日志选项
+type AccessLogOptions struct {
+	Logger     *zap.Logger
+	SkipPaths  []string // 不记录的路径（匹配请求路径或路由模板），如健康检查
+	SampleRate float64  // 成功请求的采样比例（0~1），失败请求（HTTP 状态码 >= 400 或业务 code != 200）始终记录
+}
+
+// AccessLog 使用配置驱动的结构化访问日志，替代 gin.Logger；
+// access_log.enabled/skip_paths/sample_rate 在每次请求时读取，随配置热加载生效
+func AccessLog() gin.HandlerFunc {
+	return accessLog(httplog.Named(httplog.LoggerAccess), func() (bool, AccessLogOptions) {
+		rt := config.Current()
+		return rt.AccessLogEnabled, AccessLogOptions{
+			SkipPaths:  rt.AccessLogSkipPaths,
+			SampleRate: rt.AccessLogSampleRate,
+		}
+	})
+}
+
+// AccessLogWithOptions 按自定义选项创建结构化访问日志中间件
+func AccessLogWithOptions(opts AccessLogOptions) gin.HandlerFunc {
+	if opts.Logger == nil {
+		opts.Logger = httplog.Named(httplog.LoggerAccess)
+	}
+	return accessLog(opts.Logger, func() (bool, AccessLogOptions) { return true, opts })
+}
+
+// accessLog settings 在每次请求结束时调用，返回是否记录以及跳过路径、采样比例
+func accessLog(logger *zap.Logger, settings func() (bool, AccessLogOptions)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		enabled, opts := settings()
+		if !enabled {
+			return
+		}
+		path := c.Request.URL.Path
+		route := c.FullPath()
+		if slices.Contains(opts.SkipPaths, path) || (route != "" && slices.Contains(opts.SkipPaths, route)) {
+			return
+		}
+
+		status := c.Writer.Status()
+		code := c.GetInt(response.CodeKey)
+		failed := status >= http.StatusBadRequest || (code != 0 && code != response.Success.Code)
+		if !failed && opts.SampleRate < 1 && rand.Float64() >= opts.SampleRate {
+			return
+		}
+
+		if route == "" {
+			route = "unmatched"
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", path),
+			zap.Int("status", status),
+			zap.Int("code", code),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Uint("user_id", GetCurrentUserID(c)),
+			zap.Uint("tenant_id", GetTenantID(c)),
+			zap.String("trace_id", c.GetString(RequestIDKey)),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()))
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error("access", fields...)
+		case failed:
+			logger.Warn("access", fields...)
+		default:
+			logger.Info("access", fields...)
+		}
+	}
+}
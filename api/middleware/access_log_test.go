@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"api-server/api/response"
+)
+
+func newAccessLogRouter(opts AccessLogOptions) (*gin.Engine, *observer.ObservedLogs) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.DebugLevel)
+	opts.Logger = zap.New(core)
+
+	router := gin.New()
+	router.Use(AccessLogWithOptions(opts), RequestID())
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		c.Set("tenant_id", uint(3))
+		response.ReturnData(c, nil)
+	})
+	router.GET("/denied", func(c *gin.Context) {
+		response.ReturnError(c, response.PERMISSION_DENIED, "")
+	})
+	router.GET("/health", func(c *gin.Context) {
+		response.ReturnSuccess(c)
+	})
+	return router, logs
+}
+
+func TestAccessLog_Fields(t *testing.T) {
+	router, logs := newAccessLogRouter(AccessLogOptions{SampleRate: 1})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set(RequestIDKey, "req-42")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if logs.Len() != 1 {
+		t.Fatalf("entries = %d, want 1", logs.Len())
+	}
+	fields := logs.All()[0].ContextMap()
+	want := map[string]interface{}{
+		"method":     "GET",
+		"route":      "/users/:id",
+		"status":     int64(http.StatusOK),
+		"code":       int64(200),
+		"user_id":    uint64(7),
+		"tenant_id":  uint64(3),
+		"trace_id":   "req-42",
+		"user_agent": "test-agent",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("%s = %v (%T), want %v (%T)", key, fields[key], fields[key], value, value)
+		}
+	}
+}
+
+func TestAccessLog_SkipAndSample(t *testing.T) {
+	router, logs := newAccessLogRouter(AccessLogOptions{
+		SkipPaths:  []string{"/health"},
+		SampleRate: 0,
+	})
+
+	for _, path := range []string{"/health", "/users/1", "/denied"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 健康检查被跳过；成功请求因采样率为 0 被丢弃；业务失败的请求始终记录
+	if logs.Len() != 1 {
+		t.Fatalf("entries = %d, want 1", logs.Len())
+	}
+	entry := logs.All()[0]
+	if entry.Level != zap.WarnLevel {
+		t.Errorf("level = %s, want warn", entry.Level)
+	}
+	if entry.ContextMap()["code"] != int64(response.PERMISSION_DENIED.Code) {
+		t.Errorf("code = %v, want %d", entry.ContextMap()["code"], response.PERMISSION_DENIED.Code)
+	}
+}
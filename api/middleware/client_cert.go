@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/x509"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"api-server/api/auth"
+	"api-server/api/response"
+	"api-server/config"
+	"api-server/domain/admin/tenant"
+)
+
+// serviceIdentityKey 上下文中服务身份名称的键
+const serviceIdentityKey = "service_identity"
+
+// verifiedClientCert 返回已通过 CA 校验的客户端证书；未启用 mTLS 或未提供证书时返回 nil
+func verifiedClientCert(c *gin.Context) *x509.Certificate {
+	state := c.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// serviceIdentityVerify 以客户端证书认证机器客户端：证书主题或 SAN 须登记在 service_identities 中，
+// 且请求的接口在该身份的权限范围内。认证通过后写入租户与服务身份（user_id 为 0），供后续中间件使用。
+func serviceIdentityVerify(c *gin.Context, cert *x509.Certificate) {
+	id, ok := auth.MatchServiceIdentity(cert, config.Current().ServiceIdentities)
+	if !ok {
+		zap.L().Warn("客户端证书未登记为服务身份",
+			zap.String("subject", cert.Subject.String()),
+			zap.String("path", c.Request.URL.Path),
+		)
+		response.ReturnError(c, response.UNAUTHENTICATED, "客户端证书未登记为服务身份")
+		c.Abort()
+		return
+	}
+	if !auth.ServiceAllowed(id, c.Request.Method, c.Request.URL.Path) {
+		zap.L().Warn("服务身份无权访问接口",
+			zap.String("service", id.Name),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+		)
+		response.ReturnError(c, response.PERMISSION_DENIED, "服务身份无权访问该接口")
+		c.Abort()
+		return
+	}
+
+	c.Set("tenant_id", id.TenantID)
+	c.Set("user_id", uint(0))
+	c.Set("account", "service:"+id.Name)
+	c.Set(serviceIdentityKey, id.Name)
+	c.Next()
+}
+
+// GetServiceIdentity 从上下文获取服务身份名称；以 JWT 认证的请求返回空字符串
+func GetServiceIdentity(c *gin.Context) string {
+	name, exists := c.Get(serviceIdentityKey)
+	if !exists {
+		return ""
+	}
+	return name.(string)
+}
+
+// isPlatformService 当前请求是否来自平台租户的服务身份
+func isPlatformService(c *gin.Context) bool {
+	return GetServiceIdentity(c) != "" && GetTenantID(c) == tenant.PlatformTenantID
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/config"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/admin/user"
+)
+
+func withServiceIdentities(t *testing.T, identities []config.ServiceIdentity) {
+	t.Helper()
+	withRuntime(t, func(rt *config.Runtime) { rt.ServiceIdentities = identities })
+}
+
+func newServiceRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	platform := router.Group("/platform", TokenVerify, SuperAdminVerify(user.NewService(memory.New().Repos())))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": 200, "tenant_id": GetTenantID(c), "service": GetServiceIdentity(c), "super": IsSuperAdmin(c)})
+	}
+	platform.GET("/tenant", handler)
+	platform.DELETE("/tenant", handler)
+	return router
+}
+
+// doWithCert 模拟已通过 CA 校验的客户端证书请求，cert 为 nil 时不带证书
+func doWithCert(router *gin.Engine, method, path string, cert *x509.Certificate) map[string]interface{} {
+	req := httptest.NewRequest(method, path, nil)
+	req.TLS = &tls.ConnectionState{}
+	if cert != nil {
+		req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	body := map[string]interface{}{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return body
+}
+
+func TestServiceIdentityVerify(t *testing.T) {
+	withServiceIdentities(t, []config.ServiceIdentity{
+		{Name: "ci-bot", Subjects: []string{"CN=ci-bot"}, TenantID: 1, Permissions: []string{"GET /platform/*"}},
+		{Name: "backup", SANs: []string{"spiffe://example.com/backup"}, TenantID: 2, Permissions: []string{"* /platform/tenant"}},
+	})
+	router := newServiceRouter()
+
+	ciBot := &x509.Certificate{Subject: pkix.Name{CommonName: "ci-bot", Organization: []string{"Example"}}}
+	body := doWithCert(router, http.MethodGet, "/platform/tenant", ciBot)
+	if body["code"] != float64(200) || body["service"] != "ci-bot" || body["tenant_id"] != float64(1) || body["super"] != true {
+		t.Fatalf("ci-bot GET response = %v", body)
+	}
+	if body := doWithCert(router, http.MethodDelete, "/platform/tenant", ciBot); body["code"] != float64(403) {
+		t.Fatalf("ci-bot DELETE code = %v, want 403", body["code"])
+	}
+
+	// 非平台租户的服务身份不能通过超级管理员校验
+	spiffe, _ := url.Parse("spiffe://example.com/backup")
+	backup := &x509.Certificate{Subject: pkix.Name{CommonName: "backup-job"}, URIs: []*url.URL{spiffe}}
+	if body := doWithCert(router, http.MethodGet, "/platform/tenant", backup); body["code"] != float64(403) {
+		t.Fatalf("backup GET code = %v, want 403", body["code"])
+	}
+
+	unknown := &x509.Certificate{Subject: pkix.Name{CommonName: "someone"}}
+	if body := doWithCert(router, http.MethodGet, "/platform/tenant", unknown); body["code"] != float64(401) {
+		t.Fatalf("unknown cert code = %v, want 401", body["code"])
+	}
+	if body := doWithCert(router, http.MethodGet, "/platform/tenant", nil); body["code"] != float64(401) {
+		t.Fatalf("no cert code = %v, want 401", body["code"])
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/config"
+	corsdomain "api-server/domain/cors"
+	"api-server/util/origin"
+)
+
+// CORS 按 cors 配置段处理跨域请求，配置在每次请求时读取，随热加载生效。
+// 允许的来源为 cors.allow_origins 与各启用租户登记的来源（SystemTenant.AllowedOrigins）的并集；
+// 预检请求（OPTIONS + Access-Control-Request-Method）在此直接以 204 结束，不进入路由。
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestOrigin := c.GetHeader("Origin")
+		if requestOrigin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		rt := config.Current()
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowOrigin := corsAllowOrigin(rt, requestOrigin)
+		if allowOrigin == "" {
+			// 不被允许的来源不返回任何 CORS 响应头，由浏览器拦截；预检直接拒绝
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", allowOrigin)
+		if rt.CORSAllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.Header("Access-Control-Allow-Methods", strings.Join(rt.CORSAllowMethods, ", "))
+			if headers := corsAllowHeaders(rt, c.GetHeader("Access-Control-Request-Headers")); headers != "" {
+				c.Header("Access-Control-Allow-Headers", headers)
+			}
+			if maxAge := int(rt.CORSMaxAge.Seconds()); maxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(maxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if len(rt.CORSExposeHeaders) > 0 {
+			c.Header("Access-Control-Expose-Headers", strings.Join(rt.CORSExposeHeaders, ", "))
+		}
+		c.Next()
+	}
+}
+
+// corsAllowOrigin 返回应写入 Access-Control-Allow-Origin 的值，来源不被允许时返回空字符串。
+// 配置为 "*" 且不携带凭据时返回 "*"，其余情况回显请求来源
+func corsAllowOrigin(rt *config.Runtime, requestOrigin string) string {
+	patterns := rt.CORSAllowOrigins
+	if slices.Contains(patterns, origin.Any) && !rt.CORSAllowCredentials {
+		return origin.Any
+	}
+	if origin.MatchAny(patterns, requestOrigin) || corsdomain.TenantOriginAllowed(requestOrigin) {
+		return requestOrigin
+	}
+	return ""
+}
+
+// corsAllowHeaders 返回预检允许的请求头；配置包含 "*" 时回显浏览器请求的请求头
+func corsAllowHeaders(rt *config.Runtime, requested string) string {
+	if slices.Contains(rt.CORSAllowHeaders, "*") {
+		return requested
+	}
+	return strings.Join(rt.CORSAllowHeaders, ", ")
+}
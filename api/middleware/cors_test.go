@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/repo/memory"
+	corsdomain "api-server/domain/cors"
+)
+
+// withCORSConfig 设置 cors 配置并在测试结束后恢复；租户来源取自返回的内存仓储
+func withCORSConfig(t *testing.T, origins []string, credentials bool) repo.Repos {
+	t.Helper()
+	t.Cleanup(func() { corsdomain.Init(memory.New().Repos()) })
+	withRuntime(t, func(rt *config.Runtime) {
+		rt.CORSAllowOrigins = origins
+		rt.CORSAllowMethods = []string{"GET", "POST", "PUT"}
+		rt.CORSAllowHeaders = []string{"Authorization", "Content-Type"}
+		rt.CORSExposeHeaders = []string{"X-Request-ID"}
+		rt.CORSAllowCredentials = credentials
+		rt.CORSMaxAge = time.Hour
+	})
+	repos := memory.New().Repos()
+	corsdomain.Init(repos)
+	if err := corsdomain.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return repos
+}
+
+func newCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS())
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return router
+}
+
+func doCORS(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "/ping", nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		req.Header.Set("Access-Control-Request-Headers", "authorization")
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSWildcardWithoutCredentials(t *testing.T) {
+	withCORSConfig(t, []string{"*"}, false)
+	w := doCORS(newCORSRouter(), http.MethodGet, "https://a.example.com")
+	if w.Body.String() != "pong" || w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("status=%d headers=%v", w.Code, w.Header())
+	}
+	if w.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+		t.Fatalf("expose headers = %q", w.Header().Get("Access-Control-Expose-Headers"))
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	withCORSConfig(t, []string{"https://*.example.com"}, true)
+	router := newCORSRouter()
+
+	w := doCORS(router, http.MethodOptions, "https://tenant-a.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("preflight status = %d, want 204", w.Code)
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://tenant-a.example.com" || h.Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("preflight origin headers = %v", h)
+	}
+	if h.Get("Access-Control-Allow-Methods") != "GET, POST, PUT" || h.Get("Access-Control-Allow-Headers") != "Authorization, Content-Type" {
+		t.Fatalf("preflight allow headers = %v", h)
+	}
+	if h.Get("Access-Control-Max-Age") != "3600" {
+		t.Fatalf("Access-Control-Max-Age = %q, want 3600", h.Get("Access-Control-Max-Age"))
+	}
+
+	// 不允许的来源：预检被拒绝，普通请求不返回 CORS 响应头
+	if w := doCORS(router, http.MethodOptions, "https://example.org"); w.Code != http.StatusForbidden {
+		t.Fatalf("disallowed preflight status = %d, want 403", w.Code)
+	}
+	if w := doCORS(router, http.MethodGet, "https://example.org"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("disallowed origin got Access-Control-Allow-Origin = %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORSTenantOriginsAndReload(t *testing.T) {
+	repos := withCORSConfig(t, []string{"https://admin.example.com"}, false)
+	router := newCORSRouter()
+
+	const tenantOrigin = "https://shop.tenant-b.com"
+	if w := doCORS(router, http.MethodGet, tenantOrigin); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("tenant origin should be rejected before it is registered")
+	}
+	ctx := context.Background()
+	tenant := system.SystemTenant{Code: "tenant-b", Status: system.StatusEnabled, AllowedOrigins: "https://*.tenant-b.com"}
+	if err := repos.Tenants.Create(ctx, &tenant); err != nil {
+		t.Fatal(err)
+	}
+	if err := corsdomain.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if w := doCORS(router, http.MethodGet, tenantOrigin); w.Header().Get("Access-Control-Allow-Origin") != tenantOrigin {
+		t.Fatalf("tenant origin Access-Control-Allow-Origin = %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	// 配置热加载后同一个中间件实例立即使用新的来源列表
+	withRuntime(t, func(rt *config.Runtime) { rt.CORSAllowOrigins = []string{"https://new-admin.example.com"} })
+	if w := doCORS(router, http.MethodGet, "https://admin.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("old origin should be rejected after reload")
+	}
+	if w := doCORS(router, http.MethodGet, "https://new-admin.example.com"); w.Header().Get("Vary") != "Origin" {
+		t.Fatalf("Vary = %q, want Origin", w.Header().Get("Vary"))
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/ipaccess"
+)
+
+func TestIPAccess(t *testing.T) {
+	t.Cleanup(func() { ipaccess.Init(memory.New().Repos()) })
+	withRuntime(t, func(rt *config.Runtime) { rt.IPAccessEnabled = true })
+	ctx := context.Background()
+	repos := memory.New().Repos()
+	if err := repos.IPRules.Replace(ctx, ipaccess.ScopeTenant, 7, []system.SystemIPRule{
+		{Scope: ipaccess.ScopeTenant, TenantID: 7, Action: ipaccess.ActionAllow, CIDR: "203.0.113.0/24"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.IPRules.Replace(ctx, ipaccess.ScopePlatform, 0, []system.SystemIPRule{
+		{Scope: ipaccess.ScopePlatform, Action: ipaccess.ActionAllow, CIDR: "10.0.0.0/8"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ipaccess.Init(repos)
+	if err := ipaccess.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	core, logs := observer.New(zap.WarnLevel)
+	logger := zap.New(core)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	setTenant := func(c *gin.Context) {
+		c.Set("logger", logger)
+		c.Set("tenant_id", uint(7))
+		c.Set("user_id", uint(3))
+		c.Set("account", "alice")
+	}
+	router.GET("/system", setTenant, IPAccess(), func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	router.GET("/platform", setTenant, IPAccess(), PlatformIPAccess(), func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	do := func(path, remote string) (string, int) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote + ":1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Body.String() == "ok" {
+			return "ok", 0
+		}
+		var body struct {
+			Code int `json:"code"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		return "", body.Code
+	}
+
+	if got, _ := do("/system", "203.0.113.5"); got != "ok" {
+		t.Fatal("tenant allowlist hit should pass")
+	}
+	if _, code := do("/system", "192.0.2.1"); code != 403 {
+		t.Fatalf("tenant allowlist miss code = %d, want 403", code)
+	}
+	if _, code := do("/platform", "203.0.113.5"); code != 403 {
+		t.Fatalf("platform allowlist miss code = %d, want 403", code)
+	}
+	if logs.FilterMessage("IP 访问控制拒绝请求").Len() != 2 {
+		t.Fatalf("denial logs = %d, want 2", logs.Len())
+	}
+	entry := logs.All()[1].ContextMap()
+	if entry["scope"] != ipaccess.ScopePlatform || entry["account"] != "alice" {
+		t.Fatalf("denial log fields = %v", entry)
+	}
+
+	// 紧急开关关闭后不再校验
+	withRuntime(t, func(rt *config.Runtime) { rt.IPAccessEnabled = false })
+	if got, _ := do("/platform", "192.0.2.1"); got != "ok" {
+		t.Fatal("disabled ip access should pass")
+	}
+}
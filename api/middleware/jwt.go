@@ -10,11 +10,16 @@ import (
 )
 
 // TokenVerify 多租户JWT认证中间件
+// 未携带 token 但提供了已校验的客户端证书（server.client_auth 为 optional/required）时，改为按服务身份认证
 func TokenVerify(c *gin.Context) {
 	c.FormFile("file") // 防止文件未发送完成就返回错误, 导致前端504而不是正确响应
 
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
+		if cert := verifiedClientCert(c); cert != nil {
+			serviceIdentityVerify(c, cert)
+			return
+		}
 		response.ReturnError(c, response.UNAUTHENTICATED, "未携带 token")
 		c.Abort()
 		return
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -71,8 +74,34 @@ func (rl *RateLimiter) getLimiter(key string) *rate.Limiter {
 }
 
 func (rl *RateLimiter) allow(key string) bool {
+	return rl.take(key).Allowed
+}
+
+// take 判定是否放行，并返回剩余额度等信息
+func (rl *RateLimiter) take(key string) limitResult {
 	limiter := rl.getLimiter(key)
-	return limiter.Allow()
+	now := time.Now()
+	result := limitResult{Limit: rl.burst}
+
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return result
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		result.RetryAfter = delay
+	} else {
+		result.Allowed = true
+	}
+
+	tokens := limiter.TokensAt(now)
+	if tokens > 0 {
+		result.Remaining = int(math.Floor(tokens))
+	}
+	if missing := float64(rl.burst) - tokens; missing > 0 && rl.rate > 0 {
+		result.ResetAfter = time.Duration(missing / float64(rl.rate) * float64(time.Second))
+	}
+	return result
 }
 
 func (rl *RateLimiter) cleanup() {
@@ -195,8 +224,11 @@ func getLimiterFromCache(r rate.Limit, b int) *RateLimiter {
 }
 
 type RateLimitOptions struct {
-	Rate    rate.Limit
-	Burst   int
+	Rate  rate.Limit
+	Burst int
+	// Limits 非空时在每次请求时读取速率与突发量（覆盖 Rate/Burst），用于随配置热加载变化的限流；
+	// enabled 为 false 时直接放行
+	Limits  func() (r rate.Limit, b int, enabled bool)
 	KeyFunc func(*gin.Context) string
 	Message string
 }
@@ -222,23 +254,23 @@ func TokenRateLimit(r, b int) gin.HandlerFunc {
 }
 
 func RateLimitWithOptions(opts RateLimitOptions) gin.HandlerFunc {
-	if opts.Rate <= 0 {
-		opts.Rate = rate.Limit(1)
-	}
-	if opts.Burst <= 0 {
-		opts.Burst = 1
-	}
+	opts.Rate, opts.Burst = normalizeLimit(opts.Rate, opts.Burst)
 	if opts.KeyFunc == nil {
 		opts.KeyFunc = func(c *gin.Context) string { return c.ClientIP() }
 	}
 	if opts.Message == "" {
 		opts.Message = "请求过于频繁，请稍后再试"
 	}
+	if opts.Limits != nil {
+		return dynamicRateLimit(opts)
+	}
 	limiter := getLimiterFromCache(opts.Rate, opts.Burst)
+	distributed := newRedisLimiter(opts.Rate, opts.Burst)
 
 	return func(c *gin.Context) {
-		key := opts.KeyFunc(c)
-		if !limiter.allow(key) {
+		result := takeLimit(c, limiter, distributed, opts.KeyFunc(c))
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
 			response.ReturnError(c, response.RESOURCE_EXHAUSTED, opts.Message)
 			return
 		}
@@ -246,23 +278,79 @@ func RateLimitWithOptions(opts RateLimitOptions) gin.HandlerFunc {
 	}
 }
 
-func getTokenKey(c *gin.Context) string {
-	jwtData, exists := c.Get("jwtData")
-	if !exists {
-		return c.ClientIP()
-	}
-	switch v := jwtData.(type) {
-	case string:
-		if v != "" {
-			return v
+// dynamicRateLimit 每次请求按 opts.Limits 取得当前参数；参数变化后使用对应参数的限流器，
+// 同参数的限流器在进程内共享
+func dynamicRateLimit(opts RateLimitOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, b, enabled := opts.Limits()
+		if !enabled {
+			c.Next()
+			return
 		}
-	case map[string]interface{}:
-		if id, ok := v["id"].(string); ok && id != "" {
-			return id
+		r, b = normalizeLimit(r, b)
+		result := takeLimit(c, getLimiterFromCache(r, b), newRedisLimiter(r, b), opts.KeyFunc(c))
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
+			response.ReturnError(c, response.RESOURCE_EXHAUSTED, opts.Message)
+			return
 		}
-		if uid, ok := v["user_id"].(string); ok && uid != "" {
-			return uid
+		c.Next()
+	}
+}
+
+func normalizeLimit(r rate.Limit, b int) (rate.Limit, int) {
+	if r <= 0 {
+		r = rate.Limit(1)
+	}
+	if b <= 0 {
+		b = 1
+	}
+	return r, b
+}
+
+// takeLimit 按配置的后端判定是否放行；Redis 不可用时回退本地限流，保证限流不失效
+func takeLimit(c *gin.Context, local *RateLimiter, distributed *redisLimiter, key string) limitResult {
+	if !useRedisBackend() {
+		return local.take(key)
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), redisLimitTimeout)
+	defer cancel()
+	result, err := distributed.take(ctx, redisScripter(), key)
+	if err != nil {
+		logFallback(err)
+		return local.take(key)
+	}
+	return result
+}
+
+// setRateLimitHeaders 写入限流响应头；Reset、Retry-After 为距今的秒数（向上取整）
+func setRateLimitHeaders(c *gin.Context, result limitResult) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+	if !result.Allowed {
+		retryAfter := ceilSeconds(result.RetryAfter)
+		if retryAfter < 1 {
+			retryAfter = 1
 		}
+		c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	}
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// getTokenKey 以 TokenVerify 写入上下文的租户、用户ID（或服务身份）作为限流键；未认证请求回退为客户端 IP
+func getTokenKey(c *gin.Context) string {
+	if userID := GetCurrentUserID(c); userID != 0 {
+		return fmt.Sprintf("t%d:u%d", GetTenantID(c), userID)
+	}
+	if name := GetServiceIdentity(c); name != "" {
+		return fmt.Sprintf("t%d:s:%s", GetTenantID(c), name)
 	}
 	return c.ClientIP()
 }
@@ -279,25 +367,37 @@ func RelaxedRateLimit() gin.HandlerFunc {
 	return IPRateLimit(100, 200)
 }
 
-// LoginRateLimitMiddleware 使用配置驱动的登录限流
+// GlobalRateLimitMiddleware 全局 IP 限流，开关与参数（server.enable_rate_limit/global_rate_*）随配置热加载生效
+func GlobalRateLimitMiddleware() gin.HandlerFunc {
+	return RateLimitWithOptions(RateLimitOptions{
+		Limits: func() (rate.Limit, int, bool) {
+			rt := config.Current()
+			return rate.Limit(rt.GlobalRateLimit), rt.GlobalRateBurst, rt.EnableRateLimit
+		},
+		KeyFunc: func(c *gin.Context) string { return c.ClientIP() },
+		Message: "IP 请求过于频繁",
+	})
+}
+
+// LoginRateLimitMiddleware 使用配置驱动的登录限流，参数随配置热加载生效
 func LoginRateLimitMiddleware() gin.HandlerFunc {
-	ratePerSec := rate.Limit(float64(config.LoginRatePerMinute) / 60.0)
-	if ratePerSec <= 0 {
-		ratePerSec = rate.Limit(1)
-	}
 	return RateLimitWithOptions(RateLimitOptions{
-		Rate:    ratePerSec,
-		Burst:   config.LoginBurstSize,
+		Limits: func() (rate.Limit, int, bool) {
+			rt := config.Current()
+			return rate.Limit(float64(rt.LoginRatePerMinute) / 60.0), rt.LoginBurstSize, true
+		},
 		KeyFunc: func(c *gin.Context) string { return c.ClientIP() },
 		Message: "登录请求过于频繁，请稍后再试",
 	})
 }
 
-// GeneralRateLimitMiddleware 全局接口限流
+// GeneralRateLimitMiddleware 全局接口限流，参数随配置热加载生效
 func GeneralRateLimitMiddleware() gin.HandlerFunc {
 	return RateLimitWithOptions(RateLimitOptions{
-		Rate:    rate.Limit(config.GeneralRatePerSec),
-		Burst:   config.GeneralBurstSize,
+		Limits: func() (rate.Limit, int, bool) {
+			rt := config.Current()
+			return rate.Limit(rt.GeneralRatePerSec), rt.GeneralBurstSize, true
+		},
 		KeyFunc: func(c *gin.Context) string { return c.ClientIP() },
 		Message: "请求过于频繁，请稍后再试",
 	})
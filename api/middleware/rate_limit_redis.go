@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"api-server/config"
+	"api-server/db/rdb"
+)
+
+const (
+	// RateLimitBackendLocal 进程内限流（每个实例独立计数）
+	RateLimitBackendLocal = "local"
+	// RateLimitBackendRedis Redis 分布式限流（多实例共享计数）
+	RateLimitBackendRedis = "redis"
+
+	// redisLimitTimeout 单次 Redis 限流判定的超时时间，超时即回退本地限流
+	redisLimitTimeout = 50 * time.Millisecond
+	// fallbackLogInterval Redis 不可用时回退日志的最小间隔，避免刷屏
+	fallbackLogInterval = time.Minute
+)
+
+// gcraScript 使用 GCRA（通用信元速率算法）在 Redis 中原子判定是否放行。
+// 仅保存“理论到达时间”（TAT）一个值，使用 Redis 服务端时间避免各实例时钟偏差。
+//
+//	KEYS[1] 限流键
+//	ARGV[1] 放行一个请求的时间间隔（微秒，1/rate）
+//	ARGV[2] 突发容量（burst）
+//
+// 返回 {是否放行, 剩余可用次数, 距离完全恢复的微秒数, 需等待的微秒数}
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tau = interval * burst
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - tau
+if now < allow_at then
+  return {0, 0, tat - now, allow_at - now}
+end
+
+local ttl = new_tat - now
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil(ttl / 1000))
+return {1, math.floor((now - allow_at) / interval), ttl, 0}
+`)
+
+// limitResult 单次限流判定结果，用于填充 X-RateLimit-* 响应头
+type limitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // 令牌桶恢复满额所需时间
+	RetryAfter time.Duration // 被拒绝时建议的重试等待时间
+}
+
+// redisLimiter 基于 Redis 的分布式限流器
+type redisLimiter struct {
+	name     string
+	interval int64 // 微秒
+	burst    int
+}
+
+func newRedisLimiter(r rate.Limit, b int) *redisLimiter {
+	return &redisLimiter{
+		name:     limiterKey(r, b),
+		interval: int64(math.Ceil(float64(time.Second/time.Microsecond) / float64(r))),
+		burst:    b,
+	}
+}
+
+func (l *redisLimiter) take(ctx context.Context, client redis.Scripter, key string) (limitResult, error) {
+	redisKey := fmt.Sprintf("%s%s:%s", config.Current().RateLimitRedisPrefix, l.name, key)
+	values, err := gcraScript.Run(ctx, client, []string{redisKey}, l.interval, l.burst).Int64Slice()
+	if err != nil {
+		return limitResult{}, err
+	}
+	if len(values) != 4 {
+		return limitResult{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	return limitResult{
+		Allowed:    values[0] == 1,
+		Limit:      l.burst,
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+var lastFallbackLog atomic.Int64
+
+// logFallback Redis 限流失败时按间隔记录回退日志
+func logFallback(err error) {
+	now := time.Now().UnixNano()
+	last := lastFallbackLog.Load()
+	if now-last < int64(fallbackLogInterval) || !lastFallbackLog.CompareAndSwap(last, now) {
+		return
+	}
+	zap.L().Warn("Redis 限流不可用，回退为本地限流", zap.Error(err))
+}
+
+// useRedisBackend 每次请求读取配置，便于配置热加载后立即切换
+func useRedisBackend() bool {
+	return config.Current().RateLimitBackend == RateLimitBackendRedis
+}
+
+// redisScripter 返回用于执行限流脚本的 Redis 客户端
+var redisScripter = func() redis.Scripter {
+	return rdb.GetClient()
+}
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+
+	"api-server/config"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/ratelimit"
+)
+
+func newRateLimitRouter(r rate.Limit, b int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ping", RateLimitWithOptions(RateLimitOptions{
+		Rate:    r,
+		Burst:   b,
+		KeyFunc: func(c *gin.Context) string { return "client" },
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	return router
+}
+
+func doPing(router *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	return w
+}
+
+func withRateLimitBackend(t *testing.T, backend string, scripter func() redis.Scripter) {
+	t.Helper()
+	prevScripter := redisScripter
+	withRuntime(t, func(rt *config.Runtime) {
+		rt.RateLimitBackend = backend
+		rt.RateLimitRedisPrefix = "test:ratelimit:"
+	})
+	if scripter != nil {
+		redisScripter = scripter
+	}
+	t.Cleanup(func() {
+		redisScripter = prevScripter
+		CleanupAllLimiters()
+	})
+}
+
+func assertLimited(t *testing.T, router *gin.Engine, burst int) {
+	t.Helper()
+	for i := 0; i < burst; i++ {
+		w := doPing(router)
+		if w.Body.String() != "pong" {
+			t.Fatalf("request %d should pass, body=%s", i+1, w.Body.String())
+		}
+		if got, want := w.Header().Get("X-RateLimit-Remaining"), string(rune('0'+burst-i-1)); got != want {
+			t.Fatalf("request %d remaining = %s, want %s", i+1, got, want)
+		}
+	}
+
+	w := doPing(router)
+	if w.Body.String() == "pong" {
+		t.Fatal("request beyond burst should be limited")
+	}
+	if w.Header().Get("X-RateLimit-Limit") != "3" {
+		t.Fatalf("X-RateLimit-Limit = %s, want 3", w.Header().Get("X-RateLimit-Limit"))
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("X-RateLimit-Reset") == "" {
+		t.Fatalf("missing Retry-After/X-RateLimit-Reset headers: %v", w.Header())
+	}
+}
+
+func TestRateLimitLocalHeaders(t *testing.T) {
+	withRateLimitBackend(t, RateLimitBackendLocal, nil)
+	assertLimited(t, newRateLimitRouter(rate.Limit(0.01), 3), 3)
+}
+
+func TestRateLimitRedisSharedAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	withRateLimitBackend(t, RateLimitBackendRedis, func() redis.Scripter { return client })
+
+	// 两个路由模拟两个实例：计数共享，总放行数不超过 burst
+	first := newRateLimitRouter(rate.Limit(0.02), 3)
+	second := newRateLimitRouter(rate.Limit(0.02), 3)
+
+	passed := 0
+	for i := 0; i < 6; i++ {
+		router := first
+		if i%2 == 1 {
+			router = second
+		}
+		if doPing(router).Body.String() == "pong" {
+			passed++
+		}
+	}
+	if passed != 3 {
+		t.Fatalf("passed = %d, want 3", passed)
+	}
+}
+
+func TestRateLimitRedisFallbackToLocal(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	withRateLimitBackend(t, RateLimitBackendRedis, func() redis.Scripter { return client })
+
+	assertLimited(t, newRateLimitRouter(rate.Limit(0.03), 3), 3)
+}
+
+// newTenantRateLimitRouter 租户 1 的策略：租户额度 tenantBurst、用户额度 userBurst（均几乎不恢复），X-User 头指定用户
+func newTenantRateLimitRouter(t *testing.T, tenantBurst, userBurst int) func(user string) string {
+	t.Helper()
+	withRateLimitBackend(t, RateLimitBackendLocal, nil)
+	withRuntime(t, func(rt *config.Runtime) {
+		rt.RateLimitPolicies = map[string]config.RateLimitPolicy{
+			"system": {
+				Tenant: config.RateLimitRule{Rate: 0.01, Burst: tenantBurst},
+				User:   config.RateLimitRule{Rate: 0.01, Burst: userBurst},
+			},
+		}
+	})
+	// 无租户覆盖，使用上面的配置文件策略
+	ratelimit.Init(memory.New().Repos())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ping", func(c *gin.Context) {
+		c.Set("tenant_id", uint(1))
+		c.Set("user_id", uint(c.GetHeader("X-User")[0]-'0'))
+		c.Next()
+	}, TenantRateLimit("system"), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	return func(user string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("X-User", user)
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+}
+
+func TestTenantRateLimitSharesTenantQuota(t *testing.T) {
+	call := newTenantRateLimitRouter(t, 3, 2)
+
+	// 用户 1 受用户级额度（2）限制
+	if call("1") != "pong" || call("1") != "pong" {
+		t.Fatal("user 1 first two requests should pass")
+	}
+	if call("1") == "pong" {
+		t.Fatal("user 1 third request should hit user quota")
+	}
+	// 用户 1 放行的两次请求占用了租户额度（3）中的 2 次，用户 2 只剩 1 次
+	if call("2") != "pong" {
+		t.Fatal("user 2 first request should use the remaining tenant quota")
+	}
+	if call("2") == "pong" {
+		t.Fatal("user 2 second request should hit shared tenant quota")
+	}
+}
+
+func TestTenantRateLimitUserRejectionKeepsTenantQuota(t *testing.T) {
+	call := newTenantRateLimitRouter(t, 3, 1)
+
+	if call("1") != "pong" {
+		t.Fatal("user 1 first request should pass")
+	}
+	// 被用户级规则拒绝的请求不扣减租户额度
+	for i := 0; i < 10; i++ {
+		if call("1") == "pong" {
+			t.Fatalf("user 1 request %d should hit user quota", i+2)
+		}
+	}
+	if call("2") != "pong" || call("3") != "pong" {
+		t.Fatal("other users should still have tenant quota left")
+	}
+}
+
+func TestGlobalRateLimitFollowsConfigReload(t *testing.T) {
+	withRateLimitBackend(t, RateLimitBackendLocal, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ping", GlobalRateLimitMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	// 关闭时不限流
+	withRuntime(t, func(rt *config.Runtime) { rt.EnableRateLimit = false })
+	for i := 0; i < 5; i++ {
+		if w := doPing(router); w.Body.String() != "pong" || w.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("request %d should bypass disabled rate limit", i+1)
+		}
+	}
+
+	// 热加载开启后，同一个中间件实例按新参数限流
+	withRuntime(t, func(rt *config.Runtime) { rt.EnableRateLimit, rt.GlobalRateLimit, rt.GlobalRateBurst = true, 1, 2 })
+	if doPing(router).Body.String() != "pong" || doPing(router).Body.String() != "pong" {
+		t.Fatal("first two requests should pass")
+	}
+	if doPing(router).Body.String() == "pong" {
+		t.Fatal("third request should be limited")
+	}
+
+	// 调大突发量后立即使用新的限流器
+	withRuntime(t, func(rt *config.Runtime) { rt.GlobalRateBurst = 3 })
+	if w := doPing(router); w.Body.String() != "pong" || w.Header().Get("X-RateLimit-Limit") != "3" {
+		t.Fatalf("request after reload should use new burst, limit=%s", w.Header().Get("X-RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net"
+	"net/netip"
+
+	"api-server/config"
+	"api-server/util/realip"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RealIP 直接连接方属于 server.trusted_proxies 时，按 server.client_ip_headers 的优先级
+// 从转发头解析客户端真实 IP 并改写 Request.RemoteAddr，
+// 之后的 c.ClientIP()（登录日志、限流、访问日志等）均得到真实地址。
+// 需注册为第一个中间件；可信代理与请求头在每次请求时读取配置，随热加载生效。
+func RealIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rt := config.Current()
+		trusted := rt.TrustedProxies
+		if len(trusted) == 0 {
+			c.Next()
+			return
+		}
+		host, port, err := net.SplitHostPort(c.Request.RemoteAddr)
+		if err != nil {
+			c.Next()
+			return
+		}
+		remote, err := netip.ParseAddr(host)
+		if err != nil {
+			c.Next()
+			return
+		}
+		ip := realip.ClientIP(remote, c.Request.Header, trusted, rt.ClientIPHeaders)
+		if ip != remote {
+			c.Request.RemoteAddr = net.JoinHostPort(ip.String(), port)
+		}
+		c.Next()
+	}
+}
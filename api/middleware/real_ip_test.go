@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/config"
+)
+
+func TestRealIP(t *testing.T) {
+	withRuntime(t, func(rt *config.Runtime) { rt.ClientIPHeaders = []string{"X-Forwarded-For", "X-Real-Ip"} })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.SetTrustedProxies(nil)
+	router.Use(RealIP())
+	router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	tests := []struct {
+		name    string
+		trusted []netip.Prefix
+		remote  string
+		xff     string
+		want    string
+	}{
+		{"no trusted proxies", nil, "10.0.0.5:1234", "198.51.100.7", "10.0.0.5"},
+		{"trusted proxy", []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "10.0.0.5:1234", "198.51.100.7", "198.51.100.7"},
+		{"spoofed from untrusted client", []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "203.0.113.9:1234", "198.51.100.7", "203.0.113.9"},
+	}
+	for _, tt := range tests {
+		withRuntime(t, func(rt *config.Runtime) { rt.TrustedProxies = tt.trusted })
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = tt.remote
+		req.Header.Set("X-Forwarded-For", tt.xff)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("%s: ClientIP() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"api-server/util/id"
+	httplog "api-server/util/log"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -19,6 +20,8 @@ func RequestID() gin.HandlerFunc {
 
 		c.Set(RequestIDKey, requestID)
 		c.Header(RequestIDKey, requestID)
+		// 同时写入 Request 的 context，供 GORM 等下游日志关联请求 ID
+		c.Request = c.Request.WithContext(httplog.WithRequestID(c.Request.Context(), requestID))
 
 		contextLogger := zap.L().With(
 			zap.String("trace_id", requestID),
@@ -28,8 +31,9 @@ func RequestID() gin.HandlerFunc {
 		)
 		c.Set("logger", contextLogger)
 
-		contextLogger.Info("请求开始")
+		// 请求级别的汇总信息由 AccessLog 记录，这里仅保留 Debug 级别的起止日志
+		contextLogger.Debug("请求开始")
 		c.Next()
-		contextLogger.Info("请求结束", zap.Int("status_code", c.Writer.Status()))
+		contextLogger.Debug("请求结束", zap.Int("status_code", c.Writer.Status()))
 	}
 }
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/config"
+)
+
+// withRuntime 基于当前运行时配置修改后发布，测试结束后恢复；多次调用按调用顺序叠加
+func withRuntime(t *testing.T, update func(rt *config.Runtime)) {
+	t.Helper()
+	prev := config.Current()
+	t.Cleanup(func() { config.StoreRuntime(prev) })
+	next := *prev
+	update(&next)
+	config.StoreRuntime(&next)
+}
+
+// TestMiddlewareReadsRuntimeDuringReload 请求处理与配置热加载并发执行，需配合 -race 运行
+func TestMiddlewareReadsRuntimeDuringReload(t *testing.T) {
+	withRateLimitBackend(t, RateLimitBackendLocal, nil)
+	withRuntime(t, func(rt *config.Runtime) {
+		rt.EnableRateLimit, rt.GlobalRateLimit, rt.GlobalRateBurst = true, 1000, 1000
+		rt.CORSAllowOrigins = []string{"https://admin.example.com"}
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RealIP(), GlobalRateLimitMiddleware(), CORS(), IPAccess())
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			next := *config.Current()
+			next.IPAccessEnabled = i%2 == 0
+			next.GlobalRateBurst = 1000 + i
+			next.CORSAllowOrigins = []string{"https://admin.example.com"}
+			config.StoreRuntime(&next)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	wg.Wait()
+}
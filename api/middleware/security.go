@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"api-server/api/response"
+	"api-server/config"
 )
 
 // BodySizeLimit 限制请求体大小
@@ -21,6 +22,13 @@ func BodySizeLimit(maxSize int64) gin.HandlerFunc {
 	}
 }
 
+// MaxBodySizeLimit 按 server.max_body_size 限制请求体大小，每次请求读取配置，随热加载生效
+func MaxBodySizeLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		BodySizeLimit(config.Current().MaxBodySize)(c)
+	}
+}
+
 // SecurityHeaders 添加常见安全响应头
 func SecurityHeaders() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -1,15 +1,39 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"api-server/api/response"
 	"api-server/db/pgdb/system"
 )
 
+// UserLookup 按 ID 查询用户，由 user.Service 实现；测试中可基于内存仓储构造
+type UserLookup interface {
+	GetUserProfile(ctx context.Context, userID uint) (system.SystemUser, error)
+}
+
 // SuperAdminVerify 超级管理员权限验证中间件
 // 只有用户ID为1的超级管理员才能执行租户管理操作
-func SuperAdminVerify(c *gin.Context) {
+// 平台租户的服务身份已在 TokenVerify 中按接口权限校验，直接放行
+func SuperAdminVerify(users UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		superAdminVerify(c, users)
+	}
+}
+
+func superAdminVerify(c *gin.Context, users UserLookup) {
+	if isPlatformService(c) {
+		c.Next()
+		return
+	}
+	if GetServiceIdentity(c) != "" {
+		response.ReturnError(c, response.PERMISSION_DENIED, "权限不足，只有平台租户的服务身份可以执行平台管理操作")
+		c.Abort()
+		return
+	}
+
 	// 获取当前用户ID
 	userID := GetCurrentUserID(c)
 	if userID == 0 {
@@ -26,9 +50,8 @@ func SuperAdminVerify(c *gin.Context) {
 	}
 
 	// 验证用户存在且状态正常
-	user := system.SystemUser{}
-	user.ID = userID
-	if err := system.GetUser(&user); err != nil {
+	user, err := users.GetUserProfile(c.Request.Context(), userID)
+	if err != nil {
 		response.ReturnError(c, response.UNAUTHENTICATED, "用户不存在")
 		c.Abort()
 		return
@@ -43,14 +66,25 @@ func SuperAdminVerify(c *gin.Context) {
 	c.Next()
 }
 
-// IsSuperAdmin 判断当前请求是否为平台超级管理员
+// IsSuperAdmin 判断当前请求是否为平台超级管理员（含平台租户的服务身份）
 func IsSuperAdmin(c *gin.Context) bool {
-	return GetCurrentUserID(c) == 1
+	return GetCurrentUserID(c) == 1 || isPlatformService(c)
 }
 
 // TenantAdminVerify 租户管理员权限验证中间件
-// 允许超级管理员或租户管理员执行特定操作
-func TenantAdminVerify(c *gin.Context) {
+// 允许超级管理员或租户管理员执行特定操作；服务身份已按接口权限校验，直接放行
+func TenantAdminVerify(users UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantAdminVerify(c, users)
+	}
+}
+
+func tenantAdminVerify(c *gin.Context, users UserLookup) {
+	if GetServiceIdentity(c) != "" {
+		c.Next()
+		return
+	}
+
 	// 获取当前用户ID和租户ID
 	userID := GetCurrentUserID(c)
 	tenantID := GetTenantID(c)
@@ -68,10 +102,8 @@ func TenantAdminVerify(c *gin.Context) {
 	}
 
 	// 验证用户存在且状态正常
-	user := system.SystemUser{}
-	user.ID = userID
-	user.TenantID = tenantID
-	if err := system.GetUser(&user); err != nil {
+	user, err := users.GetUserProfile(c.Request.Context(), userID)
+	if err != nil || user.TenantID != tenantID {
 		response.ReturnError(c, response.UNAUTHENTICATED, "用户不存在或不属于指定租户")
 		c.Abort()
 		return
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/admin/user"
+)
+
+func TestSuperAdminVerifyUsesInjectedUsers(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New().Repos()
+	admin := system.SystemUser{TenantID: 1, Account: "admin", Password: "secret", Status: system.StatusEnabled}
+	admin.ID = 1
+	other := system.SystemUser{TenantID: 1, Account: "other", Password: "secret", Status: system.StatusEnabled}
+	other.ID = 2
+	for _, u := range []*system.SystemUser{&admin, &other} {
+		if err := r.Users.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tenant", func(c *gin.Context) {
+		c.Set("user_id", uint(c.GetHeader("X-User")[0]-'0'))
+		c.Next()
+	}, SuperAdminVerify(user.NewService(r)), func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	call := func(userID string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Header.Set("X-User", userID)
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	if got := call("1"); got != "pong" {
+		t.Fatalf("enabled super admin should pass, got %s", got)
+	}
+	if got := call("2"); got == "pong" {
+		t.Fatal("non super admin should be rejected")
+	}
+
+	admin.Status = system.StatusDisabled
+	if err := r.Users.Update(ctx, &admin); err != nil {
+		t.Fatal(err)
+	}
+	if got := call("1"); got == "pong" {
+		t.Fatal("disabled super admin should be rejected")
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// CodeKey 在 gin.Context 中记录本次响应的业务 code，供访问日志等中间件读取
+const CodeKey = "__response_code__"
+
 func getTraceID(c *gin.Context) string {
 	if traceID, exists := c.Get("X-Request-ID"); exists {
 		if id, ok := traceID.(string); ok {
@@ -133,6 +136,7 @@ func ReturnErrorWithData(c *gin.Context, data responseData, result interface{})
 	data.Timestamp = time.Now().Unix()
 	data.TraceID = getTraceID(c)
 	data.Data = processData(result)
+	c.Set(CodeKey, data.Code)
 	c.JSON(http.StatusOK, data)
 	// Return directly
 	c.Abort()
@@ -144,6 +148,7 @@ func ReturnData(c *gin.Context, result interface{}) {
 	data.Timestamp = time.Now().Unix()
 	data.TraceID = getTraceID(c)
 	data.Data = processData(result)
+	c.Set(CodeKey, data.Code)
 	c.JSON(http.StatusOK, data)
 	// Return directly
 	c.Abort()
@@ -156,6 +161,7 @@ func ReturnDataWithTotal(c *gin.Context, count int, result interface{}) {
 	data.TraceID = getTraceID(c)
 	data.Data = processData(result)
 	data.Total = &count
+	c.Set(CodeKey, data.Code)
 	c.JSON(http.StatusOK, data)
 	// Return directly
 	c.Abort()
@@ -171,6 +177,7 @@ func ReturnError(c *gin.Context, data responseData, description string) {
 		}
 		return description
 	}()
+	c.Set(CodeKey, data.Code)
 	c.JSON(http.StatusOK, data)
 	// Return directly
 	c.Abort()
@@ -181,6 +188,7 @@ func ReturnSuccess(c *gin.Context) {
 	data := Success
 	data.Timestamp = time.Now().Unix()
 	data.TraceID = getTraceID(c)
+	c.Set(CodeKey, data.Code)
 	c.JSON(http.StatusOK, data)
 	// Return directly
 	c.Abort()
@@ -8,15 +8,18 @@ import (
 	"api-server/api/app"
 	"api-server/api/middleware"
 	"api-server/config"
+	admindomain "api-server/domain/admin"
 	httplog "api-server/util/log"
 )
 
-// InitApi 初始化 HTTP 服务
-func InitApi() *gin.Engine {
+// InitApi 初始化 HTTP 服务，services 为注入到各接口的领域服务
+func InitApi(services admindomain.Services) *gin.Engine {
 	// 将 gin 默认日志输出重定向到 zap，保持框架日志与业务日志统一
-	ginLogWriter := httplog.NewZapWriter(zap.L().With(zap.String("logger", "gin")), zapcore.InfoLevel)
+	// gin 日志使用具名 logger，级别可通过 log.level.gin 或平台接口单独调整
+	ginLogger := httplog.Named(httplog.LoggerGin)
+	ginLogWriter := httplog.NewZapWriter(ginLogger, zapcore.InfoLevel)
 	ginErrorWriter := httplog.NewZapWriter(
-		zap.L().With(zap.String("logger", "gin"), zap.String("stream", "stderr")),
+		ginLogger.With(zap.String("stream", "stderr")),
 		zapcore.ErrorLevel,
 	)
 	gin.DefaultWriter = ginLogWriter
@@ -28,22 +31,26 @@ func InitApi() *gin.Engine {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// 访问日志、全局限流、请求体大小与跨域中间件始终注册，开关和参数在每次请求时读取配置，
+	// 修改 config.yaml 后随热加载生效
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
+	// 真实客户端 IP 由 RealIP 按 server.trusted_proxies 解析后写回 RemoteAddr，
+	// gin 自身不信任任何转发头，c.ClientIP() 直接取 RemoteAddr
+	router.Use(middleware.RealIP())
+	router.Use(middleware.AccessLog())
+	router.Use(gin.Recovery())
 	router.SetTrustedProxies(nil)
 
-	if config.EnableRateLimit {
-		router.Use(middleware.IPRateLimit(config.GlobalRateLimit, config.GlobalRateBurst))
-	}
+	router.Use(middleware.GlobalRateLimitMiddleware())
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.RequestID())
-	router.Use(middleware.BodySizeLimit(config.MaxBodySize))
-	router.Use(middleware.CorssDomainHandler())
+	router.Use(middleware.MaxBodySizeLimit())
+	router.Use(middleware.CORS())
 
 	router.Static("/static", "./static")
 
 	apiGroup := router.Group("/api")
-	app.RegisterRoutes(apiGroup)
+	app.RegisterRoutes(apiGroup, services)
 
 	return router
 }
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"go.yaml.in/yaml/v3"
+
+	"api-server/api/auth"
+	"api-server/config"
+	"api-server/db/pgdb"
+	"api-server/db/pgdb/system"
+	"api-server/db/rdb"
+	systemuser "api-server/db/rdb/systemUser"
+	"api-server/domain/admin"
+	"api-server/domain/admin/menu"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/tenant"
+	userdomain "api-server/domain/admin/user"
+)
+
+// 运维子命令：复用与 HTTP 服务相同的领域服务与配置，Run 方法由 kong 注入 context 与 admin.Services。
+
+// runAdminCommand 构造与 HTTP 服务相同的领域服务并执行运维子命令；收到 SIGINT/SIGTERM 时取消
+func runAdminCommand(kctx *kong.Context) error {
+	if pgdb.GetClient() == nil {
+		return errors.New("数据库连接失败")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 与服务进程一致地维护用户缓存，使命令行的修改立即对在线服务可见
+	userdomain.RegisterCacheHandlers()
+	services := admin.NewServices(repo.NewGormRepos(), menu.RedisTreeCache{})
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	return kctx.Run(services)
+}
+
+// UserCmd 用户运维
+type UserCmd struct {
+	Create        UserCreateCmd        `cmd:"" help:"创建用户"`
+	ResetPassword UserResetPasswordCmd `cmd:"" help:"重置用户密码（未指定 --password 时从标准输入读取）"`
+	Disable       UserDisableCmd       `cmd:"" help:"禁用用户"`
+	Enable        UserEnableCmd        `cmd:"" help:"启用被禁用的用户，恢复登录"`
+}
+
+// userRef 通过租户代码与登录账号定位用户
+type userRef struct {
+	Tenant  string `help:"租户代码" required:""`
+	Account string `arg:"" help:"登录账号"`
+}
+
+func (r userRef) resolve(ctx context.Context, svc admin.Services) (system.SystemTenant, system.SystemUser, error) {
+	t, err := svc.Tenants.FindTenantByCode(ctx, r.Tenant)
+	if err != nil {
+		return system.SystemTenant{}, system.SystemUser{}, fmt.Errorf("租户 %s: %w", r.Tenant, err)
+	}
+	u, err := svc.Users.FindUserByAccount(ctx, t.ID, r.Account)
+	if err != nil {
+		return system.SystemTenant{}, system.SystemUser{}, fmt.Errorf("用户 %s: %w", r.Account, err)
+	}
+	return t, u, nil
+}
+
+type UserCreateCmd struct {
+	userRef
+	Name         string `help:"姓名"`
+	Username     string `help:"昵称，默认与账号相同"`
+	Password     string `help:"登录密码（未指定时从标准输入读取）"`
+	RoleID       uint   `help:"角色 ID，必须属于该租户" required:""`
+	DepartmentID uint   `help:"部门 ID"`
+	Phone        string `help:"手机号"`
+}
+
+func (c *UserCreateCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, err := svc.Tenants.FindTenantByCode(ctx, c.Tenant)
+	if err != nil {
+		return fmt.Errorf("租户 %s: %w", c.Tenant, err)
+	}
+	password, err := readPassword(c.Password)
+	if err != nil {
+		return err
+	}
+	username := c.Username
+	if username == "" {
+		username = c.Account
+	}
+	err = svc.Users.AddUser(ctx, t.ID, userdomain.AddUserInput{
+		Name:         c.Name,
+		Username:     username,
+		Account:      c.Account,
+		Password:     password,
+		Phone:        c.Phone,
+		Status:       system.StatusEnabled,
+		RoleID:       c.RoleID,
+		DepartmentID: c.DepartmentID,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已创建用户 %s（租户 %s）\n", c.Account, t.Code)
+	return nil
+}
+
+type UserResetPasswordCmd struct {
+	userRef
+	Password string `help:"新密码（未指定时从标准输入读取）"`
+}
+
+func (c *UserResetPasswordCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, u, err := c.resolve(ctx, svc)
+	if err != nil {
+		return err
+	}
+	password, err := readPassword(c.Password)
+	if err != nil {
+		return err
+	}
+	if err := svc.Users.ResetPassword(ctx, t.ID, u.ID, password); err != nil {
+		return err
+	}
+	fmt.Printf("已重置用户 %s（租户 %s）的密码\n", u.Account, t.Code)
+	return nil
+}
+
+type UserDisableCmd struct {
+	userRef
+}
+
+func (c *UserDisableCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, u, err := c.resolve(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if err := svc.Users.SetUserStatus(ctx, t.ID, u.ID, system.StatusDisabled); err != nil {
+		return err
+	}
+	fmt.Printf("已禁用用户 %s（租户 %s）\n", u.Account, t.Code)
+	return nil
+}
+
+type UserEnableCmd struct {
+	userRef
+}
+
+func (c *UserEnableCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, u, err := c.resolve(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if err := svc.Users.SetUserStatus(ctx, t.ID, u.ID, system.StatusEnabled); err != nil {
+		return err
+	}
+	fmt.Printf("已启用用户 %s（租户 %s）\n", u.Account, t.Code)
+	return nil
+}
+
+// TenantCmd 租户运维
+type TenantCmd struct {
+	Create  TenantCreateCmd  `cmd:"" help:"创建租户"`
+	List    TenantListCmd    `cmd:"" help:"列出全部租户"`
+	Disable TenantDisableCmd `cmd:"" help:"禁用租户，该租户下的用户将无法登录"`
+}
+
+type TenantCreateCmd struct {
+	Code    string `arg:"" help:"租户代码"`
+	Name    string `help:"租户名称" required:""`
+	Contact string `help:"联系人"`
+	Phone   string `help:"联系电话"`
+	Email   string `help:"联系邮箱"`
+}
+
+func (c *TenantCreateCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, err := svc.Tenants.AddTenant(ctx, tenant.AddTenantInput{
+		Code:    c.Code,
+		Name:    c.Name,
+		Contact: c.Contact,
+		Phone:   c.Phone,
+		Email:   c.Email,
+		Status:  system.StatusEnabled,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已创建租户 %s（ID %d）\n", t.Code, t.ID)
+	return nil
+}
+
+type TenantListCmd struct{}
+
+func (c *TenantListCmd) Run(ctx context.Context, svc admin.Services) error {
+	tenants, _, err := svc.Tenants.FindTenantList(ctx, tenant.FindListQuery{}, config.CancelPage, config.CancelPageSize)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCODE\tNAME\tSTATUS")
+	for _, t := range tenants {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.ID, t.Code, t.Name, statusText(t.Status))
+	}
+	return w.Flush()
+}
+
+type TenantDisableCmd struct {
+	Code string `arg:"" help:"租户代码"`
+}
+
+func (c *TenantDisableCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, err := svc.Tenants.FindTenantByCode(ctx, c.Code)
+	if err != nil {
+		return fmt.Errorf("租户 %s: %w", c.Code, err)
+	}
+	if err := svc.Tenants.SetTenantStatus(ctx, t.ID, system.StatusDisabled); err != nil {
+		return err
+	}
+	fmt.Printf("已禁用租户 %s\n", t.Code)
+	return nil
+}
+
+// MenuCmd 平台菜单导入导出
+type MenuCmd struct {
+	Export MenuExportCmd `cmd:"" help:"导出全部菜单与按钮权限为 JSON"`
+	Import MenuImportCmd `cmd:"" help:"从 JSON 按 ID 导入菜单与按钮权限（已存在的更新，不删除文件中没有的菜单）"`
+}
+
+type MenuExportCmd struct {
+	Output string `short:"o" help:"输出文件，默认输出到标准输出" type:"path"`
+}
+
+func (c *MenuExportCmd) Run(ctx context.Context, svc admin.Services) error {
+	data, err := svc.Menus.ExportMenus(ctx)
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	if c.Output == "" {
+		_, err = os.Stdout.Write(raw)
+		return err
+	}
+	if err := os.WriteFile(c.Output, raw, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "已导出 %d 个菜单到 %s\n", len(data.Menus), c.Output)
+	return nil
+}
+
+type MenuImportCmd struct {
+	File string `arg:"" help:"菜单 JSON 文件（menu export 的输出）" type:"existingfile"`
+}
+
+func (c *MenuImportCmd) Run(ctx context.Context, svc admin.Services) error {
+	raw, err := os.ReadFile(c.File)
+	if err != nil {
+		return err
+	}
+	var data menu.MenuExport
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", c.File, err)
+	}
+	result, err := svc.Menus.ImportMenus(ctx, data)
+	// 导入使用了显式 ID，需将自增序列对齐，避免后续通过接口新增菜单时主键冲突；
+	// 导入失败时同样对齐，确保序列不会停留在异常状态
+	if resetErr := system.ResetSequences(ctx); resetErr != nil {
+		return errors.Join(err, resetErr)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("菜单：新增 %d，更新 %d；按钮权限：新增 %d，更新 %d\n",
+		result.MenusCreated, result.MenusUpdated, result.AuthsCreated, result.AuthsUpdated)
+	return nil
+}
+
+// CacheCmd 缓存运维
+type CacheCmd struct {
+	Rebuild CacheRebuildCmd `cmd:"" help:"全量重建用户缓存并使菜单树缓存失效"`
+}
+
+type CacheRebuildCmd struct{}
+
+func (c *CacheRebuildCmd) Run(ctx context.Context, svc admin.Services) error {
+	if err := systemuser.CacheAllUsers(ctx); err != nil {
+		return fmt.Errorf("重建用户缓存失败: %w", err)
+	}
+	if err := svc.Menus.InvalidateTreeCache(ctx); err != nil {
+		return fmt.Errorf("刷新菜单树缓存失败: %w", err)
+	}
+	fmt.Println("缓存已重建")
+	return nil
+}
+
+// TokenCmd 调试用令牌
+type TokenCmd struct {
+	Issue TokenIssueCmd `cmd:"" help:"为指定用户签发访问令牌（仅用于调试）"`
+}
+
+type TokenIssueCmd struct {
+	userRef
+}
+
+func (c *TokenIssueCmd) Run(ctx context.Context, svc admin.Services) error {
+	t, u, err := c.resolve(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if t.Status != system.StatusEnabled || u.Status != system.StatusEnabled {
+		return errors.New("租户或用户已禁用")
+	}
+	token, err := auth.JWTIssue(u.ID, t.ID, u.Account)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "用户 %s（租户 %s），有效期至 %s\n", u.Account, t.Code, time.Now().Add(config.Current().JWTExpiration).Format(time.DateTime))
+	fmt.Println(token)
+	return nil
+}
+
+// ConfigCmd 配置工具
+type ConfigCmd struct {
+	Check ConfigCheckCmd `cmd:"" help:"校验配置并输出合并后的生效配置（文件 + 环境变量 + 默认值，敏感项已脱敏）"`
+}
+
+type ConfigCheckCmd struct{}
+
+// configLoadResult 配置加载结果；加载失败时 config check 仍输出生效配置与问题列表
+type configLoadResult struct {
+	err error
+}
+
+func (c *ConfigCheckCmd) Run(loaded configLoadResult) error {
+	if file := config.GetViper().ConfigFileUsed(); file != "" {
+		fmt.Printf("# 配置文件: %s\n", file)
+	} else {
+		fmt.Println("# 未找到配置文件，仅使用默认值与环境变量")
+	}
+	raw, err := yaml.Marshal(config.MaskedSettings())
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stdout.Write(raw); err != nil {
+		return err
+	}
+
+	err = loaded.err
+	if err == nil {
+		err = config.Validate()
+	}
+	if err == nil {
+		err = rdb.ValidateConfig()
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "配置校验通过")
+	return nil
+}
+
+func statusText(status uint) string {
+	if status == system.StatusEnabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// readPassword 未通过参数指定密码时从标准输入读取一行，避免密码出现在 shell 历史中
+func readPassword(flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	fmt.Fprint(os.Stderr, "请输入密码: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("密码不能为空")
+	}
+	return password, nil
+}
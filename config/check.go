@@ -1,71 +1,465 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"go.uber.org/zap"
+	"github.com/spf13/cast"
+
+	"api-server/util/origin"
 )
 
 const (
-	minJWTKeyLength  = 32
-	unsafeDefaultKey = "YOUR_SECRET_KEY_HERE"
+	minJWTKeyLength = 32
+	// minJWTKeyDistinctChars 密钥中不同字符的最少数量，拦截 "aaaa..." 这类长度达标但熵极低的密钥
+	minJWTKeyDistinctChars = 8
+	unsafeDefaultKey       = "YOUR_SECRET_KEY_HERE"
 )
 
-// CheckConfig 校验关键配置项，缺失则 fatal 并记录日志
-func CheckConfig(
-	JWTKey string,
-	JWTExpiration int64,
-	RedisHost string,
-	RedisPassword string,
-	PgsqlDSN string,
-	AdminPassword string,
-	PWDSalt string,
-) {
-	if JWTKey == "" {
-		zap.L().Fatal("JWTKey 配置缺失，请在 config.yaml 中设置")
+// EnvPrefix 环境变量覆盖的前缀，配置键中的 "." 替换为 "_"，如 server.port 对应 HTTP_SERVICES_SERVER_PORT
+const EnvPrefix = "HTTP_SERVICES"
+
+// EnvName 返回覆盖指定配置键的环境变量名
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Problem 单条配置问题
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// ValidationError 汇总全部配置问题，便于一次修正
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "配置校验失败，共 %d 项问题：", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s（环境变量 %s）：%s", p.Key, EnvName(p.Key), p.Message)
+	}
+	return b.String()
+}
+
+// Validate 按各配置段的约束校验当前生效的配置（文件 + 环境变量 + 默认值），一次性返回全部问题。
+// 校验基于原始配置值而非已解析的全局变量，避免非法的时长、端口被静默解析为 0。
+// 返回的错误类型为 *ValidationError；配置尚未加载时返回 nil。
+func Validate() error {
+	if v == nil {
+		return nil
+	}
+	c := &checker{}
+
+	validateServer(c)
+	validateJWT(c)
+	validateLog(c)
+	validateRedis(c)
+	validatePostgres(c)
+	validateCORS(c)
+	validateServiceIdentities(c)
+	validateSecrets(c)
+	validateMisc(c)
+
+	if len(c.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: c.problems}
+}
+
+func validateServer(c *checker) {
+	c.intRange("server.port", 1, 65535)
+	if _, err := parseSize(getString("server.max_body_size")); err != nil {
+		c.add("server.max_body_size", "无法解析大小 %q，应形如 10MB", getString("server.max_body_size"))
+	}
+	c.intRange("server.max_header_bytes", 1, 64<<20)
+	c.duration("server.shutdown_timeout", time.Second)
+	c.duration("server.upgrade_timeout", time.Second)
+	c.duration("server.read_timeout", 0)
+	c.duration("server.write_timeout", 0)
+	c.duration("server.idle_timeout", 0)
+	if c.flag("server.enable_rate_limit") {
+		c.intRange("server.global_rate_limit", 1, 0)
+		c.intRange("server.global_rate_burst", 1, 0)
+	}
+
+	acme := c.flag("server.enable_acme")
+	tls := c.flag("server.enable_tls")
+	if acme && tls {
+		c.add("server.enable_tls", "ACME 自动证书（server.enable_acme）与本地证书文件模式不能同时启用，请二选一")
+	}
+	if acme {
+		c.required("server.acme_domain", "已启用 ACME，需配置为公网可访问的域名")
+		c.required("server.acme_cache_dir", "已启用 ACME，需配置证书缓存目录")
+	}
+	if tls {
+		c.existingFile("server.tls_cert_file", "已启用 TLS 证书文件模式")
+		c.existingFile("server.tls_key_file", "已启用 TLS 证书文件模式")
+	}
+	if c.flag("server.enable_http3") {
+		if !acme && !tls {
+			c.add("server.enable_http3", "HTTP/3 需要 TLS，请同时启用 server.enable_acme 或 server.enable_tls")
+		}
+		c.intRange("server.http3_port", 0, 65535)
+	}
+	proxies, err := parseTrustedProxies(v.GetStringSlice("server.trusted_proxies"))
+	if err != nil {
+		c.add("server.trusted_proxies", "%v", err)
+	}
+	if _, err := parseClientIPHeaders(v.GetStringSlice("server.client_ip_headers")); err != nil {
+		c.add("server.client_ip_headers", "%v", err)
+	}
+	c.oneOf("server.proxy_protocol", ProxyProtocolOff, ProxyProtocolOptional, ProxyProtocolRequired)
+	if strings.ToLower(getString("server.proxy_protocol")) != ProxyProtocolOff && err == nil && len(proxies) == 0 {
+		c.add("server.proxy_protocol", "启用 PROXY 协议时需配置 server.trusted_proxies（负载均衡器地址），否则任何客户端都能伪造来源地址")
+	}
+	c.oneOf("server.client_auth", ClientAuthNone, ClientAuthOptional, ClientAuthRequired)
+	if mode := strings.ToLower(getString("server.client_auth")); mode == ClientAuthOptional || mode == ClientAuthRequired {
+		if !acme && !tls {
+			c.add("server.client_auth", "客户端证书校验需要 TLS，请同时启用 server.enable_acme 或 server.enable_tls")
+		}
+		c.existingFile("server.client_ca_file", "已启用客户端证书校验")
+	}
+	if c.flag("server.enable_h2c") && (acme || tls) {
+		c.add("server.enable_h2c", "h2c 仅用于明文部署，启用 TLS 时 HTTP/2 会自动协商，请关闭")
+	}
+}
+
+func validateJWT(c *checker) {
+	key := getString("jwt.key")
+	switch {
+	case key == "":
+		c.add("jwt.key", "必填")
+	case key == unsafeDefaultKey:
+		c.add("jwt.key", "仍使用示例值，请替换为随机生成的强密钥")
+	case utf8.RuneCountInString(key) < minJWTKeyLength:
+		c.add("jwt.key", "长度不足：当前 %d 个字符，至少需要 %d 个", utf8.RuneCountInString(key), minJWTKeyLength)
+	case distinctRunes(key) < minJWTKeyDistinctChars:
+		c.add("jwt.key", "强度不足：仅包含 %d 种字符，请使用随机生成的密钥", distinctRunes(key))
+	}
+	c.duration("jwt.expiration", time.Minute)
+}
+
+func validateLog(c *checker) {
+	c.intRange("log.max_size", 1, 0)
+	c.intRange("log.max_backups", 0, 0)
+	c.intRange("log.max_age", 0, 0)
+	levels := parseLogLevels()
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if level := levels[name]; !validLogLevel(level) {
+			key := "log.level"
+			if v.IsSet("log.level." + name) {
+				key = "log.level." + name
+			}
+			c.add(key, "无效的日志级别 %q（debug/info/warn/error/dpanic/panic/fatal）", level)
+		}
+	}
+	if c.flag("log.sampling.enabled") {
+		c.duration("log.sampling.tick", time.Millisecond)
+		c.intRange("log.sampling.initial", 1, 0)
+		c.intRange("log.sampling.thereafter", 1, 0)
+	}
+	sinks := map[string]bool{}
+	for _, sink := range v.GetStringSlice("log.sinks") {
+		sink = strings.ToLower(strings.TrimSpace(sink))
+		switch sink {
+		case "file", "stdout", "syslog", "http":
+			sinks[sink] = true
+		default:
+			c.add("log.sinks", "未知的日志输出 %q（file/stdout/syslog/http）", sink)
+		}
+	}
+	if sinks["syslog"] || sinks["http"] {
+		c.intRange("log.buffer_size", 1, 0)
+	}
+	if sinks["syslog"] {
+		c.oneOf("log.syslog.network", "udp", "tcp", "unix", "unixgram")
+		c.required("log.syslog.address", "已启用 syslog 输出")
+		c.intRange("log.syslog.facility", 0, 23)
+	}
+	if sinks["http"] {
+		c.required("log.http.url", "已启用 http 日志输出")
+		c.oneOf("log.http.format", "loki", "elasticsearch")
+		c.intRange("log.http.batch_size", 1, 0)
+		c.duration("log.http.flush_interval", time.Millisecond)
+		c.duration("log.http.timeout", time.Millisecond)
+	}
+	if rate, err := cast.ToFloat64E(v.Get("access_log.sample_rate")); err != nil {
+		c.add("access_log.sample_rate", "无法解析为数值：%v", v.Get("access_log.sample_rate"))
+	} else if rate < 0 || rate > 1 {
+		c.add("access_log.sample_rate", "应在 0 到 1 之间，当前为 %v", rate)
+	}
+}
+
+func validateRedis(c *checker) {
+	mode := strings.ToLower(getString("redis.mode"))
+	if mode == "" {
+		mode = RedisModeStandalone
+	}
+	switch mode {
+	case RedisModeStandalone, RedisModeSentinel, RedisModeCluster:
+	default:
+		c.add("redis.mode", "无效的部署模式 %q（standalone/sentinel/cluster）", mode)
+	}
+	if getString("redis.host") == "" && len(v.GetStringSlice("redis.addrs")) == 0 {
+		c.add("redis.host", "必填（或配置 redis.addrs）")
+	}
+	if mode == RedisModeSentinel {
+		c.required("redis.master_name", "哨兵模式")
+	}
+	c.required("redis.password", "")
+	if db, ok := c.intRange("redis.db", 0, 0); ok && mode == RedisModeCluster && db != 0 {
+		c.add("redis.db", "集群模式只支持 0")
+	}
+	c.intRange("redis.pool_size", 1, 0)
+	c.intRange("redis.min_idle_conns", 0, 0)
+	for _, key := range []string{"redis.dial_timeout", "redis.read_timeout", "redis.write_timeout", "redis.pool_timeout"} {
+		c.duration(key, 0)
+	}
+	if c.flag("redis.tls.enabled") {
+		cert, key := getString("redis.tls.cert_file"), getString("redis.tls.key_file")
+		if (cert == "") != (key == "") {
+			c.add("redis.tls.key_file", "redis.tls.cert_file 与 redis.tls.key_file 需同时配置")
+		}
+		for _, k := range []string{"redis.tls.ca_file", "redis.tls.cert_file", "redis.tls.key_file"} {
+			if getString(k) != "" {
+				c.existingFile(k, "")
+			}
+		}
+	}
+}
+
+func validatePostgres(c *checker) {
+	c.required("postgres.host", "")
+	c.required("postgres.user", "")
+	c.required("postgres.dbname", "")
+	c.intRange("postgres.port", 1, 65535)
+	c.oneOf("postgres.sslmode", "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	maxOpen, okOpen := c.intRange("postgres.max_open_conns", 0, 0)
+	maxIdle, okIdle := c.intRange("postgres.max_idle_conns", 0, 0)
+	if okOpen && okIdle && maxOpen > 0 && maxIdle > maxOpen {
+		c.add("postgres.max_idle_conns", "不能大于 postgres.max_open_conns（%d）", maxOpen)
 	}
-	if JWTKey == unsafeDefaultKey {
-		zap.L().Fatal("JWT 密钥仍使用示例值，存在严重安全风险！请修改 config.yaml 中的 jwt.key 为强密钥")
+	for _, key := range []string{"postgres.conn_max_lifetime", "postgres.conn_max_idle_time", "postgres.statement_timeout", "postgres.slow_threshold"} {
+		c.duration(key, 0)
 	}
-	if len(JWTKey) < minJWTKeyLength {
-		zap.L().Fatal("JWT 密钥长度不足",
-			zap.Int("current_length", len(JWTKey)),
-			zap.Int("min_required", minJWTKeyLength),
-			zap.String("suggestion", "请使用至少32字符的强密钥"),
-		)
+	primary := PgsqlReplica{Port: v.GetInt("postgres.port")}
+	if _, err := parsePgsqlReplicas(primary, getString("postgres.timezone")); err != nil {
+		c.add("postgres.replicas", "%v", err)
 	}
-	if JWTExpiration == 0 {
-		zap.L().Fatal("JWTExpiration 配置缺失，请在 config.yaml 中设置 jwt.expiration")
+}
+
+func validateCORS(c *checker) {
+	origins := v.GetStringSlice("cors.allow_origins")
+	for _, o := range origins {
+		if err := origin.Validate(o); err != nil {
+			c.add("cors.allow_origins", "无效的来源 %q，应形如 https://example.com、https://*.example.com 或 *", o)
+		}
 	}
-	if RedisHost == "" {
-		zap.L().Fatal("RedisHost 配置缺失")
+	if c.flag("cors.allow_credentials") && slices.Contains(origins, origin.Any) {
+		c.add("cors.allow_credentials", "允许携带凭据时 cors.allow_origins 不能包含 *，请列出具体来源")
 	}
-	if RedisPassword == "" {
-		zap.L().Fatal("RedisPassword 配置缺失")
+	if len(v.GetStringSlice("cors.allow_methods")) == 0 {
+		c.add("cors.allow_methods", "不能为空")
 	}
-	if PgsqlDSN == "" {
-		zap.L().Fatal("PgsqlDSN 配置缺失")
+	c.duration("cors.max_age", 0)
+}
+
+func validateSecrets(c *checker) {
+	c.problems = append(c.problems, secrets.problems...)
+	if v.GetString("secrets.vault.address") != "" {
+		c.oneOf("secrets.vault.kv_version", "1", "2")
+		c.duration("secrets.vault.timeout", 0)
+		if getString("secrets.vault.token") == "" {
+			c.add("secrets.vault.token", "已配置 Vault 地址时必填（可使用 secrets.vault.token_file）")
+		}
 	}
-	if AdminPassword == "" {
-		zap.L().Fatal("AdminPassword 配置缺失")
+	// 安全基线：敏感项必须来自 _file 或 ${provider:ref} 引用，禁止明文
+	if c.flag("secrets.forbid_plaintext") {
+		for _, key := range secretFileKeys {
+			if raw := v.GetString(key); raw != "" && !isSecretRef(raw) {
+				c.add(key, "已启用 secrets.forbid_plaintext，请改用 %s_file 或 ${file:...}/${env:...}/${vault:...} 引用", key)
+			}
+		}
 	}
-	if PWDSalt == "" {
-		zap.L().Fatal("PWDSalt 配置缺失")
+}
+
+func validateMisc(c *checker) {
+	c.required("admin.password", "")
+	c.required("admin.salt", "")
+
+	c.oneOf("rate_limit.backend", "local", "redis")
+	c.intRange("rate_limit.login_rate_per_minute", 1, 0)
+	c.intRange("rate_limit.login_burst_size", 1, 0)
+	c.intRange("rate_limit.general_rate_per_sec", 1, 0)
+	c.intRange("rate_limit.general_burst_size", 1, 0)
+	if _, err := parseRateLimitPolicies(); err != nil {
+		c.add("rate_limit.policies", "%v", err)
 	}
 
-	if EnableACME {
-		if strings.TrimSpace(ACMEDomain) == "" {
-			zap.L().Fatal("已启用 ACME，但未配置 server.acme_domain，请在 config.yaml 中设置为公网可访问的域名")
+	c.flag("ip_access.enabled")
+	c.duration("user_cache.reconcile_interval", 0)
+	c.intRange("tenant.min_query_length", 0, 0)
+}
+
+func validateServiceIdentities(c *checker) {
+	identities, err := parseServiceIdentities()
+	if err != nil {
+		c.add("service_identities", "%v", err)
+		return
+	}
+	names := map[string]bool{}
+	for i, id := range identities {
+		key := fmt.Sprintf("service_identities.%d", i)
+		switch {
+		case strings.TrimSpace(id.Name) == "":
+			c.add(key+".name", "必填")
+		case names[id.Name]:
+			c.add(key+".name", "与其它服务身份重名：%s", id.Name)
+		}
+		names[id.Name] = true
+		if len(id.Subjects) == 0 && len(id.SANs) == 0 {
+			c.add(key, "subjects 与 sans 至少配置一项")
 		}
+		if len(id.Permissions) == 0 {
+			c.add(key+".permissions", "至少配置一项允许访问的接口")
+		}
+		for _, p := range id.Permissions {
+			if err := ValidatePermission(p); err != nil {
+				c.add(key+".permissions", "%v", err)
+			}
+		}
+	}
+}
+
+// ValidatePermission 校验服务身份的接口权限格式 "<METHOD> <路径>"
+func ValidatePermission(p string) error {
+	method, path, ok := strings.Cut(strings.TrimSpace(p), " ")
+	path = strings.TrimSpace(path)
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("无效的权限 %q，应形如 \"GET /api/v1/...\"", p)
+	}
+	if method != "*" && !slices.Contains([]string{"GET", "POST", "PUT", "PATCH", "DELETE"}, strings.ToUpper(method)) {
+		return fmt.Errorf("权限 %q 的请求方法无效", p)
+	}
+	if i := strings.Index(path, "*"); i >= 0 && i != len(path)-1 {
+		return fmt.Errorf("权限 %q 中的 * 只能出现在路径末尾", p)
 	}
+	return nil
+}
+
+// checker 收集配置问题，各方法在发现问题时记录并返回解析结果
+type checker struct {
+	problems []Problem
+}
 
-	if EnableTLS {
-		if strings.TrimSpace(TLSCertFile) == "" || strings.TrimSpace(TLSKeyFile) == "" {
-			zap.L().Fatal("已启用 TLS 证书文件模式，但未正确配置 server.tls_cert_file 或 server.tls_key_file，请在 config.yaml 中设置")
+func (c *checker) add(key, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// required 校验字符串非空；reason 说明必填的前提（如“已启用 ACME”）
+func (c *checker) required(key, reason string) string {
+	value := strings.TrimSpace(getString(key))
+	if value == "" {
+		if reason != "" {
+			c.add(key, "%s时必填", reason)
+		} else {
+			c.add(key, "必填")
+		}
+	}
+	return value
+}
+
+func (c *checker) oneOf(key string, allowed ...string) {
+	value := strings.ToLower(getString(key))
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	c.add(key, "无效的取值 %q（%s）", getString(key), strings.Join(allowed, "/"))
+}
+
+func (c *checker) flag(key string) bool {
+	b, err := cast.ToBoolE(v.Get(key))
+	if err != nil {
+		c.add(key, "无法解析为布尔值：%v", v.Get(key))
+	}
+	return b
+}
+
+// intRange 校验整数及取值范围，max 为 0 表示不限上限
+func (c *checker) intRange(key string, min, max int) (int, bool) {
+	n, err := cast.ToIntE(v.Get(key))
+	if err != nil {
+		c.add(key, "无法解析为整数：%v", v.Get(key))
+		return 0, false
+	}
+	if n < min || (max > 0 && n > max) {
+		if max > 0 {
+			c.add(key, "应在 %d 到 %d 之间，当前为 %d", min, max, n)
+		} else {
+			c.add(key, "不能小于 %d，当前为 %d", min, n)
 		}
+		return n, false
 	}
+	return n, true
+}
+
+// duration 校验时长（如 30s、5m），min 为允许的最小值
+func (c *checker) duration(key string, min time.Duration) (time.Duration, bool) {
+	d, err := cast.ToDurationE(v.Get(key))
+	if err != nil {
+		c.add(key, "无法解析为时长：%v（应形如 30s、5m、1h）", v.Get(key))
+		return 0, false
+	}
+	if d < min {
+		c.add(key, "不能小于 %s，当前为 %s", min, d)
+		return d, false
+	}
+	return d, true
+}
+
+// existingFile 校验文件路径已配置且可读（相对路径基于 AbsPath）
+func (c *checker) existingFile(key, reason string) {
+	path := c.required(key, reason)
+	if path == "" {
+		return
+	}
+	info, err := os.Stat(resolvePath(path))
+	if err != nil {
+		c.add(key, "文件不可访问：%v", err)
+		return
+	}
+	if info.IsDir() {
+		c.add(key, "%s 是目录，需要文件", path)
+	}
+}
+
+func validLogLevel(level string) bool {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug", "info", "warn", "error", "dpanic", "panic", "fatal":
+		return true
+	}
+	return false
+}
 
-	if EnableACME && EnableTLS {
-		zap.L().Fatal("配置错误：ACME 自动 TLS 与本地证书文件 TLS 模式不能同时启用，请二选一")
+func distinctRunes(s string) int {
+	seen := map[rune]struct{}{}
+	for _, r := range s {
+		seen[r] = struct{}{}
 	}
+	return len(seen)
 }
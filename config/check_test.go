@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// validYAML 通过全部校验的最小配置
+const validYAML = `
+jwt:
+  key: "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1"
+redis:
+  host: 127.0.0.1:6379
+  password: redis-secret
+postgres:
+  host: 127.0.0.1
+  user: postgres
+  dbname: server
+admin:
+  password: admin-secret
+  salt: salt
+`
+
+func loadTestConfig(t *testing.T, yaml string) {
+	t.Helper()
+	v = viper.New()
+	v.SetConfigType("yaml")
+	setDefaults()
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+	refreshSecrets()
+}
+
+func problemKeys(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error type = %T, want *ValidationError", err)
+	}
+	keys := make([]string, 0, len(verr.Problems))
+	for _, p := range verr.Problems {
+		keys = append(keys, p.Key)
+	}
+	return keys
+}
+
+func TestValidateValid(t *testing.T) {
+	loadTestConfig(t, validYAML)
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateAggregatesProblems(t *testing.T) {
+	loadTestConfig(t, validYAML+`
+server:
+  port: 70000
+  shutdown_timeout: 10x
+  enable_acme: true
+  enable_tls: true
+  tls_cert_file: missing.pem
+log:
+  level: { gin: verbose }
+  sinks: [file, kafka]
+rate_limit:
+  backend: memcached
+`)
+	got := strings.Join(problemKeys(t, Validate()), ",")
+	for _, key := range []string{
+		"server.port", "server.shutdown_timeout", "server.enable_tls", "server.acme_domain",
+		"server.tls_cert_file", "server.tls_key_file", "log.level.gin", "log.sinks", "rate_limit.backend",
+	} {
+		if !strings.Contains(got, key) {
+			t.Errorf("problems %q missing %s", got, key)
+		}
+	}
+}
+
+func TestValidateJWTKeyStrength(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		ok   bool
+	}{
+		{"example value", unsafeDefaultKey, false},
+		{"too short", "short-key", false},
+		{"low entropy", strings.Repeat("ab", 20), false},
+		{"strong", "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestConfig(t, validYAML)
+			v.Set("jwt.key", tt.key)
+			keys := problemKeys(t, Validate())
+			if tt.ok != (len(keys) == 0) {
+				t.Fatalf("Validate() problems = %v, want ok=%v", keys, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "cert.pem")
+	key := filepath.Join(dir, "key.pem")
+	for _, f := range []string{cert, key} {
+		if err := os.WriteFile(f, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loadTestConfig(t, validYAML)
+	v.Set("server.enable_tls", true)
+	v.Set("server.tls_cert_file", cert)
+	v.Set("server.tls_key_file", key)
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestValidationErrorMentionsEnv(t *testing.T) {
+	err := &ValidationError{Problems: []Problem{{Key: "server.port", Message: "bad"}}}
+	if !strings.Contains(err.Error(), "HTTP_SERVICES_SERVER_PORT") {
+		t.Fatalf("Error() = %q, want env var name", err.Error())
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	loadTestConfig(t, validYAML+`
+cors:
+  allow_origins: ["*", "https://*.example.com", "example.org"]
+  allow_credentials: true
+  max_age: forever
+`)
+	got := problemKeys(t, Validate())
+	want := []string{"cors.allow_origins", "cors.allow_credentials", "cors.max_age"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("problems = %v, want %v", got, want)
+	}
+}
+
+func TestValidateHTTP3AndH2C(t *testing.T) {
+	loadTestConfig(t, validYAML)
+	v.Set("server.enable_http3", true)
+	v.Set("server.http3_port", 70000)
+	if got, want := problemKeys(t, Validate()), []string{"server.enable_http3", "server.http3_port"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("problems = %v, want %v", got, want)
+	}
+
+	loadTestConfig(t, validYAML)
+	v.Set("server.enable_acme", true)
+	v.Set("server.acme_domain", "api.example.com")
+	v.Set("server.enable_http3", true)
+	v.Set("server.enable_h2c", true)
+	if got, want := problemKeys(t, Validate()), []string{"server.enable_h2c"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("problems = %v, want %v", got, want)
+	}
+}
+
+func TestValidateClientAuthAndServiceIdentities(t *testing.T) {
+	loadTestConfig(t, validYAML+`
+service_identities:
+  - name: ci-bot
+    subjects: ["CN=ci-bot"]
+    permissions: ["GET /api/v1/private/admin/platform/*"]
+  - name: ci-bot
+    permissions: ["FETCH /api", "GET /api/*/tenant"]
+`)
+	v.Set("server.client_auth", "required")
+	got := problemKeys(t, Validate())
+	want := []string{
+		"server.client_auth", "server.client_ca_file",
+		"service_identities.1.name", "service_identities.1", "service_identities.1.permissions", "service_identities.1.permissions",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("problems = %v, want %v", got, want)
+	}
+
+	identities, err := parseServiceIdentities()
+	if err != nil || identities[0].TenantID != 1 {
+		t.Fatalf("parseServiceIdentities() = %+v, %v, want default platform tenant", identities, err)
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	loadTestConfig(t, validYAML)
+	v.Set("server.trusted_proxies", []string{"10.0.0.0/8", "not-an-ip"})
+	v.Set("server.client_ip_headers", []string{"X-Forwarded-For", "X-Client-IP"})
+	v.Set("server.proxy_protocol", "sometimes")
+	got := problemKeys(t, Validate())
+	want := []string{"server.trusted_proxies", "server.client_ip_headers", "server.proxy_protocol"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("problems = %v, want %v", got, want)
+	}
+
+	v.Set("server.trusted_proxies", []string{})
+	v.Set("server.client_ip_headers", []string{"forwarded", "x-real-ip"})
+	v.Set("server.proxy_protocol", "required")
+	got = problemKeys(t, Validate())
+	if strings.Join(got, ",") != "server.proxy_protocol" {
+		t.Fatalf("problems = %v, want [server.proxy_protocol]", got)
+	}
+
+	proxies, err := parseTrustedProxies([]string{"192.168.1.7/16", "::ffff:10.0.0.5"})
+	if err != nil || len(proxies) != 2 || proxies[0].String() != "192.168.0.0/16" || proxies[1].String() != "10.0.0.5/32" {
+		t.Fatalf("parseTrustedProxies() = %v, %v", proxies, err)
+	}
+	headers, _ := parseClientIPHeaders([]string{"forwarded", "x-real-ip"})
+	if strings.Join(headers, ",") != "Forwarded,X-Real-Ip" {
+		t.Fatalf("parseClientIPHeaders() = %v", headers)
+	}
+}
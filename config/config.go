@@ -29,23 +29,39 @@ var (
 	LogMaxBackups = 3
 	LogMaxAge     = 30
 	LogModelDev   = "dev"
+	// LogLevels 各具名 logger 的日志级别（键为 default/business/gin/gorm，值为 debug/info/warn/error）
+	LogLevels             = map[string]string{}
+	LogSamplingEnabled    = true
+	LogSamplingTick       = time.Second
+	LogSamplingInitial    = 4
+	LogSamplingThereafter = 1
+	// LogSinks 日志输出目标（file/stdout/syslog/http，可同时启用多个）
+	LogSinks      = []string{"file"}
+	LogBufferSize = 10000 // 远程输出异步队列容量，队列满时丢弃新日志
+	// syslog 输出（RFC 5424）
+	LogSyslogNetwork  = "udp"
+	LogSyslogAddress  = "127.0.0.1:514"
+	LogSyslogAppName  = "api-server"
+	LogSyslogFacility = 16 // local0
+	// HTTP 推送输出（Loki / Elasticsearch）
+	LogHTTPURL           = ""
+	LogHTTPFormat        = "loki"
+	LogHTTPBatchSize     = 500
+	LogHTTPFlushInterval = 2 * time.Second
+	LogHTTPTimeout       = 5 * time.Second
+	LogHTTPLabels        = map[string]string{}
+	LogHTTPHeaders       = map[string]string{}
 )
 
 // Configuration variables that will be loaded from YAML
 var (
-	// jWT
-	JWTKey        string
-	JWTExpiration time.Duration
 	// server
-	MaxBodySize     int64
 	ShutdownTimeout time.Duration
+	UpgradeTimeout  time.Duration
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	MaxHeaderBytes  int
-	EnableRateLimit bool
-	GlobalRateLimit int
-	GlobalRateBurst int
 	PidFile         string // pid 文件路径（支持相对路径，相对 AbsPath）
 	// tls / acme
 	EnableACME   bool
@@ -54,24 +70,131 @@ var (
 	EnableTLS    bool
 	TLSCertFile  string
 	TLSKeyFile   string
+	// http/3 / h2c
+	EnableHTTP3 bool // 在 UDP 上同时提供 HTTP/3，需启用 TLS
+	HTTP3Port   int  // HTTP/3 的 UDP 端口，0 表示与 server.port 相同
+	EnableH2C   bool // 明文 HTTP/2（h2c），用于 TLS 由前置代理终结的部署
+	// 真实客户端 IP
+	ProxyProtocol string // off / optional / required
+	// mtls
+	ClientCAFile string // 签发客户端证书的 CA（PEM），用于校验机器客户端证书
+	ClientAuth   string // none / optional / required
 	// redis
-	RedisHost     string
-	RedisPassword string
+	RedisMode         string   // standalone / sentinel / cluster
+	RedisHost         string   // 单机模式地址
+	RedisAddrs        []string // 哨兵地址或集群节点；为空时使用 RedisHost
+	RedisMasterName   string   // 哨兵模式的主节点名称
+	RedisUsername     string   // ACL 用户名
+	RedisPassword     string
+	RedisDB           int
+	RedisSentinelUser string
+	RedisSentinelPass string
+	RedisPoolSize     int
+	RedisMinIdleConns int
+	RedisDialTimeout  time.Duration
+	RedisReadTimeout  time.Duration
+	RedisWriteTimeout time.Duration
+	RedisPoolTimeout  time.Duration
+	// redis tls
+	RedisTLSEnabled            bool
+	RedisTLSCAFile             string // 自定义 CA（PEM），为空时使用系统根证书
+	RedisTLSCertFile           string // 客户端证书（双向认证时配置）
+	RedisTLSKeyFile            string
+	RedisTLSServerName         string
+	RedisTLSInsecureSkipVerify bool
 	// pgsql
-	PgsqlDSN string
+	PgsqlDSN             string
+	PgsqlReplicaDSNs     []string      // 只读副本 DSN，列表/报表类查询走副本
+	PgsqlMaxOpenConns    int           // 每个连接池（主库与各副本分别计算）的最大连接数
+	PgsqlMaxIdleConns    int           // 每个连接池的最大空闲连接数
+	PgsqlConnMaxLifetime time.Duration // 连接最长复用时间
+	PgsqlConnMaxIdleTime time.Duration // 连接最长空闲时间
+	PgsqlStmtTimeout     time.Duration // 单条 SQL 的服务端执行超时（statement_timeout），0 表示不限制
+	PgsqlSlowThreshold   time.Duration // 慢查询阈值，超过后以 Warn 级别记录
+	PgsqlRedactParams    bool          // SQL 日志中不展开参数
 	// admin config
 	AdminPassword string
 	PWDSalt       string
-	// rate limit config
-	LoginRatePerMinute int
-	LoginBurstSize     int
-	GeneralRatePerSec  int
-	GeneralBurstSize   int
+	// user cache config
+	UserCacheReconcileInterval time.Duration // 用户缓存全量对账间隔
 	// tenant config
-	TenantMinQueryLength int
-	DefaultTenantCode    string
+	DefaultTenantCode string
 )
 
+// RateLimitRule 单条限流规则；Rate 为每秒放行数，Rate<=0 表示不限流
+type RateLimitRule struct {
+	Rate  float64 `mapstructure:"rate" json:"rate"`
+	Burst int     `mapstructure:"burst" json:"burst"`
+}
+
+// Enabled 规则是否生效
+func (r RateLimitRule) Enabled() bool {
+	return r.Rate > 0
+}
+
+// RateLimitPolicy 路由组限流策略：同一租户的所有请求共享 Tenant 额度，单个用户另受 User 额度约束
+type RateLimitPolicy struct {
+	Tenant RateLimitRule `mapstructure:"tenant" json:"tenant"`
+	User   RateLimitRule `mapstructure:"user" json:"user"`
+}
+
+// PROXY 协议模式
+const (
+	ProxyProtocolOff      = "off"      // 不解析 PROXY 协议头
+	ProxyProtocolOptional = "optional" // 来自可信代理的连接可携带 PROXY 协议头
+	ProxyProtocolRequired = "required" // 来自可信代理的连接必须携带 PROXY 协议头
+)
+
+// 客户端证书校验模式
+const (
+	ClientAuthNone     = "none"     // 不请求客户端证书
+	ClientAuthOptional = "optional" // 请求但不强制，提供时必须通过校验
+	ClientAuthRequired = "required" // 所有 TLS 连接都必须提供有效的客户端证书
+)
+
+// ServiceIdentity 机器客户端的服务身份：客户端证书的主题或 SAN 命中任一规则即视为该身份，
+// 仅能访问 Permissions 列出的接口
+type ServiceIdentity struct {
+	Name string `mapstructure:"name" json:"name"`
+	// Subjects 证书主题，完整 DN（如 CN=ci-bot,OU=Automation,O=Example）或仅 CN（如 CN=ci-bot）
+	Subjects []string `mapstructure:"subjects" json:"subjects"`
+	// SANs 证书的 DNS、URI（如 spiffe://example.com/ci）、邮箱或 IP 类型 SAN
+	SANs []string `mapstructure:"sans" json:"sans"`
+	// TenantID 身份所属租户，默认平台租户（1）；平台租户的身份可通过平台管理接口的超级管理员校验
+	TenantID uint `mapstructure:"tenant_id" json:"tenant_id"`
+	// Permissions 允许访问的接口，格式为 "<METHOD> <路径>"，METHOD 可为 *，路径以 * 结尾表示前缀匹配，
+	// 如 "GET /api/v1/private/admin/platform/tenant"、"* /api/v1/private/admin/platform/menu*"
+	Permissions []string `mapstructure:"permissions" json:"permissions"`
+}
+
+// PgsqlReplica 只读副本连接配置；未填写的字段沿用主库配置
+type PgsqlReplica struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	DBName   string `mapstructure:"dbname"`
+	SSLMode  string `mapstructure:"sslmode"`
+}
+
+// Redis 部署模式
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// RedisAddrList 返回当前模式下的连接地址（redis.addrs 为空时退回 redis.host）
+func RedisAddrList() []string {
+	if len(RedisAddrs) > 0 {
+		return RedisAddrs
+	}
+	if RedisHost != "" {
+		return []string{RedisHost}
+	}
+	return nil
+}
+
 // page config
 var (
 	DefaultPageSize = 20 // default page size
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/http"
+	"net/netip"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
@@ -24,7 +27,7 @@ func LoadConfig() error {
 	v.AddConfigPath("/etc/http-services/")
 
 	// 支持环境变量覆盖
-	v.SetEnvPrefix("HTTP_SERVICES")
+	v.SetEnvPrefix(EnvPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
@@ -41,7 +44,17 @@ func LoadConfig() error {
 		zap.L().Info("配置文件已加载", zap.String("file", v.ConfigFileUsed()))
 	}
 
-	return applyConfig()
+	refreshSecrets()
+	if err := applyConfig(); err != nil {
+		// 解析失败时优先返回完整的校验结果，一次列出全部问题
+		if verr := Validate(); verr != nil {
+			return verr
+		}
+		return err
+	}
+	snapshotSettings()
+	startupSettings = lastSettings
+	return nil
 }
 
 func setDefaults() {
@@ -50,6 +63,7 @@ func setDefaults() {
 	v.SetDefault("server.max_body_size", "10MB")
 	v.SetDefault("server.max_header_bytes", 1<<20)
 	v.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("server.upgrade_timeout", "30s")
 	v.SetDefault("server.read_timeout", "30s")
 	v.SetDefault("server.write_timeout", "30s")
 	v.SetDefault("server.idle_timeout", "120s")
@@ -63,6 +77,30 @@ func setDefaults() {
 	v.SetDefault("server.enable_tls", false)
 	v.SetDefault("server.tls_cert_file", "")
 	v.SetDefault("server.tls_key_file", "")
+	v.SetDefault("server.enable_http3", false)
+	v.SetDefault("server.http3_port", 0)
+	v.SetDefault("server.enable_h2c", false)
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.client_ip_headers", []string{"X-Forwarded-For", "X-Real-IP"})
+	v.SetDefault("server.proxy_protocol", ProxyProtocolOff)
+	v.SetDefault("server.client_ca_file", "")
+	v.SetDefault("server.client_auth", ClientAuthNone)
+
+	// access log
+	v.SetDefault("access_log.enabled", true)
+	v.SetDefault("access_log.skip_paths", []string{"/api/v1/open/health"})
+	v.SetDefault("access_log.sample_rate", 1.0)
+
+	// cors
+	v.SetDefault("cors.allow_origins", []string{"*"})
+	v.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	v.SetDefault("cors.allow_headers", []string{"Authorization", "Content-Type", "X-Request-ID"})
+	v.SetDefault("cors.expose_headers", []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"})
+	v.SetDefault("cors.allow_credentials", false)
+	v.SetDefault("cors.max_age", "48h")
+
+	// ip access
+	v.SetDefault("ip_access.enabled", true)
 
 	// jwt
 	v.SetDefault("jwt.expiration", "12h")
@@ -71,10 +109,44 @@ func setDefaults() {
 	v.SetDefault("log.max_size", 50)
 	v.SetDefault("log.max_backups", 3)
 	v.SetDefault("log.max_age", 30)
+	v.SetDefault("log.sampling.enabled", true)
+	v.SetDefault("log.sampling.tick", "1s")
+	v.SetDefault("log.sampling.initial", 4)
+	v.SetDefault("log.sampling.thereafter", 1)
+	v.SetDefault("log.sinks", []string{"file"})
+	v.SetDefault("log.buffer_size", 10000)
+	v.SetDefault("log.syslog.network", "udp")
+	v.SetDefault("log.syslog.address", "127.0.0.1:514")
+	v.SetDefault("log.syslog.app_name", "api-server")
+	v.SetDefault("log.syslog.facility", 16)
+	v.SetDefault("log.http.url", "")
+	v.SetDefault("log.http.format", "loki")
+	v.SetDefault("log.http.batch_size", 500)
+	v.SetDefault("log.http.flush_interval", "2s")
+	v.SetDefault("log.http.timeout", "5s")
 
 	// redis
+	v.SetDefault("redis.mode", RedisModeStandalone)
 	v.SetDefault("redis.host", "")
+	v.SetDefault("redis.addrs", []string{})
+	v.SetDefault("redis.master_name", "")
+	v.SetDefault("redis.username", "")
 	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.db", 0)
+	v.SetDefault("redis.sentinel_username", "")
+	v.SetDefault("redis.sentinel_password", "")
+	v.SetDefault("redis.pool_size", 100)
+	v.SetDefault("redis.min_idle_conns", 50)
+	v.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.read_timeout", "3s")
+	v.SetDefault("redis.write_timeout", "3s")
+	v.SetDefault("redis.pool_timeout", "4s")
+	v.SetDefault("redis.tls.enabled", false)
+	v.SetDefault("redis.tls.ca_file", "")
+	v.SetDefault("redis.tls.cert_file", "")
+	v.SetDefault("redis.tls.key_file", "")
+	v.SetDefault("redis.tls.server_name", "")
+	v.SetDefault("redis.tls.insecure_skip_verify", false)
 
 	// postgres
 	v.SetDefault("postgres.host", "")
@@ -84,114 +156,434 @@ func setDefaults() {
 	v.SetDefault("postgres.port", 5432)
 	v.SetDefault("postgres.sslmode", "disable")
 	v.SetDefault("postgres.timezone", "Asia/Shanghai")
+	v.SetDefault("postgres.max_open_conns", 100)
+	v.SetDefault("postgres.max_idle_conns", 10)
+	v.SetDefault("postgres.conn_max_lifetime", "1h")
+	v.SetDefault("postgres.conn_max_idle_time", "0s")
+	v.SetDefault("postgres.statement_timeout", "10s")
+	v.SetDefault("postgres.slow_threshold", "200ms")
+	v.SetDefault("postgres.redact_params", false)
 
 	// admin
 	v.SetDefault("admin.password", "")
 	v.SetDefault("admin.salt", "")
 
 	// rate limit
+	v.SetDefault("rate_limit.backend", "local")
+	v.SetDefault("rate_limit.redis_prefix", "ratelimit:")
 	v.SetDefault("rate_limit.login_rate_per_minute", 5)
 	v.SetDefault("rate_limit.login_burst_size", 10)
 	v.SetDefault("rate_limit.general_rate_per_sec", 100)
 	v.SetDefault("rate_limit.general_burst_size", 200)
 
+	// user cache
+	v.SetDefault("user_cache.reconcile_interval", "6h")
+
 	// tenant
 	v.SetDefault("tenant.min_query_length", 3)
 	v.SetDefault("tenant.default_code", "platform")
+
+	// secrets
+	v.SetDefault("secrets.forbid_plaintext", false)
+	v.SetDefault("secrets.vault.address", "")
+	v.SetDefault("secrets.vault.token", "")
+	v.SetDefault("secrets.vault.namespace", "")
+	v.SetDefault("secrets.vault.mount", "secret")
+	v.SetDefault("secrets.vault.kv_version", 2)
+	v.SetDefault("secrets.vault.timeout", "5s")
+	for _, key := range secretFileKeys {
+		v.SetDefault(key+"_file", "")
+	}
 }
 
 func applyConfig() error {
+	// 请求处理路径读取的配置先写入新快照，末尾整体发布
+	rt := &Runtime{}
+
 	// server
 	ListenPort = v.GetInt("server.port")
 
-	sizeStr := v.GetString("server.max_body_size")
+	sizeStr := getString("server.max_body_size")
 	size, err := parseSize(sizeStr)
 	if err != nil {
 		return fmt.Errorf("invalid max_body_size: %w", err)
 	}
-	MaxBodySize = size
+	rt.MaxBodySize = size
 
 	MaxHeaderBytes = v.GetInt("server.max_header_bytes")
 	ShutdownTimeout = v.GetDuration("server.shutdown_timeout")
+	UpgradeTimeout = v.GetDuration("server.upgrade_timeout")
 	ReadTimeout = v.GetDuration("server.read_timeout")
 	WriteTimeout = v.GetDuration("server.write_timeout")
 	IdleTimeout = v.GetDuration("server.idle_timeout")
-	EnableRateLimit = v.GetBool("server.enable_rate_limit")
-	GlobalRateLimit = v.GetInt("server.global_rate_limit")
-	GlobalRateBurst = v.GetInt("server.global_rate_burst")
+	rt.EnableRateLimit = v.GetBool("server.enable_rate_limit")
+	rt.GlobalRateLimit = v.GetInt("server.global_rate_limit")
+	rt.GlobalRateBurst = v.GetInt("server.global_rate_burst")
 
 	// pid 文件（相对路径基于程序所在目录）
-	PidFile = v.GetString("server.pid_file")
+	PidFile = getString("server.pid_file")
 	if PidFile != "" && !filepath.IsAbs(PidFile) {
 		PidFile = filepath.Join(AbsPath, PidFile)
 	}
 
 	EnableACME = v.GetBool("server.enable_acme")
-	ACMEDomain = v.GetString("server.acme_domain")
-	ACMECacheDir = v.GetString("server.acme_cache_dir")
+	ACMEDomain = getString("server.acme_domain")
+	ACMECacheDir = getString("server.acme_cache_dir")
 	EnableTLS = v.GetBool("server.enable_tls")
-	TLSCertFile = v.GetString("server.tls_cert_file")
-	TLSKeyFile = v.GetString("server.tls_key_file")
+	TLSCertFile = getString("server.tls_cert_file")
+	TLSKeyFile = getString("server.tls_key_file")
+	EnableHTTP3 = v.GetBool("server.enable_http3")
+	HTTP3Port = v.GetInt("server.http3_port")
+	if HTTP3Port == 0 {
+		HTTP3Port = ListenPort
+	}
+	EnableH2C = v.GetBool("server.enable_h2c")
+	proxies, err := parseTrustedProxies(v.GetStringSlice("server.trusted_proxies"))
+	if err != nil {
+		return fmt.Errorf("invalid server.trusted_proxies: %w", err)
+	}
+	rt.TrustedProxies = proxies
+	headers, err := parseClientIPHeaders(v.GetStringSlice("server.client_ip_headers"))
+	if err != nil {
+		return fmt.Errorf("invalid server.client_ip_headers: %w", err)
+	}
+	rt.ClientIPHeaders = headers
+	ProxyProtocol = strings.ToLower(getString("server.proxy_protocol"))
+	ClientCAFile = getString("server.client_ca_file")
+	if ClientCAFile != "" && !filepath.IsAbs(ClientCAFile) {
+		ClientCAFile = filepath.Join(AbsPath, ClientCAFile)
+	}
+	ClientAuth = strings.ToLower(getString("server.client_auth"))
+	identities, err := parseServiceIdentities()
+	if err != nil {
+		return fmt.Errorf("invalid service_identities: %w", err)
+	}
+	rt.ServiceIdentities = identities
+
+	// access log
+	rt.AccessLogEnabled = v.GetBool("access_log.enabled")
+	rt.AccessLogSkipPaths = v.GetStringSlice("access_log.skip_paths")
+	rt.AccessLogSampleRate = v.GetFloat64("access_log.sample_rate")
+
+	// cors
+	rt.CORSAllowOrigins = v.GetStringSlice("cors.allow_origins")
+	rt.CORSAllowMethods = v.GetStringSlice("cors.allow_methods")
+	rt.CORSAllowHeaders = v.GetStringSlice("cors.allow_headers")
+	rt.CORSExposeHeaders = v.GetStringSlice("cors.expose_headers")
+	rt.CORSAllowCredentials = v.GetBool("cors.allow_credentials")
+	rt.CORSMaxAge = v.GetDuration("cors.max_age")
+
+	// ip access
+	rt.IPAccessEnabled = v.GetBool("ip_access.enabled")
 
 	// jwt
-	JWTKey = v.GetString("jwt.key")
-	JWTExpiration = v.GetDuration("jwt.expiration")
+	rt.JWTKey = getString("jwt.key")
+	rt.JWTExpiration = v.GetDuration("jwt.expiration")
 
 	// log
 	LogMaxSize = v.GetInt("log.max_size")
 	LogMaxBackups = v.GetInt("log.max_backups")
 	LogMaxAge = v.GetInt("log.max_age")
+	LogLevels = parseLogLevels()
+	LogSamplingEnabled = v.GetBool("log.sampling.enabled")
+	LogSamplingTick = v.GetDuration("log.sampling.tick")
+	LogSamplingInitial = v.GetInt("log.sampling.initial")
+	LogSamplingThereafter = v.GetInt("log.sampling.thereafter")
+	LogSinks = v.GetStringSlice("log.sinks")
+	LogBufferSize = v.GetInt("log.buffer_size")
+	LogSyslogNetwork = getString("log.syslog.network")
+	LogSyslogAddress = getString("log.syslog.address")
+	LogSyslogAppName = getString("log.syslog.app_name")
+	LogSyslogFacility = v.GetInt("log.syslog.facility")
+	LogHTTPURL = getString("log.http.url")
+	LogHTTPFormat = strings.ToLower(getString("log.http.format"))
+	LogHTTPBatchSize = v.GetInt("log.http.batch_size")
+	LogHTTPFlushInterval = v.GetDuration("log.http.flush_interval")
+	LogHTTPTimeout = v.GetDuration("log.http.timeout")
+	LogHTTPLabels = v.GetStringMapString("log.http.labels")
+	LogHTTPHeaders = v.GetStringMapString("log.http.headers")
 
 	// redis
-	RedisHost = v.GetString("redis.host")
-	RedisPassword = v.GetString("redis.password")
+	if err := applyRedisConfig(); err != nil {
+		return err
+	}
 
 	// postgres -> DSN
-	host := v.GetString("postgres.host")
-	user := v.GetString("postgres.user")
-	password := v.GetString("postgres.password")
-	dbname := v.GetString("postgres.dbname")
-	port := v.GetInt("postgres.port")
-	sslmode := v.GetString("postgres.sslmode")
-	timezone := v.GetString("postgres.timezone")
-
-	if host != "" {
-		PgsqlDSN = fmt.Sprintf(
-			"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
-			host,
-			user,
-			password,
-			dbname,
-			port,
-			sslmode,
-			timezone,
-		)
+	primary := PgsqlReplica{
+		Host:     getString("postgres.host"),
+		Port:     v.GetInt("postgres.port"),
+		User:     getString("postgres.user"),
+		Password: getString("postgres.password"),
+		DBName:   getString("postgres.dbname"),
+		SSLMode:  getString("postgres.sslmode"),
+	}
+	timezone := getString("postgres.timezone")
+	PgsqlStmtTimeout = v.GetDuration("postgres.statement_timeout")
+	if primary.Host != "" {
+		PgsqlDSN = buildPgsqlDSN(primary, timezone)
 	} else {
 		PgsqlDSN = ""
 	}
+	replicas, err := parsePgsqlReplicas(primary, timezone)
+	if err != nil {
+		return fmt.Errorf("invalid postgres.replicas: %w", err)
+	}
+	PgsqlReplicaDSNs = replicas
+	PgsqlMaxOpenConns = v.GetInt("postgres.max_open_conns")
+	PgsqlMaxIdleConns = v.GetInt("postgres.max_idle_conns")
+	PgsqlConnMaxLifetime = v.GetDuration("postgres.conn_max_lifetime")
+	PgsqlConnMaxIdleTime = v.GetDuration("postgres.conn_max_idle_time")
+	PgsqlSlowThreshold = v.GetDuration("postgres.slow_threshold")
+	PgsqlRedactParams = v.GetBool("postgres.redact_params")
 
 	// admin
-	AdminPassword = v.GetString("admin.password")
-	PWDSalt = v.GetString("admin.salt")
+	AdminPassword = getString("admin.password")
+	PWDSalt = getString("admin.salt")
 
 	// rate limit
-	LoginRatePerMinute = v.GetInt("rate_limit.login_rate_per_minute")
-	LoginBurstSize = v.GetInt("rate_limit.login_burst_size")
-	GeneralRatePerSec = v.GetInt("rate_limit.general_rate_per_sec")
-	GeneralBurstSize = v.GetInt("rate_limit.general_burst_size")
+	rt.RateLimitBackend = strings.ToLower(getString("rate_limit.backend"))
+	rt.RateLimitRedisPrefix = getString("rate_limit.redis_prefix")
+	policies, err := parseRateLimitPolicies()
+	if err != nil {
+		return fmt.Errorf("invalid rate_limit.policies: %w", err)
+	}
+	rt.RateLimitPolicies = policies
+	rt.LoginRatePerMinute = v.GetInt("rate_limit.login_rate_per_minute")
+	rt.LoginBurstSize = v.GetInt("rate_limit.login_burst_size")
+	rt.GeneralRatePerSec = v.GetInt("rate_limit.general_rate_per_sec")
+	rt.GeneralBurstSize = v.GetInt("rate_limit.general_burst_size")
+
+	// user cache
+	UserCacheReconcileInterval = v.GetDuration("user_cache.reconcile_interval")
+	if UserCacheReconcileInterval <= 0 {
+		UserCacheReconcileInterval = 6 * time.Hour
+	}
 
 	// tenant
-	TenantMinQueryLength = v.GetInt("tenant.min_query_length")
-	DefaultTenantCode = v.GetString("tenant.default_code")
+	rt.TenantMinQueryLength = v.GetInt("tenant.min_query_length")
+	DefaultTenantCode = getString("tenant.default_code")
 	if DefaultTenantCode == "" {
 		DefaultTenantCode = "platform"
 	}
 
+	// 全部解析成功后整体发布，请求处理路径不会读到部分更新的配置
+	StoreRuntime(rt)
 	return nil
 }
 
-// WatchConfig 监听配置变化
-func WatchConfig(onChange func()) {
+// applyRedisConfig 读取 redis 配置并校验模式相关的必填项
+func applyRedisConfig() error {
+	mode := strings.ToLower(getString("redis.mode"))
+	if mode == "" {
+		mode = RedisModeStandalone
+	}
+	switch mode {
+	case RedisModeStandalone, RedisModeSentinel, RedisModeCluster:
+	default:
+		return fmt.Errorf("invalid redis.mode: %q (standalone/sentinel/cluster)", mode)
+	}
+	masterName := getString("redis.master_name")
+	if mode == RedisModeSentinel && masterName == "" {
+		return fmt.Errorf("redis.master_name is required in sentinel mode")
+	}
+	db := v.GetInt("redis.db")
+	if db < 0 {
+		return fmt.Errorf("invalid redis.db: %d", db)
+	}
+	if mode == RedisModeCluster && db != 0 {
+		return fmt.Errorf("redis.db must be 0 in cluster mode")
+	}
+	// 证书路径支持相对路径（相对 AbsPath）
+	certFile := resolvePath(getString("redis.tls.cert_file"))
+	keyFile := resolvePath(getString("redis.tls.key_file"))
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("redis.tls.cert_file and redis.tls.key_file must be set together")
+	}
+
+	RedisMode = mode
+	RedisHost = getString("redis.host")
+	RedisAddrs = v.GetStringSlice("redis.addrs")
+	RedisMasterName = masterName
+	RedisUsername = getString("redis.username")
+	RedisPassword = getString("redis.password")
+	RedisDB = db
+	RedisSentinelUser = getString("redis.sentinel_username")
+	RedisSentinelPass = getString("redis.sentinel_password")
+	RedisPoolSize = v.GetInt("redis.pool_size")
+	RedisMinIdleConns = v.GetInt("redis.min_idle_conns")
+	RedisDialTimeout = v.GetDuration("redis.dial_timeout")
+	RedisReadTimeout = v.GetDuration("redis.read_timeout")
+	RedisWriteTimeout = v.GetDuration("redis.write_timeout")
+	RedisPoolTimeout = v.GetDuration("redis.pool_timeout")
+
+	RedisTLSEnabled = v.GetBool("redis.tls.enabled")
+	RedisTLSCAFile = resolvePath(getString("redis.tls.ca_file"))
+	RedisTLSCertFile = certFile
+	RedisTLSKeyFile = keyFile
+	RedisTLSServerName = getString("redis.tls.server_name")
+	RedisTLSInsecureSkipVerify = v.GetBool("redis.tls.insecure_skip_verify")
+	return nil
+}
+
+// resolvePath 将相对路径转换为基于 AbsPath 的绝对路径，空字符串原样返回
+func resolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(AbsPath, path)
+}
+
+// buildPgsqlDSN 拼接 PostgreSQL DSN
+// 配置了 statement_timeout 时作为连接参数下发，由服务端终止超时的语句
+func buildPgsqlDSN(c PgsqlReplica, timezone string) string {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		c.Host,
+		c.User,
+		c.Password,
+		c.DBName,
+		c.Port,
+		c.SSLMode,
+		timezone,
+	)
+	if PgsqlStmtTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", PgsqlStmtTimeout.Milliseconds())
+	}
+	return dsn
+}
+
+// parsePgsqlReplicas 解析 postgres.replicas，未填写的字段沿用主库配置
+func parsePgsqlReplicas(primary PgsqlReplica, timezone string) ([]string, error) {
+	var replicas []PgsqlReplica
+	if err := v.UnmarshalKey("postgres.replicas", &replicas); err != nil {
+		return nil, err
+	}
+	dsns := make([]string, 0, len(replicas))
+	for i, r := range replicas {
+		// 副本的用户名、密码同样支持 ${provider:ref} 引用
+		if s, ok := secrets.values[fmt.Sprintf("postgres.replicas.%d.user", i)]; ok {
+			r.User = s
+		}
+		if s, ok := secrets.values[fmt.Sprintf("postgres.replicas.%d.password", i)]; ok {
+			r.Password = s
+		}
+		if r.Host == "" {
+			return nil, fmt.Errorf("replica #%d: host is required", i+1)
+		}
+		if r.Port == 0 {
+			r.Port = primary.Port
+		}
+		if r.User == "" {
+			r.User = primary.User
+		}
+		if r.Password == "" {
+			r.Password = primary.Password
+		}
+		if r.DBName == "" {
+			r.DBName = primary.DBName
+		}
+		if r.SSLMode == "" {
+			r.SSLMode = primary.SSLMode
+		}
+		dsns = append(dsns, buildPgsqlDSN(r, timezone))
+	}
+	return dsns, nil
+}
+
+// parseLogLevels 解析 log.level：既支持单个字符串（对所有 logger 生效），
+// 也支持按 logger 名称配置的映射（default/business/gin/gorm）
+func parseLogLevels() map[string]string {
+	levels := map[string]string{}
+	switch raw := v.Get("log.level").(type) {
+	case nil:
+	case string:
+		if raw != "" {
+			levels["default"] = raw
+		}
+	default:
+		for name, level := range v.GetStringMapString("log.level") {
+			levels[strings.ToLower(name)] = level
+		}
+	}
+	return levels
+}
+
+// parseRateLimitPolicies 解析 rate_limit.policies（键为路由组名称）
+func parseRateLimitPolicies() (map[string]RateLimitPolicy, error) {
+	policies := map[string]RateLimitPolicy{}
+	if err := v.UnmarshalKey("rate_limit.policies", &policies); err != nil {
+		return nil, err
+	}
+	result := make(map[string]RateLimitPolicy, len(policies))
+	for group, policy := range policies {
+		if policy.Tenant.Enabled() && policy.Tenant.Burst <= 0 {
+			policy.Tenant.Burst = int(policy.Tenant.Rate) + 1
+		}
+		if policy.User.Enabled() && policy.User.Burst <= 0 {
+			policy.User.Burst = int(policy.User.Rate) + 1
+		}
+		result[strings.ToLower(group)] = policy
+	}
+	return result, nil
+}
+
+// parseTrustedProxies 解析可信代理列表，支持 CIDR（10.0.0.0/8）与单个 IP
+func parseTrustedProxies(items []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "/") {
+			p, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// parseClientIPHeaders 校验并规范化客户端 IP 请求头名称，保持配置顺序
+func parseClientIPHeaders(items []string) ([]string, error) {
+	headers := make([]string, 0, len(items))
+	for _, item := range items {
+		name := http.CanonicalHeaderKey(strings.TrimSpace(item))
+		switch name {
+		case "X-Forwarded-For", "X-Real-Ip", "Forwarded":
+			headers = append(headers, name)
+		default:
+			return nil, fmt.Errorf("unsupported header %q, want X-Forwarded-For, X-Real-IP or Forwarded", item)
+		}
+	}
+	return headers, nil
+}
+
+// parseServiceIdentities 解析 service_identities，未填写 tenant_id 的身份归属平台租户
+func parseServiceIdentities() ([]ServiceIdentity, error) {
+	var identities []ServiceIdentity
+	if err := v.UnmarshalKey("service_identities", &identities); err != nil {
+		return nil, err
+	}
+	for i := range identities {
+		if identities[i].TenantID == 0 {
+			identities[i].TenantID = 1
+		}
+	}
+	return identities, nil
+}
+
+// WatchConfig 监听配置变化；onChange 收到本次变更中已生效与需重启才能生效的配置键
+func WatchConfig(onChange func(ReloadResult)) {
 	if v == nil {
 		return
 	}
@@ -202,14 +594,27 @@ func WatchConfig(onChange func()) {
 			zap.String("op", e.Op.String()),
 		)
 
+		// 重新读取密钥文件与外部密钥存储，校验不通过时保留当前配置（含已解析的密钥），避免部分生效
+		prevSecrets := secrets
+		refreshSecrets()
+		if err := Validate(); err != nil {
+			secrets = prevSecrets
+			zap.L().Error("配置热加载失败，继续使用当前配置", zap.Error(err))
+			return
+		}
+
 		// 重新应用配置
 		if err := applyConfig(); err != nil {
 			zap.L().Error("配置热加载失败", zap.Error(err))
 			return
 		}
 
+		prev := lastSettings
+		snapshotSettings()
+		result := diffSettings(prev, lastSettings)
+
 		if onChange != nil {
-			onChange()
+			onChange(result)
 		}
 
 		zap.L().Info("配置热加载完成")
@@ -2,8 +2,11 @@ package config
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/spf13/viper"
 )
 
 func TestParseSize(t *testing.T) {
@@ -76,12 +79,12 @@ func TestApplyConfig(t *testing.T) {
 		t.Fatalf("ListenPort = %d, want 8080", ListenPort)
 	}
 
-	if MaxBodySize != 10*1024*1024 {
-		t.Fatalf("MaxBodySize = %d, want %d", MaxBodySize, 10*1024*1024)
+	if got := Current().MaxBodySize; got != 10*1024*1024 {
+		t.Fatalf("MaxBodySize = %d, want %d", got, 10*1024*1024)
 	}
 
-	if JWTExpiration != 12*time.Hour {
-		t.Fatalf("JWTExpiration = %v, want %v", JWTExpiration, 12*time.Hour)
+	if got := Current().JWTExpiration; got != 12*time.Hour {
+		t.Fatalf("JWTExpiration = %v, want %v", got, 12*time.Hour)
 	}
 
 	if filepath.Base(PidFile) != "api-server.pid" {
@@ -103,8 +106,8 @@ func TestLoadConfigWithEnv(t *testing.T) {
 		t.Fatalf("ListenPort = %d, want 9090 (from env)", ListenPort)
 	}
 
-	if JWTExpiration != 24*time.Hour {
-		t.Fatalf("JWTExpiration = %v, want 24h (from env)", JWTExpiration)
+	if got := Current().JWTExpiration; got != 24*time.Hour {
+		t.Fatalf("JWTExpiration = %v, want 24h (from env)", got)
 	}
 
 	if PidFile != pidPath {
@@ -122,3 +125,121 @@ func TestGetViper(t *testing.T) {
 		t.Fatalf("GetViper() did not return the expected viper instance")
 	}
 }
+
+func TestParseRateLimitPolicies(t *testing.T) {
+	v = viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(strings.NewReader(`
+rate_limit:
+  policies:
+    System:
+      tenant: { rate: 100, burst: 200 }
+      user: { rate: 5 }
+`))
+	if err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+
+	policies, err := parseRateLimitPolicies()
+	if err != nil {
+		t.Fatalf("parseRateLimitPolicies() error = %v", err)
+	}
+	got, ok := policies["system"]
+	if !ok {
+		t.Fatalf("policy keys should be lower-cased, got %v", policies)
+	}
+	if got.Tenant.Rate != 100 || got.Tenant.Burst != 200 {
+		t.Fatalf("tenant rule = %+v", got.Tenant)
+	}
+	// 未配置 burst 时按 rate 补齐
+	if got.User.Rate != 5 || got.User.Burst != 6 {
+		t.Fatalf("user rule = %+v", got.User)
+	}
+}
+
+func TestApplyRedisConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{"standalone default", "redis:\n  host: 127.0.0.1:6379\n", false},
+		{"sentinel", "redis:\n  mode: Sentinel\n  master_name: mymaster\n  addrs: [a:26379, b:26379]\n", false},
+		{"sentinel without master", "redis:\n  mode: sentinel\n  addrs: [a:26379]\n", true},
+		{"cluster with db", "redis:\n  mode: cluster\n  db: 1\n  addrs: [a:7000]\n", true},
+		{"unknown mode", "redis:\n  mode: ring\n", true},
+		{"cert without key", "redis:\n  tls:\n    cert_file: client.pem\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v = viper.New()
+			v.SetConfigType("yaml")
+			setDefaults()
+			if err := v.ReadConfig(strings.NewReader(tt.yaml)); err != nil {
+				t.Fatalf("ReadConfig() error = %v", err)
+			}
+			err := applyRedisConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyRedisConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 校验失败时不应覆盖上一次有效配置（sentinel 用例）
+	if RedisMode != RedisModeSentinel || len(RedisAddrList()) != 2 || RedisPoolSize != 100 {
+		t.Fatalf("last valid config not retained: mode=%s addrs=%v pool=%d", RedisMode, RedisAddrList(), RedisPoolSize)
+	}
+}
+
+func TestParsePgsqlReplicas(t *testing.T) {
+	v = viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(strings.NewReader(`
+postgres:
+  replicas:
+    - host: 10.0.0.2
+    - host: 10.0.0.3
+      port: 6432
+      user: reader
+`))
+	if err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+
+	prevTimeout := PgsqlStmtTimeout
+	PgsqlStmtTimeout = 0
+	t.Cleanup(func() { PgsqlStmtTimeout = prevTimeout })
+
+	primary := PgsqlReplica{Host: "10.0.0.1", Port: 5432, User: "postgres", Password: "secret", DBName: "server", SSLMode: "disable"}
+	dsns, err := parsePgsqlReplicas(primary, "Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("parsePgsqlReplicas() error = %v", err)
+	}
+	want := []string{
+		"host=10.0.0.2 user=postgres password=secret dbname=server port=5432 sslmode=disable TimeZone=Asia/Shanghai",
+		"host=10.0.0.3 user=reader password=secret dbname=server port=6432 sslmode=disable TimeZone=Asia/Shanghai",
+	}
+	if len(dsns) != len(want) {
+		t.Fatalf("parsePgsqlReplicas() = %v, want %v", dsns, want)
+	}
+	for i := range want {
+		if dsns[i] != want[i] {
+			t.Fatalf("replica #%d dsn = %q, want %q", i+1, dsns[i], want[i])
+		}
+	}
+
+	v = viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader("postgres:\n  replicas:\n    - port: 5433\n")); err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+	if _, err := parsePgsqlReplicas(primary, "UTC"); err == nil {
+		t.Fatal("replica without host should fail")
+	}
+
+	// statement_timeout 以毫秒作为连接参数下发
+	PgsqlStmtTimeout = 5 * time.Second
+	if dsn := buildPgsqlDSN(primary, "UTC"); !strings.HasSuffix(dsn, " statement_timeout=5000") {
+		t.Fatalf("buildPgsqlDSN() = %q, want statement_timeout=5000", dsn)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import "strings"
+
+// MaskedValue 敏感配置项脱敏后的占位值
+const MaskedValue = "******"
+
+// secretKeyNames 视为敏感信息的配置键名（取最后一级键名比较）
+var secretKeyNames = map[string]struct{}{
+	"password": {},
+	"key":      {},
+	"salt":     {},
+	"secret":   {},
+	"token":    {},
+	"dsn":      {},
+}
+
+// secretKeySuffixes 视为敏感信息的配置键名后缀
+var secretKeySuffixes = []string{"_password", "_secret", "_token"}
+
+// IsSecretKey 判断配置键（如 jwt.key、postgres.password）是否为敏感项
+func IsSecretKey(key string) bool {
+	name := strings.ToLower(key)
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if _, ok := secretKeyNames[name]; ok {
+		return true
+	}
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskedSettings 返回当前生效的完整配置（文件 + 环境变量 + 默认值合并后），敏感项已脱敏。
+// 敏感项为空时保留空字符串，便于排查“是否已配置”。
+func MaskedSettings() map[string]interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	return maskSettings("", v.AllSettings())
+}
+
+func maskSettings(prefix string, settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	for k, val := range settings {
+		fullKey := k
+		if prefix != "" {
+			fullKey = prefix + "." + k
+		}
+		switch typed := val.(type) {
+		case map[string]interface{}:
+			result[k] = maskSettings(fullKey, typed)
+		case []interface{}:
+			// 列表中的对象（如 postgres.replicas）同样需要脱敏
+			items := make([]interface{}, len(typed))
+			for i, item := range typed {
+				if m, ok := item.(map[string]interface{}); ok {
+					items[i] = maskSettings(fullKey, m)
+				} else {
+					items[i] = item
+				}
+			}
+			result[k] = items
+		default:
+			if IsSecretKey(fullKey) {
+				if s, ok := val.(string); ok && s == "" {
+					result[k] = ""
+				} else {
+					result[k] = MaskedValue
+				}
+				continue
+			}
+			result[k] = val
+		}
+	}
+	return result
+}
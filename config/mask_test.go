@@ -0,0 +1,68 @@
+package config
+
+import "testing"
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"jwt.key", true},
+		{"postgres.password", true},
+		{"redis.password", true},
+		{"admin.salt", true},
+		{"vault.client_token", true},
+		{"server.tls_key_file", false},
+		{"server.port", false},
+		{"log.max_size", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSecretKey(tt.key); got != tt.want {
+				t.Fatalf("IsSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"jwt": map[string]interface{}{
+			"key":        "a-very-secret-key",
+			"expiration": "12h",
+		},
+		"redis": map[string]interface{}{
+			"host":     "127.0.0.1:6379",
+			"password": "",
+		},
+		"postgres": map[string]interface{}{
+			"replicas": []interface{}{
+				map[string]interface{}{"host": "10.0.0.2", "password": "replica-secret"},
+			},
+		},
+	}
+
+	got := maskSettings("", settings)
+
+	jwt := got["jwt"].(map[string]interface{})
+	if jwt["key"] != MaskedValue {
+		t.Fatalf("jwt.key = %v, want %v", jwt["key"], MaskedValue)
+	}
+	if jwt["expiration"] != "12h" {
+		t.Fatalf("jwt.expiration = %v, want 12h", jwt["expiration"])
+	}
+
+	redis := got["redis"].(map[string]interface{})
+	if redis["password"] != "" {
+		t.Fatalf("empty redis.password should stay empty, got %v", redis["password"])
+	}
+	if redis["host"] != "127.0.0.1:6379" {
+		t.Fatalf("redis.host = %v, want 127.0.0.1:6379", redis["host"])
+	}
+
+	replica := got["postgres"].(map[string]interface{})["replicas"].([]interface{})[0].(map[string]interface{})
+	if replica["password"] != MaskedValue || replica["host"] != "10.0.0.2" {
+		t.Fatalf("postgres.replicas[0] = %v, want masked password", replica)
+	}
+}
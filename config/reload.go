@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// restartRequiredKeys 只在启动时读取、热加载后不会生效的配置（键名或配置段前缀）。
+// 监听端口、TLS 模式与 http.Server 超时由监听器持有；Redis/PostgreSQL 连接池、日志输出目标
+// 与采样在启动时创建；定时任务的周期在注册时确定。
+var restartRequiredKeys = []string{
+	"server.port",
+	"server.max_header_bytes",
+	"server.read_timeout",
+	"server.write_timeout",
+	"server.idle_timeout",
+	"server.pid_file",
+	"server.enable_acme",
+	"server.acme_domain",
+	"server.acme_cache_dir",
+	"server.enable_tls",
+	"server.tls_cert_file",
+	"server.tls_key_file",
+	"server.enable_http3",
+	"server.http3_port",
+	"server.enable_h2c",
+	"server.proxy_protocol",
+	"server.client_ca_file",
+	"server.client_auth",
+	"log.max_size",
+	"log.max_backups",
+	"log.max_age",
+	"log.sampling",
+	"log.sinks",
+	"log.buffer_size",
+	"log.syslog",
+	"log.http",
+	"redis",
+	"postgres",
+	"user_cache.reconcile_interval",
+}
+
+// ReloadResult 一次配置热加载的结果
+type ReloadResult struct {
+	Changed         []string // 已生效的配置键
+	RestartRequired []string // 已修改但需重启才能生效的配置键
+}
+
+var (
+	// startupSettings 启动时的配置快照，lastSettings 最近一次成功应用的配置快照（扁平化后的键值）
+	startupSettings map[string]interface{}
+	lastSettings    map[string]interface{}
+)
+
+// RequiresRestart 判断配置键是否需要重启后才能生效
+func RequiresRestart(key string) bool {
+	for _, k := range restartRequiredKeys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingRestart 返回启动后被修改、需重启服务才能生效的配置键；改回启动时的值后不再列出
+func PendingRestart() []string {
+	if startupSettings == nil {
+		return nil
+	}
+	return diffSettings(startupSettings, lastSettings).RestartRequired
+}
+
+// snapshotSettings 记录当前生效的配置快照；解析后的密钥一并记录，以便密钥轮换后热加载能识别变更
+func snapshotSettings() {
+	lastSettings = flattenSettings("", v.AllSettings())
+	for key, val := range secrets.values {
+		lastSettings[key] = val
+	}
+}
+
+// diffSettings 比对新旧配置，按是否需要重启分类返回变更的键（已排序）
+func diffSettings(prev, next map[string]interface{}) ReloadResult {
+	keys := make(map[string]struct{}, len(next))
+	for k := range prev {
+		keys[k] = struct{}{}
+	}
+	for k := range next {
+		keys[k] = struct{}{}
+	}
+
+	var result ReloadResult
+	for k := range keys {
+		if fmt.Sprint(prev[k]) == fmt.Sprint(next[k]) {
+			continue
+		}
+		if RequiresRestart(k) {
+			result.RestartRequired = append(result.RestartRequired, k)
+		} else {
+			result.Changed = append(result.Changed, k)
+		}
+	}
+	sort.Strings(result.Changed)
+	sort.Strings(result.RestartRequired)
+	return result
+}
+
+// flattenSettings 将嵌套配置展开为 "a.b.c" 形式的键
+func flattenSettings(prefix string, settings map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for k, val := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := val.(map[string]interface{}); ok {
+			for nk, nv := range flattenSettings(key, nested) {
+				out[nk] = nv
+			}
+			continue
+		}
+		out[key] = val
+	}
+	return out
+}
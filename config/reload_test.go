@@ -0,0 +1,89 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenSettings(t *testing.T) {
+	got := flattenSettings("", map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080},
+		"log":    map[string]interface{}{"sampling": map[string]interface{}{"tick": "1s"}},
+		"cors":   map[string]interface{}{"allow_origins": []string{"*"}},
+	})
+	want := map[string]interface{}{
+		"server.port":        8080,
+		"log.sampling.tick":  "1s",
+		"cors.allow_origins": []string{"*"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flattenSettings() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffSettings(t *testing.T) {
+	prev := map[string]interface{}{
+		"server.port":                      8080,
+		"server.enable_rate_limit":         false,
+		"server.global_rate_limit":         100,
+		"jwt.expiration":                   "12h",
+		"redis.tls.enabled":                false,
+		"rate_limit.login_rate_per_minute": 5,
+	}
+	next := map[string]interface{}{
+		"server.port":                      9090,
+		"server.enable_rate_limit":         true,
+		"server.global_rate_limit":         100,
+		"jwt.expiration":                   "1h",
+		"redis.tls.enabled":                true,
+		"rate_limit.login_rate_per_minute": 5,
+		"cors.allow_origins":               []string{"https://a.example.com"},
+	}
+	got := diffSettings(prev, next)
+	wantChanged := []string{"cors.allow_origins", "jwt.expiration", "server.enable_rate_limit"}
+	wantRestart := []string{"redis.tls.enabled", "server.port"}
+	if !reflect.DeepEqual(got.Changed, wantChanged) {
+		t.Errorf("Changed = %v, want %v", got.Changed, wantChanged)
+	}
+	if !reflect.DeepEqual(got.RestartRequired, wantRestart) {
+		t.Errorf("RestartRequired = %v, want %v", got.RestartRequired, wantRestart)
+	}
+}
+
+func TestRequiresRestart(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"server.port", true},
+		{"server.enable_tls", true},
+		{"postgres.replicas", true},
+		{"log.sampling.tick", true},
+		{"log.level.gorm", false},
+		{"server.portal", false},
+		{"server.max_body_size", false},
+		{"rate_limit.policies.admin.tenant.rate", false},
+	}
+	for _, tt := range tests {
+		if got := RequiresRestart(tt.key); got != tt.want {
+			t.Errorf("RequiresRestart(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPendingRestart(t *testing.T) {
+	prevStartup, prevLast := startupSettings, lastSettings
+	t.Cleanup(func() { startupSettings, lastSettings = prevStartup, prevLast })
+
+	startupSettings = map[string]interface{}{"server.port": 8080, "jwt.expiration": "12h"}
+	lastSettings = map[string]interface{}{"server.port": 9090, "jwt.expiration": "1h"}
+	if got := PendingRestart(); !reflect.DeepEqual(got, []string{"server.port"}) {
+		t.Fatalf("PendingRestart() = %v, want [server.port]", got)
+	}
+
+	// 改回启动时的值后不再需要重启
+	lastSettings = map[string]interface{}{"server.port": 8080, "jwt.expiration": "1h"}
+	if got := PendingRestart(); len(got) != 0 {
+		t.Fatalf("PendingRestart() = %v, want empty", got)
+	}
+}
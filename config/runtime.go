@@ -0,0 +1,66 @@
+package config
+
+import (
+	"net/netip"
+	"sync/atomic"
+	"time"
+)
+
+// Runtime 请求处理路径上读取、支持热加载的配置。
+// 每次加载生成新的快照整体发布，中间件通过 Current 读取，与配置热加载之间没有数据竞争；
+// 快照发布后只读，需要修改时复制一份再调用 StoreRuntime。
+type Runtime struct {
+	// jwt
+	JWTKey        string
+	JWTExpiration time.Duration
+	// server
+	MaxBodySize     int64
+	EnableRateLimit bool
+	GlobalRateLimit int
+	GlobalRateBurst int
+	// 真实客户端 IP
+	TrustedProxies  []netip.Prefix // 可信代理网段，仅来自这些地址的连接才读取转发头与 PROXY 协议头
+	ClientIPHeaders []string       // 解析客户端 IP 的请求头及优先级（X-Forwarded-For / X-Real-IP / Forwarded）
+	// ServiceIdentities 客户端证书到服务身份的映射
+	ServiceIdentities []ServiceIdentity
+	// access log
+	AccessLogEnabled    bool
+	AccessLogSkipPaths  []string
+	AccessLogSampleRate float64
+	// cors
+	CORSAllowOrigins     []string // 允许跨域的来源，支持 https://*.example.com 通配子域名，"*" 表示任意来源
+	CORSAllowMethods     []string
+	CORSAllowHeaders     []string // 预检允许的请求头，"*" 表示回显浏览器请求的全部请求头
+	CORSExposeHeaders    []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration // 预检结果缓存时长
+	// ip access
+	IPAccessEnabled bool // 是否执行 IP 允许/拒绝名单（名单存于数据库），误配置导致无法访问时可临时关闭
+	// rate limit
+	RateLimitBackend     string // local / redis
+	RateLimitRedisPrefix string // Redis 限流键前缀
+	// RateLimitPolicies 按路由组（system/platform）配置的租户级、用户级限流策略
+	RateLimitPolicies  map[string]RateLimitPolicy
+	LoginRatePerMinute int
+	LoginBurstSize     int
+	GeneralRatePerSec  int
+	GeneralBurstSize   int
+	// tenant
+	TenantMinQueryLength int
+}
+
+var runtimeConfig atomic.Pointer[Runtime]
+
+func init() {
+	runtimeConfig.Store(&Runtime{})
+}
+
+// Current 返回当前生效的运行时配置；同一请求内应只读取一次，保证各项设置来自同一份快照
+func Current() *Runtime {
+	return runtimeConfig.Load()
+}
+
+// StoreRuntime 发布新的运行时配置；配置加载与热加载时调用，测试中可用于注入
+func StoreRuntime(r *Runtime) {
+	runtimeConfig.Store(r)
+}
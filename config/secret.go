@@ -0,0 +1,243 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretFileKeys 支持 <key>_file 变体的敏感配置：从挂载的文件（Docker/K8s secret）读取取值，
+// 文件末尾的换行会被去除
+var secretFileKeys = []string{
+	"jwt.key",
+	"postgres.password",
+	"redis.password",
+	"redis.sentinel_password",
+	"admin.password",
+	"admin.salt",
+	"secrets.vault.token",
+}
+
+// secretRefPattern 密钥引用语法 ${<provider>:<ref>}，如 ${file:/run/secrets/jwt_key}、${env:JWT_KEY}、
+// ${vault:api-server/prod#jwt_key}；可以是完整取值，也可以嵌在字符串中
+var secretRefPattern = regexp.MustCompile(`\$\{([a-z][a-z0-9_]*):([^}]*)\}`)
+
+// SecretResolver 按引用解析密钥取值的提供者
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc 函数形式的 SecretResolver
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	customResolversMu sync.RWMutex
+	customResolvers   = map[string]SecretResolver{}
+)
+
+// RegisterSecretResolver 注册自定义密钥提供者（需在 LoadConfig 之前调用），
+// 之后可在配置中以 ${<name>:<ref>} 引用；同名覆盖内置的 file/env/vault
+func RegisterSecretResolver(name string, r SecretResolver) {
+	customResolversMu.Lock()
+	defer customResolversMu.Unlock()
+	customResolvers[name] = r
+}
+
+// secretState 一次解析的结果：values 为解析后的取值（键为配置键），problems 为解析失败的配置项
+type secretState struct {
+	values   map[string]string
+	problems []Problem
+}
+
+// secrets 当前生效的密钥解析结果，配置加载与热加载时刷新
+var secrets secretState
+
+// getString 读取字符串配置，含密钥引用或 _file 变体时返回解析后的取值
+func getString(key string) string {
+	if s, ok := secrets.values[key]; ok {
+		return s
+	}
+	return v.GetString(key)
+}
+
+// refreshSecrets 重新读取 _file 文件并解析全部密钥引用（包括 Vault 等外部存储）
+func refreshSecrets() {
+	timeout := v.GetDuration("secrets.vault.timeout")
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	secrets = resolveSecrets(ctx)
+}
+
+// resolveSecrets 解析 _file 变体与所有字符串配置中的 ${provider:ref} 引用
+func resolveSecrets(ctx context.Context) secretState {
+	state := secretState{values: map[string]string{}}
+	fail := func(key, format string, args ...interface{}) {
+		state.problems = append(state.problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// 先处理 _file 变体与 Vault 凭据，Vault 提供者依赖解析后的 token
+	for _, key := range secretFileKeys {
+		path := v.GetString(key + "_file")
+		if path == "" {
+			continue
+		}
+		if v.GetString(key) != "" {
+			fail(key+"_file", "不能与 %s 同时配置", key)
+			continue
+		}
+		data, err := os.ReadFile(resolvePath(path))
+		if err != nil {
+			fail(key+"_file", "读取密钥文件失败：%v", err)
+			continue
+		}
+		state.values[key] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	resolvers := secretResolvers(state.values)
+	cache := map[string]string{}
+	resolve := func(key, raw string) {
+		var errs []string
+		out := secretRefPattern.ReplaceAllStringFunc(raw, func(ref string) string {
+			if val, ok := cache[ref]; ok {
+				return val
+			}
+			m := secretRefPattern.FindStringSubmatch(ref)
+			r, ok := resolvers[m[1]]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("未知的密钥提供者 %q", m[1]))
+				return ref
+			}
+			val, err := r.Resolve(ctx, m[2])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("解析 %s 失败：%v", ref, err))
+				return ref
+			}
+			cache[ref] = val
+			return val
+		})
+		if len(errs) > 0 {
+			fail(key, "%s", strings.Join(errs, "；"))
+			return
+		}
+		state.values[key] = out
+	}
+
+	// Vault token 自身可以来自 _file 或 ${file:}/${env:} 引用，需先于其它引用解析
+	token, fromFile := state.values["secrets.vault.token"]
+	if !fromFile {
+		token = v.GetString("secrets.vault.token")
+	}
+	if secretRefPattern.MatchString(token) {
+		resolve("secrets.vault.token", token)
+	}
+	resolvers = secretResolvers(state.values)
+
+	refs := collectSecretRefs("", v.AllSettings())
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "secrets.vault.token" {
+			continue
+		}
+		if _, fromFile := state.values[key]; fromFile {
+			continue
+		}
+		resolve(key, refs[key])
+	}
+	return state
+}
+
+// secretResolvers 返回本次解析可用的提供者；resolved 为已解析的取值（用于读取 Vault token）
+func secretResolvers(resolved map[string]string) map[string]SecretResolver {
+	resolvers := map[string]SecretResolver{
+		"file": SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+			data, err := os.ReadFile(resolvePath(ref))
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimRight(string(data), "\r\n"), nil
+		}),
+		"env": SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+			val, ok := os.LookupEnv(ref)
+			if !ok {
+				return "", fmt.Errorf("环境变量 %s 未设置", ref)
+			}
+			return val, nil
+		}),
+	}
+	if addr := v.GetString("secrets.vault.address"); addr != "" {
+		token := v.GetString("secrets.vault.token")
+		if s, ok := resolved["secrets.vault.token"]; ok {
+			token = s
+		}
+		resolvers["vault"] = &VaultResolver{
+			Address:   addr,
+			Token:     token,
+			Namespace: v.GetString("secrets.vault.namespace"),
+			Mount:     v.GetString("secrets.vault.mount"),
+			KVVersion: v.GetInt("secrets.vault.kv_version"),
+		}
+	} else {
+		resolvers["vault"] = SecretResolverFunc(func(context.Context, string) (string, error) {
+			return "", fmt.Errorf("未配置 secrets.vault.address")
+		})
+	}
+
+	customResolversMu.RLock()
+	defer customResolversMu.RUnlock()
+	for name, r := range customResolvers {
+		resolvers[name] = r
+	}
+	return resolvers
+}
+
+// collectSecretRefs 收集包含 ${provider:ref} 引用的字符串配置，列表元素以下标作为键（如 postgres.replicas.0.password）
+func collectSecretRefs(prefix string, value interface{}) map[string]string {
+	out := map[string]string{}
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch val := value.(type) {
+	case string:
+		if secretRefPattern.MatchString(val) {
+			out[prefix] = val
+		}
+	case map[string]interface{}:
+		for k, nested := range val {
+			for nk, nv := range collectSecretRefs(join(strings.ToLower(k)), nested) {
+				out[nk] = nv
+			}
+		}
+	case []interface{}:
+		for i, nested := range val {
+			for nk, nv := range collectSecretRefs(join(fmt.Sprint(i)), nested) {
+				out[nk] = nv
+			}
+		}
+	}
+	return out
+}
+
+// isSecretRef 判断取值是否完全来自密钥引用（用于 secrets.forbid_plaintext 校验）
+func isSecretRef(value string) bool {
+	loc := secretRefPattern.FindStringIndex(value)
+	return loc != nil && loc[0] == 0 && loc[1] == len(value)
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeSecret 写入临时密钥文件（带末尾换行，模拟 Docker/K8s secret）
+func writeSecret(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newVaultStub 模拟 Vault KV v2 接口，仅接受指定 token
+func newVaultStub(t *testing.T, token string, secrets map[string]map[string]interface{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		data, ok := secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		body := map[string]interface{}{"data": map[string]interface{}{"data": data, "metadata": map[string]interface{}{"version": 1}}}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSecretFileVariant(t *testing.T) {
+	jwtKey := "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1"
+	path := writeSecret(t, jwtKey)
+	loadTestConfig(t, strings.Replace(validYAML, `key: "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1"`, "key_file: "+path, 1))
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := getString("jwt.key"); got != jwtKey {
+		t.Fatalf("jwt.key = %q, want %q", got, jwtKey)
+	}
+
+	// 密钥文件轮换后，重新解析（配置热加载）即可取得新值
+	rotated := "Zr8Tb5Vn0Yc4Hd6Fg1k3J9x!pQ7w#Lm2"
+	if err := os.WriteFile(path, []byte(rotated), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	refreshSecrets()
+	if got := getString("jwt.key"); got != rotated {
+		t.Fatalf("jwt.key after refresh = %q, want %q", got, rotated)
+	}
+
+	// 与明文同时配置、文件不存在都应报错
+	loadTestConfig(t, validYAML+"  password_file: "+path+"\n")
+	if keys := problemKeys(t, Validate()); !slices.Contains(keys, "admin.password_file") {
+		t.Fatalf("problem keys = %v, want admin.password_file", keys)
+	}
+	loadTestConfig(t, strings.Replace(validYAML, `key: "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1"`, "key_file: /nonexistent/jwt", 1))
+	if keys := problemKeys(t, Validate()); !slices.Contains(keys, "jwt.key_file") {
+		t.Fatalf("problem keys = %v, want jwt.key_file", keys)
+	}
+}
+
+func TestSecretReferences(t *testing.T) {
+	t.Setenv("TEST_REDIS_PASSWORD", "from-env")
+	path := writeSecret(t, "from-file")
+	loadTestConfig(t, validYAML)
+	v.Set("admin.salt", "${file:"+path+"}")
+	v.Set("redis.password", "${env:TEST_REDIS_PASSWORD}")
+	v.Set("postgres.user", "app-${env:TEST_REDIS_PASSWORD}")
+	refreshSecrets()
+
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	tests := map[string]string{
+		"redis.password": "from-env",
+		"admin.salt":     "from-file",
+		"postgres.user":  "app-from-env",
+	}
+	for key, want := range tests {
+		if got := getString(key); got != want {
+			t.Errorf("getString(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	v.Set("redis.password", "${env:TEST_MISSING_VAR}")
+	v.Set("postgres.user", "${unknown:x}")
+	refreshSecrets()
+	keys := problemKeys(t, Validate())
+	if !slices.Contains(keys, "redis.password") || !slices.Contains(keys, "postgres.user") {
+		t.Fatalf("problem keys = %v, want redis.password and postgres.user", keys)
+	}
+}
+
+func TestVaultResolver(t *testing.T) {
+	srv := newVaultStub(t, "s.test", map[string]map[string]interface{}{
+		"api-server/prod": {"jwt_key": "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1", "port": 5432},
+	})
+
+	r := &VaultResolver{Address: srv.URL, Token: "s.test"}
+	got, err := r.Resolve(context.Background(), "api-server/prod#jwt_key")
+	if err != nil || got != "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1" {
+		t.Fatalf("Resolve() = %q, %v", got, err)
+	}
+	for _, ref := range []string{"api-server/prod#missing", "api-server/prod#port", "api-server/other#jwt_key", "no-field"} {
+		if _, err := r.Resolve(context.Background(), ref); err == nil {
+			t.Errorf("Resolve(%q) expected error", ref)
+		}
+	}
+	bad := &VaultResolver{Address: srv.URL, Token: "wrong"}
+	if _, err := bad.Resolve(context.Background(), "api-server/prod#jwt_key"); err == nil {
+		t.Error("expected error for invalid token")
+	}
+}
+
+func TestVaultReferenceInConfig(t *testing.T) {
+	srv := newVaultStub(t, "s.test", map[string]map[string]interface{}{
+		"api-server/prod": {"jwt_key": "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1", "pg": "pg-secret"},
+	})
+	tokenFile := writeSecret(t, "s.test")
+	loadTestConfig(t, strings.Replace(validYAML, `key: "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1"`, `key: "${vault:api-server/prod#jwt_key}"`, 1)+fmt.Sprintf(`
+secrets:
+  forbid_plaintext: false
+  vault:
+    address: %s
+    token_file: %s
+`, srv.URL, tokenFile))
+	v.Set("postgres.password", "${vault:api-server/prod#pg}")
+	refreshSecrets()
+
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := getString("jwt.key"); got != "k3J9x!pQ7w#Lm2Zr8Tb5Vn0Yc4Hd6Fg1" {
+		t.Fatalf("jwt.key = %q", got)
+	}
+	if got := getString("postgres.password"); got != "pg-secret" {
+		t.Fatalf("postgres.password = %q", got)
+	}
+}
+
+func TestForbidPlaintextSecrets(t *testing.T) {
+	loadTestConfig(t, validYAML+`
+secrets:
+  forbid_plaintext: true
+`)
+	keys := problemKeys(t, Validate())
+	for _, want := range []string{"jwt.key", "redis.password", "admin.password", "admin.salt"} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("problem keys = %v, want %s", keys, want)
+		}
+	}
+}
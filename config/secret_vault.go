@@ -0,0 +1,89 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultResolver 从 HashiCorp Vault KV 引擎读取密钥，引用格式为 <path>#<field>，
+// 如 ${vault:api-server/prod#jwt_key} 读取 <mount>/api-server/prod 中的 jwt_key 字段
+type VaultResolver struct {
+	Address   string // Vault 地址，如 https://vault.example.com:8200
+	Token     string
+	Namespace string // Vault 企业版命名空间，可为空
+	Mount     string // KV 引擎挂载路径，默认 secret
+	KVVersion int    // KV 引擎版本（1 或 2），默认 2
+	Client    *http.Client
+}
+
+// Resolve 读取 ref 指向的字段，字段取值必须是字符串
+func (r *VaultResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid vault reference %q, want <path>#<field>", ref)
+	}
+	data, err := r.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	raw, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %q", path, field)
+	}
+	val, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s field %q is not a string", path, field)
+	}
+	return val, nil
+}
+
+func (r *VaultResolver) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	mount := strings.Trim(r.Mount, "/")
+	if mount == "" {
+		mount = "secret"
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", strings.TrimRight(r.Address, "/"), mount, path)
+	if r.KVVersion != 1 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(r.Address, "/"), mount, path)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", r.Token)
+	if r.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.Namespace)
+	}
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	// KV v1: {"data": {...}}；KV v2: {"data": {"data": {...}, "metadata": {...}}}
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+	if r.KVVersion == 1 {
+		return body.Data, nil
+	}
+	nested, ok := body.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("vault secret %s has no data (deleted or destroyed)", path)
+	}
+	return nested, nil
+}
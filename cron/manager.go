@@ -27,3 +27,36 @@ func InitCronJobs() {
 
 	zap.L().Info("定时任务调度器已启动")
 }
+
+// JobInfo 定时任务运行信息（用于运行时诊断）
+type JobInfo struct {
+	ID      string
+	Name    string
+	Tags    []string
+	NextRun time.Time
+	LastRun time.Time
+}
+
+// ListJobs 返回当前已注册的定时任务及其上次/下次执行时间
+func ListJobs() []JobInfo {
+	if scheduler == nil {
+		return []JobInfo{}
+	}
+	jobs := scheduler.Jobs()
+	result := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		info := JobInfo{
+			ID:   job.ID().String(),
+			Name: job.Name(),
+			Tags: job.Tags(),
+		}
+		if next, err := job.NextRun(); err == nil {
+			info.NextRun = next
+		}
+		if last, err := job.LastRun(); err == nil {
+			info.LastRun = last
+		}
+		result = append(result, info)
+	}
+	return result
+}
@@ -1,43 +1,53 @@
 package cron
 
 import (
-	"time"
+	"context"
 
 	"github.com/go-co-op/gocron/v2"
 	"go.uber.org/zap"
 
+	"api-server/config"
 	systemuser "api-server/db/rdb/systemUser"
 )
 
-// InitUserCacheJob 初始化用户缓存定时任务
+// UserCacheJobName 用户缓存定时任务名称
+const UserCacheJobName = "user-cache-refresh"
+
+// InitUserCacheJob 初始化用户缓存全量对账任务。
+// 用户/角色变更已通过领域事件增量更新缓存，这里仅低频兜底（默认每 6 小时），修正事件处理失败等导致的偏差。
 func InitUserCacheJob() {
 	// 立即执行一次缓存
-	if err := systemuser.CacheAllUsers(); err != nil {
+	if err := systemuser.CacheAllUsers(context.Background()); err != nil {
 		zap.L().Error("初始化用户缓存失败", zap.Error(err))
 	} else {
 		zap.L().Info("初始化用户缓存成功")
 	}
 
-	// 每10分钟执行一次用户信息缓存更新
+	interval := config.UserCacheReconcileInterval
 	job, err := scheduler.NewJob(
-		gocron.DurationJob(
-			10*60*time.Second, // 10分钟
-		),
+		gocron.DurationJob(interval),
 		gocron.NewTask(
-			func() {
-				zap.L().Info("开始执行用户缓存定时更新")
-				if err := systemuser.CacheAllUsers(); err != nil {
-					zap.L().Error("更新用户缓存失败", zap.Error(err))
+			// gocron 传入的 ctx 在调度器关闭时取消，退出时不再等待整轮对账完成
+			func(ctx context.Context) {
+				zap.L().Info("开始执行用户缓存全量对账")
+				if err := systemuser.CacheAllUsers(ctx); err != nil {
+					zap.L().Error("用户缓存全量对账失败", zap.Error(err))
 				} else {
-					zap.L().Info("更新用户缓存成功")
+					zap.L().Info("用户缓存全量对账完成")
 				}
 			},
 		),
+		gocron.WithName(UserCacheJobName),
+		// 上一次对账尚未结束时跳过本次，避免大数据量下任务堆积
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
 	)
 
 	if err != nil {
 		zap.L().Error("创建用户缓存定时任务失败", zap.Error(err))
 	} else {
-		zap.L().Info("用户缓存定时任务已创建，每10分钟执行一次", zap.String("jobID", job.ID().String()))
+		zap.L().Info("用户缓存对账任务已创建",
+			zap.Duration("interval", interval),
+			zap.String("jobID", job.ID().String()),
+		)
 	}
 }
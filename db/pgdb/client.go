@@ -1,17 +1,26 @@
 package pgdb
 
 import (
-	"time"
+	"context"
+	"database/sql"
+	"errors"
 
 	"api-server/config"
 
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
 var client *gorm.DB
 
+// replicaResolver 只读副本解析器名称
+const replicaResolver = "replica"
+
+// replicaEnabled 是否已注册只读副本
+var replicaEnabled bool
+
 func GetClient() *gorm.DB {
 	if client == nil {
 		Init()
@@ -24,6 +33,11 @@ func Init() error {
 	db, err := gorm.Open(postgres.Open(config.PgsqlDSN), &gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true, // 禁用迁移时的外键约束
 		CreateBatchSize:                          1000, // 批量插入大小
+		Logger: NewLogger(nil, LoggerOptions{
+			SlowThreshold:             config.PgsqlSlowThreshold,
+			RedactParams:              config.PgsqlRedactParams,
+			IgnoreRecordNotFoundError: true,
+		}),
 	})
 	if err != nil {
 		zap.L().Error("connect to mysql failed", zap.Error(err))
@@ -34,12 +48,69 @@ func Init() error {
 		zap.L().Error("get db failed", zap.Error(err))
 		return err
 	}
-	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
-	pgDB.SetMaxIdleConns(10)
-	// SetMaxOpenConns sets the maximum number of open connections to the database.
-	pgDB.SetMaxOpenConns(100)
-	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
-	pgDB.SetConnMaxLifetime(time.Hour)
+	pgDB.SetMaxIdleConns(config.PgsqlMaxIdleConns)
+	pgDB.SetMaxOpenConns(config.PgsqlMaxOpenConns)
+	pgDB.SetConnMaxLifetime(config.PgsqlConnMaxLifetime)
+	pgDB.SetConnMaxIdleTime(config.PgsqlConnMaxIdleTime)
+
+	if err := registerReplicas(db); err != nil {
+		zap.L().Error("register postgres replicas failed", zap.Error(err))
+		return err
+	}
 	client = db
 	return nil
 }
+
+// registerReplicas 以具名解析器注册只读副本。
+// 不注册全局解析器：默认所有读写仍走主库（保证写后读一致），
+// 仅显式通过 Replica() 发起的查询才会路由到副本。
+func registerReplicas(db *gorm.DB) error {
+	if len(config.PgsqlReplicaDSNs) == 0 {
+		return nil
+	}
+	replicas := make([]gorm.Dialector, 0, len(config.PgsqlReplicaDSNs))
+	for _, dsn := range config.PgsqlReplicaDSNs {
+		replicas = append(replicas, postgres.Open(dsn))
+	}
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, replicaResolver).
+		SetMaxIdleConns(config.PgsqlMaxIdleConns).
+		SetMaxOpenConns(config.PgsqlMaxOpenConns).
+		SetConnMaxLifetime(config.PgsqlConnMaxLifetime).
+		SetConnMaxIdleTime(config.PgsqlConnMaxIdleTime)
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+	replicaEnabled = true
+	zap.L().Info("postgres 只读副本已启用", zap.Int("replicas", len(replicas)))
+	return nil
+}
+
+// Replica 返回路由到只读副本的查询会话，用于列表、报表等可容忍复制延迟的查询；
+// 未配置副本时等同于 GetClient().WithContext(ctx)。写操作与写后立即读取的场景必须使用 GetClient()。
+func Replica(ctx context.Context) *gorm.DB {
+	db := GetClient()
+	if db == nil {
+		return nil
+	}
+	db = db.WithContext(ctx)
+	if !replicaEnabled {
+		return db
+	}
+	return db.Clauses(dbresolver.Use(replicaResolver), dbresolver.Read)
+}
+
+// Stats 返回底层 sql.DB 连接池统计信息，用于运行时诊断
+func Stats() (sql.DBStats, error) {
+	db := GetClient()
+	if db == nil {
+		return sql.DBStats{}, errors.New("postgres client not initialized")
+	}
+	pgDB, err := db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return pgDB.Stats(), nil
+}
@@ -0,0 +1,121 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+
+	httplog "api-server/util/log"
+)
+
+// zapLogger 将 GORM 日志桥接到 zap 的具名 logger（gorm），
+// 级别由 log.level.gorm 控制：Debug 输出全部 SQL，Warn 仅输出慢查询，Error 仅输出失败的 SQL。
+type zapLogger struct {
+	logger                    *zap.Logger
+	level                     gormlogger.LogLevel
+	slowThreshold             time.Duration
+	redactParams              bool
+	ignoreRecordNotFoundError bool
+}
+
+// LoggerOptions GORM 日志选项
+type LoggerOptions struct {
+	SlowThreshold             time.Duration // 慢查询阈值，<=0 表示不区分慢查询
+	RedactParams              bool          // 日志中不展开 SQL 参数（保留 $1 占位符）
+	IgnoreRecordNotFoundError bool          // 不把 ErrRecordNotFound 记为错误
+}
+
+// NewLogger 创建基于 zap 的 gorm/logger.Interface 实现
+func NewLogger(l *zap.Logger, opts LoggerOptions) gormlogger.Interface {
+	if l == nil {
+		l = httplog.Named(httplog.LoggerGorm)
+	}
+	return &zapLogger{
+		logger:                    l,
+		level:                     gormlogger.Info,
+		slowThreshold:             opts.SlowThreshold,
+		redactParams:              opts.RedactParams,
+		ignoreRecordNotFoundError: opts.IgnoreRecordNotFoundError,
+	}
+}
+
+func (l *zapLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *zapLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.withContext(ctx).Info(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+func (l *zapLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.withContext(ctx).Warn(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+func (l *zapLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.withContext(ctx).Error(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+// Trace 记录每条 SQL 的执行结果、影响行数与耗时
+func (l *zapLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	isSlow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	logger := l.withContext(ctx)
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error &&
+		(!l.ignoreRecordNotFoundError || !errors.Is(err, gorm.ErrRecordNotFound)):
+		sql, rows := fc()
+		logger.Error("SQL 执行失败", append(traceFields(sql, rows, elapsed), zap.Error(err))...)
+	case isSlow && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		logger.Warn("慢查询", append(traceFields(sql, rows, elapsed), zap.Duration("slow_threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info:
+		// 普通 SQL 以 Debug 级别输出，是否落盘由 gorm logger 的原子级别决定
+		if ce := logger.Check(zap.DebugLevel, "SQL"); ce != nil {
+			sql, rows := fc()
+			ce.Write(traceFields(sql, rows, elapsed)...)
+		}
+	}
+}
+
+// ParamsFilter 实现 gorm 的参数过滤接口；开启脱敏时日志中的 SQL 仅保留占位符
+func (l *zapLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.redactParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
+func (l *zapLogger) withContext(ctx context.Context) *zap.Logger {
+	if requestID := httplog.RequestIDFromContext(ctx); requestID != "" {
+		return l.logger.With(zap.String("trace_id", requestID))
+	}
+	return l.logger
+}
+
+func traceFields(sql string, rows int64, elapsed time.Duration) []zap.Field {
+	return []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+		zap.String("source", utils.FileWithLineNum()),
+	}
+}
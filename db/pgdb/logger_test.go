@@ -0,0 +1,73 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+
+	httplog "api-server/util/log"
+)
+
+func newObservedLogger(level zapcore.Level, opts LoggerOptions) (*zapLogger, *observer.ObservedLogs) {
+	core, logs := observer.New(level)
+	return NewLogger(zap.New(core), opts).(*zapLogger), logs
+}
+
+func TestTrace_SlowQuery(t *testing.T) {
+	l, logs := newObservedLogger(zap.DebugLevel, LoggerOptions{SlowThreshold: 10 * time.Millisecond})
+	ctx := httplog.WithRequestID(context.Background(), "req-1")
+
+	l.Trace(ctx, time.Now().Add(-50*time.Millisecond), func() (string, int64) {
+		return "SELECT * FROM system_users", 3
+	}, nil)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != zap.WarnLevel {
+		t.Fatalf("level = %s, want warn", entry.Level)
+	}
+	fields := entry.ContextMap()
+	if fields["trace_id"] != "req-1" {
+		t.Errorf("trace_id = %v, want req-1", fields["trace_id"])
+	}
+	if fields["rows"] != int64(3) {
+		t.Errorf("rows = %v, want 3", fields["rows"])
+	}
+}
+
+func TestTrace_RecordNotFoundIgnored(t *testing.T) {
+	l, logs := newObservedLogger(zap.InfoLevel, LoggerOptions{IgnoreRecordNotFoundError: true})
+
+	l.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 0
+	}, gorm.ErrRecordNotFound)
+
+	if logs.Len() != 0 {
+		t.Fatalf("record not found should not be logged at info level, got %d entries", logs.Len())
+	}
+
+	l.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 0
+	}, errors.New("connection reset"))
+
+	if logs.FilterLevelExact(zap.ErrorLevel).Len() != 1 {
+		t.Fatalf("expected one error entry")
+	}
+}
+
+func TestParamsFilter_Redact(t *testing.T) {
+	l, _ := newObservedLogger(zap.DebugLevel, LoggerOptions{RedactParams: true})
+	sql, params := l.ParamsFilter(context.Background(), "SELECT * FROM t WHERE id = $1", 1)
+	if sql != "SELECT * FROM t WHERE id = $1" || params != nil {
+		t.Fatalf("ParamsFilter() = %q, %v; want params redacted", sql, params)
+	}
+}
@@ -0,0 +1,336 @@
+// Package migration 实现带版本号的数据库迁移：迁移记录保存在 schema_migrations 表中，
+// 执行期间持有 PostgreSQL advisory lock，保证多副本同时启动时只有一个实例在迁移。
+// 迁移步骤可以是 Go 函数（适合回填数据等需要程序逻辑的场景），也可以是嵌入的 SQL 文件（见 LoadSQL）。
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lockKey schema 迁移使用的 advisory lock 键，所有实例必须一致
+const lockKey int64 = 0x6170695f6d696772 // "api_migr"
+
+var (
+	// ErrIrreversible 迁移未提供回滚步骤
+	ErrIrreversible = errors.New("migration is irreversible")
+	// ErrUnknownVersion 数据库中存在当前程序不认识的迁移版本，通常是用新版本迁移后又回退了程序
+	ErrUnknownVersion = errors.New("unknown migration version")
+	// ErrInvalidVersion 目标版本不存在
+	ErrInvalidVersion = errors.New("invalid migration version")
+)
+
+// Migration 单个迁移步骤。Up/Down 默认在同一事务中执行并写入/删除迁移记录；
+// Down 为 nil 表示不可回滚。NoTx 为 true 时不包裹事务（如 CREATE INDEX CONCURRENTLY），
+// 此时步骤失败可能留下部分变更，需保证语句可重复执行。
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+	NoTx    bool
+}
+
+// SchemaMigration 迁移历史记录
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status 单个版本的迁移状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Unknown   bool // 已在数据库中应用，但当前程序没有对应的迁移
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New 校验迁移列表（版本号为正且不重复、Up 不为空）并按版本排序
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q: version must be positive", m.Name)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s: up step is required", m.Version, m.Name)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+	}
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// Up 执行全部未应用的迁移，返回本次执行的迁移；数据库中更高的未知版本不会被回滚
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		done, err = m.migrate(conn, applied, math.MaxInt64)
+		return err
+	})
+	return done, err
+}
+
+// Down 按版本倒序回滚最近 steps 个已应用的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, nil
+	}
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		versions := sortedVersions(applied)
+		if len(versions) < steps {
+			steps = len(versions)
+		}
+		var target int64
+		if len(versions) > steps {
+			target = versions[len(versions)-steps-1]
+		}
+		done, err = m.migrate(conn, applied, target)
+		return err
+	})
+	return done, err
+}
+
+// To 迁移到指定版本：高于当前版本时依次执行 Up，低于当前版本时依次回滚；0 表示回滚全部
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidVersion, version)
+	}
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		done, err = m.migrate(conn, applied, version)
+		return err
+	})
+	return done, err
+}
+
+// Status 返回全部已知迁移及数据库中未知版本的状态，按版本排序；只读，不会创建迁移记录表
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn := m.db.WithContext(ctx)
+	applied := map[int64]SchemaMigration{}
+	if conn.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if applied, err = m.applied(conn); err != nil {
+			return nil, err
+		}
+	}
+	return buildStatus(m.migrations, applied), nil
+}
+
+// Pending 返回尚未应用的迁移数量，供启动时提示
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func buildStatus(migrations []Migration, applied map[int64]SchemaMigration) []Status {
+	out := make([]Status, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+	for _, mig := range migrations {
+		known[mig.Version] = true
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if rec, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = rec.AppliedAt
+		}
+		out = append(out, s)
+	}
+	for version, rec := range applied {
+		if !known[version] {
+			out = append(out, Status{Version: version, Name: rec.Name, Applied: true, AppliedAt: rec.AppliedAt, Unknown: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// plan 计算迁移到 target 需要执行的步骤：up 按版本升序，down 按版本降序
+func plan(migrations []Migration, applied map[int64]SchemaMigration, target int64) (up, down []Migration, err error) {
+	known := make(map[int64]bool, len(migrations))
+	for _, mig := range migrations {
+		known[mig.Version] = true
+		_, ok := applied[mig.Version]
+		if !ok && mig.Version <= target {
+			up = append(up, mig)
+		}
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mig := migrations[i]
+		if _, ok := applied[mig.Version]; ok && mig.Version > target {
+			if mig.Down == nil {
+				return nil, nil, fmt.Errorf("%w: %d_%s", ErrIrreversible, mig.Version, mig.Name)
+			}
+			down = append(down, mig)
+		}
+	}
+	for version := range applied {
+		if !known[version] && version > target {
+			return nil, nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+	}
+	return up, down, nil
+}
+
+func (m *Migrator) migrate(conn *gorm.DB, applied map[int64]SchemaMigration, target int64) ([]Migration, error) {
+	up, down, err := plan(m.migrations, applied, target)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, mig := range down {
+		if err := m.run(conn, mig, false); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	for _, mig := range up {
+		if err := m.run(conn, mig, true); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+func (m *Migrator) run(conn *gorm.DB, mig Migration, up bool) error {
+	direction, step := "down", mig.Down
+	if up {
+		direction, step = "up", mig.Up
+	}
+	fields := []zap.Field{zap.Int64("version", mig.Version), zap.String("name", mig.Name), zap.String("direction", direction)}
+	zap.L().Info("执行数据库迁移", fields...)
+	start := time.Now()
+
+	apply := func(tx *gorm.DB) error {
+		if err := step(tx); err != nil {
+			return err
+		}
+		if up {
+			return tx.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&SchemaMigration{Version: mig.Version}).Error
+	}
+	var err error
+	if mig.NoTx {
+		err = apply(conn)
+	} else {
+		err = conn.Transaction(apply)
+	}
+	if err != nil {
+		zap.L().Error("数据库迁移失败", append(fields, zap.Error(err))...)
+		return fmt.Errorf("migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+	zap.L().Info("数据库迁移完成", append(fields, zap.Duration("elapsed", time.Since(start)))...)
+	return nil
+}
+
+// locked 在单个连接上持有 advisory lock 执行 fn；锁被其它实例持有时阻塞等待，直到 ctx 取消
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// 连接参数中的 statement_timeout 面向在线请求，会中断等待迁移锁与耗时较长的回填，
+		// 迁移期间在本连接上取消限制，归还连接池前恢复为连接参数中的值
+		if err := conn.Exec("SET statement_timeout = 0").Error; err != nil {
+			return err
+		}
+		defer func() {
+			if err := conn.WithContext(context.WithoutCancel(ctx)).Exec("RESET statement_timeout").Error; err != nil {
+				zap.L().Warn("恢复 statement_timeout 失败", zap.Error(err))
+			}
+		}()
+
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", lockKey).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			zap.L().Info("其它实例正在执行数据库迁移，等待迁移锁")
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+		}
+		defer func() {
+			// 解锁不应受 ctx 取消影响，否则锁会保留到连接关闭
+			if err := conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", lockKey).Error; err != nil {
+				zap.L().Warn("释放迁移锁失败", zap.Error(err))
+			}
+		}()
+
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := conn.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]SchemaMigration, len(records))
+	for _, r := range records {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func sortedVersions(applied map[int64]SchemaMigration) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
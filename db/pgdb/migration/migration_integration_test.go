@@ -0,0 +1,91 @@
+package migration
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 连接 MIGRATION_TEST_DSN 指定的空测试库，附加较短的 statement_timeout 模拟线上连接参数
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("跳过集成测试（-short）")
+	}
+	dsn := os.Getenv("MIGRATION_TEST_DSN")
+	if dsn == "" {
+		t.Skip("未设置 MIGRATION_TEST_DSN，跳过集成测试")
+	}
+	db, err := gorm.Open(postgres.Open(dsn+" statement_timeout=200"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("连接测试库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func TestUpWaitsForLockBeyondStatementTimeout_Integration(t *testing.T) {
+	ctx := context.Background()
+	holder := openTestDB(t)
+	db := openTestDB(t)
+	// 单连接：迁移结束后归还的连接应恢复连接参数中的 statement_timeout
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	const version int64 = 900001
+	t.Cleanup(func() {
+		holder.Exec("DELETE FROM schema_migrations WHERE version = ?", version)
+	})
+
+	// 另一个会话持有迁移锁的时间超过 statement_timeout
+	locked := make(chan struct{})
+	released := make(chan error, 1)
+	go func() {
+		released <- holder.Connection(func(conn *gorm.DB) error {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				close(locked)
+				return err
+			}
+			close(locked)
+			time.Sleep(time.Second)
+			return conn.Exec("SELECT pg_advisory_unlock(?)", lockKey).Error
+		})
+	}()
+	<-locked
+
+	m, err := New(db, []Migration{{
+		Version: version,
+		Name:    "slow_backfill",
+		Up:      func(tx *gorm.DB) error { return tx.Exec("SELECT pg_sleep(0.5)").Error },
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v, want lock wait and backfill not cancelled by statement_timeout", err)
+	}
+	if len(done) != 1 || done[0].Version != version {
+		t.Fatalf("Up() = %v, want version %d applied", versionsOf(done), version)
+	}
+	if err := <-released; err != nil {
+		t.Fatalf("持有迁移锁失败: %v", err)
+	}
+
+	var timeout string
+	if err := db.Raw("SHOW statement_timeout").Scan(&timeout).Error; err != nil {
+		t.Fatal(err)
+	}
+	if timeout != "200ms" {
+		t.Fatalf("statement_timeout after migration = %q, want 200ms restored", timeout)
+	}
+}
@@ -0,0 +1,167 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func noop(*gorm.DB) error { return nil }
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "one", Up: noop, Down: noop},
+		{Version: 2, Name: "two", Up: noop},
+		{Version: 3, Name: "three", Up: noop, Down: noop},
+	}
+}
+
+func appliedSet(versions ...int64) map[int64]SchemaMigration {
+	out := make(map[int64]SchemaMigration, len(versions))
+	for _, v := range versions {
+		out[v] = SchemaMigration{Version: v, Name: "applied", AppliedAt: time.Unix(v, 0)}
+	}
+	return out
+}
+
+func versionsOf(migrations []Migration) []int64 {
+	out := make([]int64, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m.Version)
+	}
+	return out
+}
+
+func equalVersions(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPlan(t *testing.T) {
+	cases := []struct {
+		name     string
+		applied  map[int64]SchemaMigration
+		target   int64
+		wantUp   []int64
+		wantDown []int64
+		wantErr  error
+	}{
+		{name: "全部执行", applied: appliedSet(), target: 3, wantUp: []int64{1, 2, 3}},
+		{name: "补齐缺失版本", applied: appliedSet(1, 3), target: 3, wantUp: []int64{2}},
+		{name: "迁移到中间版本", applied: appliedSet(), target: 2, wantUp: []int64{1, 2}},
+		{name: "已是最新", applied: appliedSet(1, 2, 3), target: 3},
+		{name: "回滚一步", applied: appliedSet(1, 2, 3), target: 2, wantDown: []int64{3}},
+		{name: "回滚经过不可逆迁移", applied: appliedSet(1, 2, 3), target: 1, wantErr: ErrIrreversible},
+		{name: "存在未知新版本", applied: appliedSet(1, 2, 3, 9), target: 3, wantErr: ErrUnknownVersion},
+		{name: "未知版本低于目标", applied: appliedSet(1, 2, 3, 9), target: 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			up, down, err := plan(testMigrations(), tc.applied, tc.target)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := versionsOf(up); !equalVersions(got, tc.wantUp) {
+				t.Errorf("up = %v, want %v", got, tc.wantUp)
+			}
+			if got := versionsOf(down); !equalVersions(got, tc.wantDown) {
+				t.Errorf("down = %v, want %v", got, tc.wantDown)
+			}
+		})
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	statuses := buildStatus(testMigrations(), appliedSet(1, 7))
+	if len(statuses) != 4 {
+		t.Fatalf("len = %d, want 4", len(statuses))
+	}
+	want := []struct {
+		version          int64
+		applied, unknown bool
+	}{{1, true, false}, {2, false, false}, {3, false, false}, {7, true, true}}
+	for i, w := range want {
+		s := statuses[i]
+		if s.Version != w.version || s.Applied != w.applied || s.Unknown != w.unknown {
+			t.Errorf("statuses[%d] = %+v, want version=%d applied=%v unknown=%v", i, s, w.version, w.applied, w.unknown)
+		}
+	}
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New(nil, []Migration{{Version: 0, Name: "zero", Up: noop}}); err == nil {
+		t.Error("expected error for zero version")
+	}
+	if _, err := New(nil, []Migration{{Version: 1, Name: "a", Up: noop}, {Version: 1, Name: "b", Up: noop}}); err == nil {
+		t.Error("expected error for duplicate version")
+	}
+	if _, err := New(nil, []Migration{{Version: 1, Name: "a"}}); err == nil {
+		t.Error("expected error for missing up step")
+	}
+	m, err := New(nil, []Migration{{Version: 2, Name: "b", Up: noop}, {Version: 1, Name: "a", Up: noop}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := versionsOf(m.migrations); !equalVersions(got, []int64{1, 2}) {
+		t.Errorf("migrations not sorted: %v", got)
+	}
+}
+
+func TestLoadSQL(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0003_add_index.up.sql":   {Data: []byte("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY x ON t(a);")},
+		"m/0003_add_index.down.sql": {Data: []byte("DROP INDEX x;")},
+		"m/0004_drop_column.up.sql": {Data: []byte("ALTER TABLE t DROP COLUMN b;")},
+	}
+	migrations, err := LoadSQL(fsys, "m")
+	if err != nil {
+		t.Fatalf("LoadSQL: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("len = %d, want 2", len(migrations))
+	}
+	byVersion := map[int64]Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	idx := byVersion[3]
+	if idx.Name != "add_index" || !idx.NoTx || idx.Up == nil || idx.Down == nil {
+		t.Errorf("unexpected migration 3: %+v", idx)
+	}
+	drop := byVersion[4]
+	if drop.Name != "drop_column" || drop.NoTx || drop.Up == nil || drop.Down != nil {
+		t.Errorf("unexpected migration 4: %+v", drop)
+	}
+
+	missingUp := fstest.MapFS{"m/0005_x.down.sql": {Data: []byte("SELECT 1;")}}
+	if _, err := LoadSQL(missingUp, "m"); err == nil {
+		t.Error("expected error for missing up file")
+	}
+	badName := fstest.MapFS{"m/5_Add-Index.up.sql": {Data: []byte("SELECT 1;")}}
+	if _, err := LoadSQL(badName, "m"); err == nil {
+		t.Error("expected error for invalid file name")
+	}
+	conflict := fstest.MapFS{
+		"m/0006_a.up.sql": {Data: []byte("SELECT 1;")},
+		"m/0006_b.up.sql": {Data: []byte("SELECT 1;")},
+	}
+	if _, err := LoadSQL(conflict, "m"); err == nil {
+		t.Error("expected error for conflicting names")
+	}
+}
@@ -0,0 +1,80 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sqlFilePattern 迁移文件命名：<版本>_<名称>.up.sql / <版本>_<名称>.down.sql
+var sqlFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// noTxDirective 出现在 up 文件第一行时，该迁移不包裹事务执行
+const noTxDirective = "-- migrate:no-transaction"
+
+// LoadSQL 读取 dir 下的 SQL 迁移文件。每个版本必须有 up 文件，down 文件可选（缺失表示不可回滚）；
+// 文件内容作为单次 Exec 执行，可包含多条语句。
+func LoadSQL(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	var order []int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, want <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		raw, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		content := string(raw)
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+			order = append(order, version)
+		} else if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, mig.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			mig.Up = execSQL(content)
+			mig.NoTx = strings.HasPrefix(strings.TrimSpace(content), noTxDirective)
+		} else {
+			mig.Down = execSQL(content)
+		}
+	}
+
+	out := make([]Migration, 0, len(order))
+	for _, version := range order {
+		mig := byVersion[version]
+		if mig.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", mig.Version, mig.Name)
+		}
+		out = append(out, *mig)
+	}
+	return out, nil
+}
+
+func execSQL(content string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(content).Error
+	}
+}
@@ -1,6 +1,8 @@
 package system
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 
 	"api-server/config"
@@ -8,10 +10,10 @@ import (
 )
 
 // FindDepartmentList 查询部门列表(带分页)
-func FindDepartmentList(department *SystemDepartment, page, pageSize int) ([]SystemDepartment, int64, error) {
+func FindDepartmentList(ctx context.Context, department *SystemDepartment, page, pageSize int) ([]SystemDepartment, int64, error) {
 	var departments []SystemDepartment
 	var total int64
-	db := pgdb.GetClient()
+	db := pgdb.GetClient().WithContext(ctx)
 
 	// 构建基础查询
 	query := db.Model(&SystemDepartment{})
@@ -54,32 +56,32 @@ func FindDepartmentList(department *SystemDepartment, page, pageSize int) ([]Sys
 }
 
 // GetDepartment 查询单个部门
-func GetDepartment(department *SystemDepartment) error {
-	if err := pgdb.GetClient().Where(department).First(department).Error; err != nil {
+func GetDepartment(ctx context.Context, department *SystemDepartment) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where(department).First(department).Error; err != nil {
 		zap.L().Error("failed to get department", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func AddDepartment(department *SystemDepartment) error {
-	if err := pgdb.GetClient().Create(&department).Error; err != nil {
+func AddDepartment(ctx context.Context, department *SystemDepartment) error {
+	if err := pgdb.GetClient().WithContext(ctx).Create(&department).Error; err != nil {
 		zap.L().Error("failed to create department", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func UpdateDepartment(department *SystemDepartment) error {
-	if err := pgdb.GetClient().Updates(&department).Error; err != nil {
+func UpdateDepartment(ctx context.Context, department *SystemDepartment) error {
+	if err := pgdb.GetClient().WithContext(ctx).Updates(&department).Error; err != nil {
 		zap.L().Error("failed to update department", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func DeleteDepartment(department *SystemDepartment) error {
-	if err := pgdb.GetClient().Delete(&department).Error; err != nil {
+func DeleteDepartment(ctx context.Context, department *SystemDepartment) error {
+	if err := pgdb.GetClient().WithContext(ctx).Delete(&department).Error; err != nil {
 		zap.L().Error("failed to delete department", zap.Error(err))
 		return err
 	}
@@ -87,8 +89,8 @@ func DeleteDepartment(department *SystemDepartment) error {
 }
 
 // CountUsersByDepartmentID 统计指定部门下的用户数量
-func CountUsersByDepartmentID(departmentID uint, count *int64) error {
-	if err := pgdb.GetClient().Model(&SystemUser{}).Where("department_id = ?", departmentID).Count(count).Error; err != nil {
+func CountUsersByDepartmentID(ctx context.Context, departmentID uint, count *int64) error {
+	if err := pgdb.GetClient().WithContext(ctx).Model(&SystemUser{}).Where("department_id = ?", departmentID).Count(count).Error; err != nil {
 		zap.L().Error("failed to count users by department id", zap.Error(err))
 		return err
 	}
@@ -0,0 +1,50 @@
+package system
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"api-server/db/pgdb"
+)
+
+// FindAllIPRules 查询所有作用域的 IP 访问控制规则
+func FindAllIPRules(ctx context.Context, items *[]SystemIPRule) error {
+	if err := pgdb.GetClient().WithContext(ctx).Order("scope, tenant_id, action, cidr").Find(items).Error; err != nil {
+		zap.L().Error("failed to find ip rules", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindIPRules 查询指定作用域的 IP 访问控制规则
+func FindIPRules(ctx context.Context, scope string, tenantID uint, items *[]SystemIPRule) error {
+	err := pgdb.GetClient().WithContext(ctx).
+		Where("scope = ? AND tenant_id = ?", scope, tenantID).
+		Order("action, cidr").
+		Find(items).Error
+	if err != nil {
+		zap.L().Error("failed to find ip rules", zap.String("scope", scope), zap.Uint("tenantID", tenantID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ReplaceIPRules 全量覆盖指定作用域的规则，items 为空表示清空（物理删除旧规则，便于同一网段再次添加）
+func ReplaceIPRules(ctx context.Context, scope string, tenantID uint, items []SystemIPRule) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("scope = ? AND tenant_id = ?", scope, tenantID).Delete(&SystemIPRule{}).Error; err != nil {
+			zap.L().Error("failed to clear ip rules", zap.String("scope", scope), zap.Uint("tenantID", tenantID), zap.Error(err))
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			zap.L().Error("failed to create ip rules", zap.String("scope", scope), zap.Uint("tenantID", tenantID), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
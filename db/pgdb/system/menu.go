@@ -1,6 +1,9 @@
 package system
 
 import (
+	"context"
+	"fmt"
+
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
@@ -9,16 +12,16 @@ import (
 )
 
 // GetUserMenuData 获取用户菜单数据
-func GetUserMenuData(userID uint) ([]SystemMenu, []SystemMenuAuth, error) {
+func GetUserMenuData(ctx context.Context, userID uint) ([]SystemMenu, []SystemMenuAuth, error) {
 	// 获取用户信息及其角色
 	var user SystemUser
-	if err := pgdb.GetClient().Where(&SystemUser{Model: gorm.Model{ID: userID}}).First(&user).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Where(&SystemUser{Model: gorm.Model{ID: userID}}).First(&user).Error; err != nil {
 		zap.L().Error("failed to get user", zap.Error(err))
 		return nil, nil, err
 	}
 	// 获取该角色关联的所有菜单(包括权限)
 	var role SystemRole
-	if err := pgdb.GetClient().Preload("SystemMenus").
+	if err := pgdb.GetClient().WithContext(ctx).Preload("SystemMenus").
 		Preload("SystemMenuAuths").
 		Where("id = ?", user.RoleID).
 		First(&role).Error; err != nil {
@@ -29,14 +32,14 @@ func GetUserMenuData(userID uint) ([]SystemMenu, []SystemMenuAuth, error) {
 }
 
 // 获取菜单树(不带分页)
-func GetMenuData() ([]SystemMenu, []SystemMenuAuth, error) {
+func GetMenuData(ctx context.Context) ([]SystemMenu, []SystemMenuAuth, error) {
 	var menus []SystemMenu
-	if err := pgdb.GetClient().Find(&menus).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Find(&menus).Error; err != nil {
 		zap.L().Error("failed to get menus", zap.Error(err))
 		return nil, nil, err
 	}
 	var Auths []SystemMenuAuth
-	if err := pgdb.GetClient().Find(&Auths).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Find(&Auths).Error; err != nil {
 		zap.L().Error("failed to get menu Auths", zap.Error(err))
 		return nil, nil, err
 	}
@@ -44,22 +47,22 @@ func GetMenuData() ([]SystemMenu, []SystemMenuAuth, error) {
 }
 
 // GetMenuDataByRoleID 获取指定角色ID的菜单和权限数据
-func GetMenuDataByRoleID(roleID uint) ([]SystemMenu, []SystemMenuAuth, []uint, []uint, error) {
+func GetMenuDataByRoleID(ctx context.Context, roleID uint) ([]SystemMenu, []SystemMenuAuth, []uint, []uint, error) {
 	// 获取所有菜单
 	var allMenus []SystemMenu
-	if err := pgdb.GetClient().Find(&allMenus).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Find(&allMenus).Error; err != nil {
 		zap.L().Error("failed to get all menus", zap.Error(err))
 		return nil, nil, nil, nil, err
 	}
 	// 获取所有权限
 	var allAuths []SystemMenuAuth
-	if err := pgdb.GetClient().Find(&allAuths).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Find(&allAuths).Error; err != nil {
 		zap.L().Error("failed to get all menu auths", zap.Error(err))
 		return nil, nil, nil, nil, err
 	}
 	// 获取角色拥有的菜单ID列表
 	var role SystemRole
-	if err := pgdb.GetClient().Preload("SystemMenus").
+	if err := pgdb.GetClient().WithContext(ctx).Preload("SystemMenus").
 		Preload("SystemMenuAuths").
 		Where("id = ?", roleID).
 		First(&role).Error; err != nil {
@@ -79,8 +82,8 @@ func GetMenuDataByRoleID(roleID uint) ([]SystemMenu, []SystemMenuAuth, []uint, [
 }
 
 // 新增一个菜单
-func AddMenu(menu *SystemMenu) error {
-	if err := pgdb.GetClient().Create(menu).Error; err != nil {
+func AddMenu(ctx context.Context, menu *SystemMenu) error {
+	if err := pgdb.GetClient().WithContext(ctx).Create(menu).Error; err != nil {
 		zap.L().Error("failed to create menu", zap.Error(err))
 		return err
 	}
@@ -88,24 +91,24 @@ func AddMenu(menu *SystemMenu) error {
 }
 
 // 删除一个菜单
-func DeleteMenu(menu *SystemMenu) error {
-	if err := pgdb.GetClient().Delete(menu).Error; err != nil {
+func DeleteMenu(ctx context.Context, menu *SystemMenu) error {
+	if err := pgdb.GetClient().WithContext(ctx).Delete(menu).Error; err != nil {
 		zap.L().Error("failed to delete menu", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func UpdateMenu(menu *SystemMenu) error {
-	if err := pgdb.GetClient().Omit("created_at").Save(menu).Error; err != nil {
+func UpdateMenu(ctx context.Context, menu *SystemMenu) error {
+	if err := pgdb.GetClient().WithContext(ctx).Omit("created_at").Save(menu).Error; err != nil {
 		zap.L().Error("failed to update menu", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func GetMenu(menu *SystemMenu) error {
-	if err := pgdb.GetClient().Where(menu).First(menu).Error; err != nil {
+func GetMenu(ctx context.Context, menu *SystemMenu) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where(menu).First(menu).Error; err != nil {
 		zap.L().Error("failed to get menu", zap.Error(err))
 		return err
 	}
@@ -113,10 +116,10 @@ func GetMenu(menu *SystemMenu) error {
 }
 
 // FindMenuList 查询菜单列表(带分页)
-func FindMenuList(menu *SystemMenu, page, pageSize int) ([]SystemMenu, int64, error) {
+func FindMenuList(ctx context.Context, menu *SystemMenu, page, pageSize int) ([]SystemMenu, int64, error) {
 	var menus []SystemMenu
 	var total int64
-	db := pgdb.GetClient()
+	db := pgdb.GetClient().WithContext(ctx)
 
 	// 构建基础查询
 	query := db.Model(&SystemMenu{})
@@ -230,3 +233,42 @@ func FilterUintIDs(source []uint, allowedIDs []uint) []uint {
 	}
 	return result
 }
+
+// MenuImport 以显式 ID 写入的菜单与按钮权限，已存在的记录放在 Update* 中
+type MenuImport struct {
+	CreateMenus []SystemMenu
+	UpdateMenus []SystemMenu
+	CreateAuths []SystemMenuAuth
+	UpdateAuths []SystemMenuAuth
+}
+
+// ImportMenus 在单个事务中写入菜单与按钮权限（先菜单后按钮），任一记录失败时整体回滚
+func ImportMenus(ctx context.Context, data MenuImport) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range data.CreateMenus {
+			if err := tx.Create(&data.CreateMenus[i]).Error; err != nil {
+				zap.L().Error("failed to import menu", zap.Uint("id", data.CreateMenus[i].ID), zap.Error(err))
+				return fmt.Errorf("import menu %d: %w", data.CreateMenus[i].ID, err)
+			}
+		}
+		for i := range data.UpdateMenus {
+			if err := tx.Omit("created_at").Save(&data.UpdateMenus[i]).Error; err != nil {
+				zap.L().Error("failed to import menu", zap.Uint("id", data.UpdateMenus[i].ID), zap.Error(err))
+				return fmt.Errorf("import menu %d: %w", data.UpdateMenus[i].ID, err)
+			}
+		}
+		for i := range data.CreateAuths {
+			if err := tx.Create(&data.CreateAuths[i]).Error; err != nil {
+				zap.L().Error("failed to import menu auth", zap.Uint("id", data.CreateAuths[i].ID), zap.Error(err))
+				return fmt.Errorf("import auth %d: %w", data.CreateAuths[i].ID, err)
+			}
+		}
+		for i := range data.UpdateAuths {
+			if err := tx.Updates(&data.UpdateAuths[i]).Error; err != nil {
+				zap.L().Error("failed to import menu auth", zap.Uint("id", data.UpdateAuths[i].ID), zap.Error(err))
+				return fmt.Errorf("import auth %d: %w", data.UpdateAuths[i].ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -1,32 +1,21 @@
 package system
 
 import (
+	"context"
+	"embed"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
 	"api-server/config"
+	"api-server/db/pgdb"
+	"api-server/db/pgdb/migration"
 )
 
-func migrateTable(db *gorm.DB) error {
-	err := db.AutoMigrate(
-		&SystemTenant{},
-		&SystemDepartment{},
-		&SystemRole{},
-		&SystemMenu{},
-		&SystemMenuAuth{},
-		&SystemUser{},
-		&SystemUserLoginLog{},
-		&SystemTenantMenuScope{},
-		&SystemTenantAuthScope{},
-	)
-	if err != nil {
-		zap.L().Error("failed to migrate system model", zap.Error(err))
-		return err
-	}
-	return nil
-}
+//go:embed migrations/*.sql
+var sqlMigrations embed.FS
 
 func migrateData(db *gorm.DB) error {
 	err := db.Transaction(func(tx *gorm.DB) error {
@@ -34,14 +23,16 @@ func migrateData(db *gorm.DB) error {
 		var tenantCount int64
 		tx.Model(&SystemTenant{}).Count(&tenantCount)
 		if tenantCount == 0 {
-			// 创建默认租户
-			defaultTenant := SystemTenant{
-				Model:  gorm.Model{ID: 1},
-				Code:   config.DefaultTenantCode,
-				Name:   "平台管理",
-				Status: StatusEnabled,
-			}
-			err := tx.Create(&defaultTenant).Error
+			// 创建默认租户：只写基线表结构中的列，不依赖后续迁移新增的列
+			now := time.Now()
+			err := tx.Table("system_tenants").Create(map[string]any{
+				"id":         1,
+				"created_at": now,
+				"updated_at": now,
+				"code":       config.DefaultTenantCode,
+				"name":       "平台管理",
+				"status":     StatusEnabled,
+			}).Error
 			if err != nil {
 				zap.L().Error("failed to create default tenant", zap.Error(err))
 				return err
@@ -174,6 +165,11 @@ func migrateData(db *gorm.DB) error {
 	return err
 }
 
+// ResetSequences 将自增序列对齐到各表当前最大 ID；以显式 ID 写入数据（如菜单导入）后需调用
+func ResetSequences(ctx context.Context) error {
+	return resetSequences(pgdb.GetClient().WithContext(ctx))
+}
+
 func resetSequences(db *gorm.DB) error {
 	tables := []string{
 		"system_tenants", "system_menus", "system_roles", "system_departments", "system_users",
@@ -192,19 +188,21 @@ func resetSequences(db *gorm.DB) error {
 	return nil
 }
 
-func Migrate(db *gorm.DB) error {
-	err := migrateTable(db)
+// Migrations 返回 system 模块的全部迁移，由 migration.Migrator 按版本执行。
+// 版本 1 为 migrations/ 下固定的基线表结构 SQL，版本 2 写入初始数据（均可重复执行，已有数据库首次升级时也能安全应用）；
+// 此后的表结构或数据变更请新增 migrations/ 下的 SQL 文件或追加 Go 步骤，不要再修改已发布的版本。
+func Migrations() ([]migration.Migration, error) {
+	files, err := migration.LoadSQL(sqlMigrations, "migrations")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = migrateData(db)
-	if err != nil {
-		return err
-	}
-	// 添加序列重置操作
-	err = resetSequences(db)
-	if err != nil {
-		return err
+	steps := []migration.Migration{
+		{Version: 2, Name: "seed_default_data", Up: func(tx *gorm.DB) error {
+			if err := migrateData(tx); err != nil {
+				return err
+			}
+			return resetSequences(tx)
+		}},
 	}
-	return nil
+	return append(steps, files...), nil
 }
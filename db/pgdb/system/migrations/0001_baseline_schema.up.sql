@@ -0,0 +1,155 @@
+-- 基线表结构：与引入版本化迁移前 AutoMigrate 建出的结构一致，此后不再修改。
+-- 已有数据库首次升级时各表均已存在，IF NOT EXISTS 保证可安全重复执行；新增列、表一律通过后续版本的迁移完成。
+CREATE TABLE IF NOT EXISTS system_tenants (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    code       text   NOT NULL,
+    name       text   NOT NULL,
+    contact    text,
+    phone      text,
+    email      text,
+    status     bigint DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_system_tenants_deleted_at ON system_tenants (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_system_tenants_code ON system_tenants (code);
+
+CREATE TABLE IF NOT EXISTS system_departments (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    tenant_id  bigint NOT NULL,
+    name       text,
+    sort       bigint,
+    status     bigint,
+    CONSTRAINT fk_system_tenants_departments FOREIGN KEY (tenant_id) REFERENCES system_tenants (id)
+);
+CREATE INDEX IF NOT EXISTS idx_system_departments_deleted_at ON system_departments (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_system_departments_tenant_id ON system_departments (tenant_id);
+
+CREATE TABLE IF NOT EXISTS system_roles (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    tenant_id  bigint NOT NULL,
+    name       text,
+    "desc"     text,
+    status     bigint,
+    CONSTRAINT fk_system_tenants_roles FOREIGN KEY (tenant_id) REFERENCES system_tenants (id)
+);
+CREATE INDEX IF NOT EXISTS idx_system_roles_deleted_at ON system_roles (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_system_roles_tenant_id ON system_roles (tenant_id);
+
+CREATE TABLE IF NOT EXISTS system_menus (
+    id              bigserial PRIMARY KEY,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    path            text,
+    name            text,
+    component       text,
+    title           text,
+    icon            text,
+    show_badge      bigint,
+    show_text_badge text,
+    is_hide         bigint,
+    is_hide_tab     bigint,
+    link            text,
+    is_iframe       bigint,
+    keep_alive      bigint,
+    is_first_level  bigint,
+    status          bigint,
+    level           bigint,
+    parent_id       bigint,
+    sort            bigint
+);
+CREATE INDEX IF NOT EXISTS idx_system_menus_deleted_at ON system_menus (deleted_at);
+
+CREATE TABLE IF NOT EXISTS system_menu_auths (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    menu_id    bigint,
+    mark       text,
+    title      text,
+    CONSTRAINT fk_system_menus_system_menu_auths FOREIGN KEY (menu_id) REFERENCES system_menus (id)
+);
+CREATE INDEX IF NOT EXISTS idx_system_menu_auths_deleted_at ON system_menu_auths (deleted_at);
+
+CREATE TABLE IF NOT EXISTS system_roles__system_menus (
+    system_role_id bigint,
+    system_menu_id bigint,
+    PRIMARY KEY (system_role_id, system_menu_id),
+    CONSTRAINT fk_system_roles__system_menus_system_role FOREIGN KEY (system_role_id) REFERENCES system_roles (id),
+    CONSTRAINT fk_system_roles__system_menus_system_menu FOREIGN KEY (system_menu_id) REFERENCES system_menus (id)
+);
+
+CREATE TABLE IF NOT EXISTS system_roles__system_auths (
+    system_role_id      bigint,
+    system_menu_auth_id bigint,
+    PRIMARY KEY (system_role_id, system_menu_auth_id),
+    CONSTRAINT fk_system_roles__system_auths_system_role FOREIGN KEY (system_role_id) REFERENCES system_roles (id),
+    CONSTRAINT fk_system_roles__system_auths_system_menu_auth FOREIGN KEY (system_menu_auth_id) REFERENCES system_menu_auths (id)
+);
+
+CREATE TABLE IF NOT EXISTS system_users (
+    id            bigserial PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz,
+    tenant_id     bigint NOT NULL,
+    department_id bigint,
+    role_id       bigint,
+    name          text,
+    username      text,
+    account       text,
+    password      text,
+    phone         text,
+    gender        bigint,
+    status        bigint,
+    CONSTRAINT fk_system_tenants_system_users FOREIGN KEY (tenant_id) REFERENCES system_tenants (id),
+    CONSTRAINT fk_system_departments_system_users FOREIGN KEY (department_id) REFERENCES system_departments (id),
+    CONSTRAINT fk_system_roles_system_users FOREIGN KEY (role_id) REFERENCES system_roles (id)
+);
+CREATE INDEX IF NOT EXISTS idx_system_users_deleted_at ON system_users (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_system_users_tenant_id ON system_users (tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_account ON system_users (tenant_id, account);
+
+CREATE TABLE IF NOT EXISTS system_user_login_logs (
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    tenant_code  text,
+    user_name    text,
+    password     text,
+    ip           text,
+    login_status text
+);
+CREATE INDEX IF NOT EXISTS idx_system_user_login_logs_deleted_at ON system_user_login_logs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS system_tenant_menu_scopes (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    tenant_id  bigint NOT NULL,
+    menu_id    bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_system_tenant_menu_scopes_deleted_at ON system_tenant_menu_scopes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tenant_menu ON system_tenant_menu_scopes (tenant_id, menu_id);
+
+CREATE TABLE IF NOT EXISTS system_tenant_auth_scopes (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    tenant_id  bigint NOT NULL,
+    auth_id    bigint NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_system_tenant_auth_scopes_deleted_at ON system_tenant_auth_scopes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tenant_auth ON system_tenant_auth_scopes (tenant_id, auth_id);
@@ -0,0 +1,2 @@
+-- 恢复早期的账号全局唯一索引。不同租户已存在相同账号时建索引失败，回滚随事务整体撤销
+CREATE UNIQUE INDEX IF NOT EXISTS idx_system_users_account ON system_users (account);
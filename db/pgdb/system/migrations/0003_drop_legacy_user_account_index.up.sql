@@ -0,0 +1,3 @@
+-- 早期版本在 system_users.account 上建立了全局唯一索引，多租户后改为 (tenant_id, account) 联合唯一索引
+-- idx_tenant_account。AutoMigrate 只会新增索引、不会删除旧索引，导致不同租户无法使用相同账号。
+DROP INDEX IF EXISTS idx_system_users_account;
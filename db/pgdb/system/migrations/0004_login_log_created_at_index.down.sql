@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_system_user_login_logs_created_at;
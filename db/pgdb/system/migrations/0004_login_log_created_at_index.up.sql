@@ -0,0 +1,2 @@
+-- 登录日志列表按 created_at 倒序分页
+CREATE INDEX IF NOT EXISTS idx_system_user_login_logs_created_at ON system_user_login_logs (created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE system_tenants DROP COLUMN IF EXISTS allowed_origins;
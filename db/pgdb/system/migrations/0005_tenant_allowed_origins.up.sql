@@ -0,0 +1,2 @@
+-- 租户前端允许跨域的来源（逗号分隔），与配置文件 cors.allow_origins 合并生效
+ALTER TABLE system_tenants ADD COLUMN IF NOT EXISTS allowed_origins text NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+-- IP 访问控制名单：scope 为 global / tenant / platform，action 为 allow / deny，每行一个规范化后的 CIDR
+CREATE TABLE IF NOT EXISTS system_ip_rules (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    scope      varchar(16) NOT NULL,
+    tenant_id  bigint      NOT NULL DEFAULT 0,
+    action     varchar(8)  NOT NULL,
+    cidr       varchar(64) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_system_ip_rules_deleted_at ON system_ip_rules (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ip_rule ON system_ip_rules (scope, tenant_id, action, cidr);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS system_tenant_rate_limits;
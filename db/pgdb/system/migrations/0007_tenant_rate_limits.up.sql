@@ -0,0 +1,15 @@
+-- 平台为租户设置的限流策略覆盖，按 (tenant_id, route_group) 唯一
+CREATE TABLE IF NOT EXISTS system_tenant_rate_limits (
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    tenant_id    bigint      NOT NULL,
+    route_group  varchar(64) NOT NULL,
+    tenant_rate  decimal,
+    tenant_burst bigint,
+    user_rate    decimal,
+    user_burst   bigint
+);
+CREATE INDEX IF NOT EXISTS idx_system_tenant_rate_limits_deleted_at ON system_tenant_rate_limits (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_rate_limit ON system_tenant_rate_limits (tenant_id, route_group);
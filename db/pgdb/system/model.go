@@ -5,15 +5,16 @@ import "gorm.io/gorm"
 // Tenant 租户/企业表
 type SystemTenant struct {
 	gorm.Model
-	Code        string             `json:"code,omitempty" gorm:"uniqueIndex;not null"` // 企业编号，唯一
-	Name        string             `json:"name,omitempty" gorm:"not null"`             // 企业名称
-	Contact     string             `json:"contact,omitempty"`                          // 联系人
-	Phone       string             `json:"phone,omitempty"`                            // 联系电话
-	Email       string             `json:"email,omitempty"`                            // 邮箱
-	Status      uint               `json:"status,omitempty" gorm:"default:1"`          // 状态(StatusEnabled: 启用, StatusDisabled: 禁用)
-	SystemUsers []SystemUser       `json:"users,omitempty" gorm:"foreignKey:TenantID"`
-	Departments []SystemDepartment `json:"departments,omitempty" gorm:"foreignKey:TenantID"`
-	Roles       []SystemRole       `json:"roles,omitempty" gorm:"foreignKey:TenantID"`
+	Code           string             `json:"code,omitempty" gorm:"uniqueIndex;not null"`           // 企业编号，唯一
+	Name           string             `json:"name,omitempty" gorm:"not null"`                       // 企业名称
+	Contact        string             `json:"contact,omitempty"`                                    // 联系人
+	Phone          string             `json:"phone,omitempty"`                                      // 联系电话
+	Email          string             `json:"email,omitempty"`                                      // 邮箱
+	Status         uint               `json:"status,omitempty" gorm:"default:1"`                    // 状态(StatusEnabled: 启用, StatusDisabled: 禁用)
+	AllowedOrigins string             `json:"allowed_origins,omitempty" gorm:"not null;default:''"` // 前端允许跨域的来源（逗号分隔，支持 https://*.example.com）
+	SystemUsers    []SystemUser       `json:"users,omitempty" gorm:"foreignKey:TenantID"`
+	Departments    []SystemDepartment `json:"departments,omitempty" gorm:"foreignKey:TenantID"`
+	Roles          []SystemRole       `json:"roles,omitempty" gorm:"foreignKey:TenantID"`
 }
 
 // Department 部门表
@@ -102,6 +103,26 @@ type SystemTenantMenuScope struct {
 	MenuID   uint `json:"menu_id,omitempty" gorm:"not null;index:idx_tenant_menu,uniqueIndex:idx_tenant_menu"`
 }
 
+// SystemTenantRateLimit 平台为单个租户设置的限流策略覆盖（按路由组），优先于配置文件中的默认策略
+type SystemTenantRateLimit struct {
+	gorm.Model
+	TenantID    uint    `json:"tenant_id,omitempty" gorm:"not null;uniqueIndex:idx_tenant_rate_limit"`
+	RouteGroup  string  `json:"route_group,omitempty" gorm:"not null;size:64;uniqueIndex:idx_tenant_rate_limit"` // 路由组，如 system / platform
+	TenantRate  float64 `json:"tenant_rate"`                                                                     // 租户级每秒放行数，<=0 表示不限
+	TenantBurst int     `json:"tenant_burst"`                                                                    // 租户级突发容量
+	UserRate    float64 `json:"user_rate"`                                                                       // 用户级每秒放行数，<=0 表示不限
+	UserBurst   int     `json:"user_burst"`                                                                      // 用户级突发容量
+}
+
+// SystemIPRule IP 访问控制规则，每行一个 CIDR；作用域为 global（全局）、tenant（单个租户）或 platform（平台管理接口）
+type SystemIPRule struct {
+	gorm.Model
+	Scope    string `json:"scope,omitempty" gorm:"not null;size:16;uniqueIndex:idx_ip_rule"`            // 作用域：global / tenant / platform
+	TenantID uint   `json:"tenant_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_ip_rule"`      // 作用域为 tenant 时的租户 ID，其它作用域为 0
+	Action   string `json:"action,omitempty" gorm:"not null;size:8;uniqueIndex:idx_ip_rule"`            // allow 允许名单 / deny 拒绝名单
+	CIDR     string `json:"cidr,omitempty" gorm:"column:cidr;not null;size:64;uniqueIndex:idx_ip_rule"` // 规范化后的网段，如 203.0.113.0/24
+}
+
 // SystemTenantAuthScope 定义每个租户可用的按钮权限范围
 type SystemTenantAuthScope struct {
 	gorm.Model
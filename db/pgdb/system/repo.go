@@ -0,0 +1,257 @@
+package system
+
+import "context"
+
+// 以下类型是 domain/admin/repo 中各仓储接口基于 GORM 的实现，方法直接委托给本包已有的查询函数。
+
+// UserRepo 用户仓储
+type UserRepo struct{}
+
+func (UserRepo) List(ctx context.Context, filter *SystemUser, page, pageSize int) ([]UserWithRelations, int64, error) {
+	return FindUserList(ctx, filter, page, pageSize)
+}
+
+func (UserRepo) Get(ctx context.Context, user *SystemUser) error {
+	return GetUser(ctx, user)
+}
+
+func (UserRepo) Create(ctx context.Context, user *SystemUser) error {
+	return AddUser(ctx, user)
+}
+
+func (UserRepo) Update(ctx context.Context, user *SystemUser) error {
+	return UpdateUser(ctx, user)
+}
+
+func (UserRepo) Delete(ctx context.Context, user *SystemUser) error {
+	return DeleteUser(ctx, user)
+}
+
+func (UserRepo) Verify(ctx context.Context, tenantCode, account, password string) (SystemUser, SystemTenant, error) {
+	return VerifyUser(ctx, tenantCode, account, password)
+}
+
+func (UserRepo) CountByDepartment(ctx context.Context, departmentID uint) (int64, error) {
+	var count int64
+	err := CountUsersByDepartmentID(ctx, departmentID, &count)
+	return count, err
+}
+
+// LoginLogRepo 登录日志仓储
+type LoginLogRepo struct{}
+
+func (LoginLogRepo) Create(ctx context.Context, log *SystemUserLoginLog) error {
+	return CreateLoginLog(ctx, log)
+}
+
+func (LoginLogRepo) List(ctx context.Context, filter *SystemUserLoginLog, page, pageSize int) ([]SystemUserLoginLog, int64, error) {
+	return FindLoginLogList(ctx, filter, page, pageSize)
+}
+
+// RoleRepo 角色仓储
+type RoleRepo struct{}
+
+func (RoleRepo) List(ctx context.Context, filter *SystemRole, page, pageSize int) ([]SystemRole, int64, error) {
+	return FindRoleList(ctx, filter, page, pageSize)
+}
+
+func (RoleRepo) Get(ctx context.Context, role *SystemRole) error {
+	return GetRole(ctx, role)
+}
+
+func (RoleRepo) Create(ctx context.Context, role *SystemRole) error {
+	return AddRole(ctx, role)
+}
+
+func (RoleRepo) Update(ctx context.Context, role *SystemRole) error {
+	return UpdateRole(ctx, role)
+}
+
+func (RoleRepo) Delete(ctx context.Context, role *SystemRole) error {
+	return DeleteRole(ctx, role)
+}
+
+func (RoleRepo) SaveMenus(ctx context.Context, roleID uint, menuIDs, authIDs []uint) error {
+	return SaveRoleMenuAssociations(ctx, roleID, menuIDs, authIDs)
+}
+
+// TenantRepo 租户仓储
+type TenantRepo struct{}
+
+func (TenantRepo) List(ctx context.Context, filter *SystemTenant, page, pageSize int) ([]SystemTenant, int64, error) {
+	return FindTenantList(ctx, filter, page, pageSize)
+}
+
+func (TenantRepo) Get(ctx context.Context, tenant *SystemTenant) error {
+	return GetTenant(ctx, tenant)
+}
+
+func (TenantRepo) Create(ctx context.Context, tenant *SystemTenant) error {
+	return AddTenant(ctx, tenant)
+}
+
+func (TenantRepo) Update(ctx context.Context, tenant *SystemTenant) error {
+	return UpdateTenant(ctx, tenant)
+}
+
+func (TenantRepo) Delete(ctx context.Context, tenant *SystemTenant) error {
+	return DeleteTenant(ctx, tenant)
+}
+
+func (TenantRepo) SetAllowedOrigins(ctx context.Context, tenantID uint, origins string) error {
+	return UpdateTenantAllowedOrigins(ctx, tenantID, origins)
+}
+
+func (TenantRepo) AllowedOrigins(ctx context.Context) ([]SystemTenant, error) {
+	var tenants []SystemTenant
+	err := FindTenantAllowedOrigins(ctx, &tenants)
+	return tenants, err
+}
+
+func (TenantRepo) SuggestByCode(ctx context.Context, code string, limit int) ([]SystemTenant, error) {
+	return SuggestTenantByCode(ctx, code, limit)
+}
+
+func (TenantRepo) MenuScope(ctx context.Context, tenantID uint) ([]uint, error) {
+	return GetTenantMenuScopeIDs(ctx, tenantID)
+}
+
+func (TenantRepo) AuthScope(ctx context.Context, tenantID uint) ([]uint, error) {
+	return GetTenantAuthScopeIDs(ctx, tenantID)
+}
+
+func (TenantRepo) SaveMenuScope(ctx context.Context, tenantID uint, menuIDs []uint) error {
+	return SaveTenantMenuScope(ctx, tenantID, menuIDs)
+}
+
+func (TenantRepo) SaveAuthScope(ctx context.Context, tenantID uint, authIDs []uint) error {
+	return SaveTenantAuthScope(ctx, tenantID, authIDs)
+}
+
+func (TenantRepo) PruneRoleAssociations(ctx context.Context, tenantID uint, menuIDs, authIDs []uint) error {
+	return PruneTenantRoleAssociations(ctx, tenantID, menuIDs, authIDs)
+}
+
+// DepartmentRepo 部门仓储
+type DepartmentRepo struct{}
+
+func (DepartmentRepo) List(ctx context.Context, filter *SystemDepartment, page, pageSize int) ([]SystemDepartment, int64, error) {
+	return FindDepartmentList(ctx, filter, page, pageSize)
+}
+
+func (DepartmentRepo) Get(ctx context.Context, department *SystemDepartment) error {
+	return GetDepartment(ctx, department)
+}
+
+func (DepartmentRepo) Create(ctx context.Context, department *SystemDepartment) error {
+	return AddDepartment(ctx, department)
+}
+
+func (DepartmentRepo) Update(ctx context.Context, department *SystemDepartment) error {
+	return UpdateDepartment(ctx, department)
+}
+
+func (DepartmentRepo) Delete(ctx context.Context, department *SystemDepartment) error {
+	return DeleteDepartment(ctx, department)
+}
+
+// MenuRepo 菜单与按钮权限仓储
+type MenuRepo struct{}
+
+func (MenuRepo) All(ctx context.Context) ([]SystemMenu, []SystemMenuAuth, error) {
+	return GetMenuData(ctx)
+}
+
+func (MenuRepo) ForUser(ctx context.Context, userID uint) ([]SystemMenu, []SystemMenuAuth, error) {
+	return GetUserMenuData(ctx, userID)
+}
+
+func (MenuRepo) ForRole(ctx context.Context, roleID uint) ([]SystemMenu, []SystemMenuAuth, []uint, []uint, error) {
+	return GetMenuDataByRoleID(ctx, roleID)
+}
+
+func (MenuRepo) List(ctx context.Context, filter *SystemMenu, page, pageSize int) ([]SystemMenu, int64, error) {
+	return FindMenuList(ctx, filter, page, pageSize)
+}
+
+func (MenuRepo) Get(ctx context.Context, menu *SystemMenu) error {
+	return GetMenu(ctx, menu)
+}
+
+func (MenuRepo) Create(ctx context.Context, menu *SystemMenu) error {
+	return AddMenu(ctx, menu)
+}
+
+func (MenuRepo) Update(ctx context.Context, menu *SystemMenu) error {
+	return UpdateMenu(ctx, menu)
+}
+
+func (MenuRepo) Delete(ctx context.Context, menu *SystemMenu) error {
+	return DeleteMenu(ctx, menu)
+}
+
+func (MenuRepo) Import(ctx context.Context, data MenuImport) error {
+	return ImportMenus(ctx, data)
+}
+
+func (MenuRepo) ListAuths(ctx context.Context, filter *SystemMenuAuth) ([]SystemMenuAuth, error) {
+	return FindMenuAuthList(ctx, filter)
+}
+
+func (MenuRepo) CreateAuth(ctx context.Context, auth *SystemMenuAuth) error {
+	return AddMenuAuth(ctx, auth)
+}
+
+func (MenuRepo) UpdateAuth(ctx context.Context, auth *SystemMenuAuth) error {
+	return UpdateMenuAuth(ctx, auth)
+}
+
+func (MenuRepo) DeleteAuth(ctx context.Context, auth *SystemMenuAuth) error {
+	return DeleteMenuAuth(ctx, auth)
+}
+
+// IPRuleRepo IP 访问控制规则仓储
+type IPRuleRepo struct{}
+
+func (IPRuleRepo) All(ctx context.Context) ([]SystemIPRule, error) {
+	var items []SystemIPRule
+	err := FindAllIPRules(ctx, &items)
+	return items, err
+}
+
+func (IPRuleRepo) Find(ctx context.Context, scope string, tenantID uint) ([]SystemIPRule, error) {
+	var items []SystemIPRule
+	err := FindIPRules(ctx, scope, tenantID, &items)
+	return items, err
+}
+
+func (IPRuleRepo) Replace(ctx context.Context, scope string, tenantID uint, items []SystemIPRule) error {
+	return ReplaceIPRules(ctx, scope, tenantID, items)
+}
+
+// RateLimitRepo 租户限流覆盖仓储
+type RateLimitRepo struct{}
+
+func (RateLimitRepo) All(ctx context.Context) ([]SystemTenantRateLimit, error) {
+	var items []SystemTenantRateLimit
+	err := FindAllTenantRateLimits(ctx, &items)
+	return items, err
+}
+
+func (RateLimitRepo) ForTenant(ctx context.Context, tenantID uint) ([]SystemTenantRateLimit, error) {
+	var items []SystemTenantRateLimit
+	err := FindTenantRateLimits(ctx, tenantID, &items)
+	return items, err
+}
+
+func (RateLimitRepo) Save(ctx context.Context, item *SystemTenantRateLimit) error {
+	return SaveTenantRateLimit(ctx, item)
+}
+
+func (RateLimitRepo) Delete(ctx context.Context, tenantID uint, routeGroup string) (int64, error) {
+	return DeleteTenantRateLimit(ctx, tenantID, routeGroup)
+}
+
+func (RateLimitRepo) DeleteTenant(ctx context.Context, tenantID uint) error {
+	return DeleteTenantRateLimits(ctx, tenantID)
+}
@@ -1,6 +1,8 @@
 package system
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
@@ -8,24 +10,24 @@ import (
 	"api-server/db/pgdb"
 )
 
-func UpdateRole(role *SystemRole) error {
-	if err := pgdb.GetClient().Updates(&role).Error; err != nil {
+func UpdateRole(ctx context.Context, role *SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Updates(&role).Error; err != nil {
 		zap.L().Error("failed to update role", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func AddRole(role *SystemRole) error {
-	if err := pgdb.GetClient().Create(&role).Error; err != nil {
+func AddRole(ctx context.Context, role *SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Create(&role).Error; err != nil {
 		zap.L().Error("failed to create role", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func DeleteRole(role *SystemRole) error {
-	if err := pgdb.GetClient().Delete(&role).Error; err != nil {
+func DeleteRole(ctx context.Context, role *SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Delete(&role).Error; err != nil {
 		zap.L().Error("failed to delete role", zap.Error(err))
 		return err
 	}
@@ -33,17 +35,26 @@ func DeleteRole(role *SystemRole) error {
 }
 
 // GetRole 获取单个角色信息
-func GetRole(role *SystemRole) error {
-	if err := pgdb.GetClient().Where(role).First(role).Error; err != nil {
+func GetRole(ctx context.Context, role *SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where(role).First(role).Error; err != nil {
 		zap.L().Error("failed to get role", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
+// FindRolesByTenant 查询指定租户下的所有角色
+func FindRolesByTenant(ctx context.Context, tenantID uint, roles *[]SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where("tenant_id = ?", tenantID).Find(roles).Error; err != nil {
+		zap.L().Error("failed to find roles by tenant", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 // FindAllRoles 查询所有角色
-func FindAllRoles(roles *[]SystemRole) error {
-	if err := pgdb.GetClient().Find(roles).Error; err != nil {
+func FindAllRoles(ctx context.Context, roles *[]SystemRole) error {
+	if err := pgdb.GetClient().WithContext(ctx).Find(roles).Error; err != nil {
 		zap.L().Error("failed to find all roles", zap.Error(err))
 		return err
 	}
@@ -51,10 +62,10 @@ func FindAllRoles(roles *[]SystemRole) error {
 }
 
 // FindRoleList 查询角色列表(带分页)
-func FindRoleList(role *SystemRole, page, pageSize int) ([]SystemRole, int64, error) {
+func FindRoleList(ctx context.Context, role *SystemRole, page, pageSize int) ([]SystemRole, int64, error) {
 	var roles []SystemRole
 	var total int64
-	db := pgdb.GetClient()
+	db := pgdb.GetClient().WithContext(ctx)
 
 	// 构建基础查询
 	query := db.Model(&SystemRole{})
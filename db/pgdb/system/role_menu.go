@@ -1,6 +1,8 @@
 package system
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
@@ -11,8 +13,8 @@ import (
 // 说明：
 // - 菜单（SystemMenus）与按钮权限（SystemMenuAuths）独立保存；
 // - 由上层（domain/api）负责完成“可分配范围”校验与 ID 提取。
-func SaveRoleMenuAssociations(roleID uint, menuIDs []uint, authIDs []uint) error {
-	return pgdb.GetClient().Transaction(func(tx *gorm.DB) error {
+func SaveRoleMenuAssociations(ctx context.Context, roleID uint, menuIDs []uint, authIDs []uint) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var role SystemRole
 		if err := tx.First(&role, roleID).Error; err != nil {
 			zap.L().Error("failed to find role", zap.Uint("role_id", roleID), zap.Error(err))
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 
 	"go.uber.org/zap"
@@ -11,9 +12,9 @@ import (
 )
 
 // GetTenantByCode 根据企业编号获取租户信息
-func GetTenantByCode(code string) (SystemTenant, error) {
+func GetTenantByCode(ctx context.Context, code string) (SystemTenant, error) {
 	var tenant SystemTenant
-	err := pgdb.GetClient().Where("code = ? AND status = 1", code).First(&tenant).Error
+	err := pgdb.GetClient().WithContext(ctx).Where("code = ? AND status = 1", code).First(&tenant).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return tenant, nil // 返回空租户，ID为0表示未找到
@@ -25,8 +26,8 @@ func GetTenantByCode(code string) (SystemTenant, error) {
 }
 
 // GetTenant 获取租户信息
-func GetTenant(tenant *SystemTenant) error {
-	if err := pgdb.GetClient().Where(tenant).First(tenant).Error; err != nil {
+func GetTenant(ctx context.Context, tenant *SystemTenant) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where(tenant).First(tenant).Error; err != nil {
 		zap.L().Error("failed to get tenant", zap.Error(err))
 		return err
 	}
@@ -34,8 +35,8 @@ func GetTenant(tenant *SystemTenant) error {
 }
 
 // AddTenant 添加租户
-func AddTenant(tenant *SystemTenant) error {
-	if err := pgdb.GetClient().Create(tenant).Error; err != nil {
+func AddTenant(ctx context.Context, tenant *SystemTenant) error {
+	if err := pgdb.GetClient().WithContext(ctx).Create(tenant).Error; err != nil {
 		zap.L().Error("failed to add tenant", zap.Error(err))
 		return err
 	}
@@ -43,17 +44,39 @@ func AddTenant(tenant *SystemTenant) error {
 }
 
 // UpdateTenant 更新租户
-func UpdateTenant(tenant *SystemTenant) error {
-	if err := pgdb.GetClient().Updates(tenant).Error; err != nil {
+func UpdateTenant(ctx context.Context, tenant *SystemTenant) error {
+	if err := pgdb.GetClient().WithContext(ctx).Updates(tenant).Error; err != nil {
 		zap.L().Error("failed to update tenant", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
+// UpdateTenantAllowedOrigins 更新租户允许跨域的来源（逗号分隔），空字符串表示清空
+func UpdateTenantAllowedOrigins(ctx context.Context, tenantID uint, origins string) error {
+	if err := pgdb.GetClient().WithContext(ctx).Model(&SystemTenant{}).Where("id = ?", tenantID).Update("allowed_origins", origins).Error; err != nil {
+		zap.L().Error("failed to update tenant allowed origins", zap.Uint("tenantID", tenantID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindTenantAllowedOrigins 查询配置了跨域来源的启用租户（仅 ID 与来源字段）
+func FindTenantAllowedOrigins(ctx context.Context, tenants *[]SystemTenant) error {
+	err := pgdb.GetClient().WithContext(ctx).
+		Select("id", "allowed_origins").
+		Where("status = ? AND allowed_origins <> ''", StatusEnabled).
+		Find(tenants).Error
+	if err != nil {
+		zap.L().Error("failed to find tenant allowed origins", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 // DeleteTenant 删除租户
-func DeleteTenant(tenant *SystemTenant) error {
-	if err := pgdb.GetClient().Delete(tenant).Error; err != nil {
+func DeleteTenant(ctx context.Context, tenant *SystemTenant) error {
+	if err := pgdb.GetClient().WithContext(ctx).Delete(tenant).Error; err != nil {
 		zap.L().Error("failed to delete tenant", zap.Error(err))
 		return err
 	}
@@ -61,10 +84,11 @@ func DeleteTenant(tenant *SystemTenant) error {
 }
 
 // FindTenantList 查询租户列表，支持分页
-func FindTenantList(tenant *SystemTenant, page, pageSize int) ([]SystemTenant, int64, error) {
+func FindTenantList(ctx context.Context, tenant *SystemTenant, page, pageSize int) ([]SystemTenant, int64, error) {
 	var tenants []SystemTenant
 	var total int64
-	db := pgdb.GetClient()
+	// 列表查询可容忍复制延迟，走只读副本
+	db := pgdb.Replica(ctx)
 
 	// 构建基础查询
 	baseQuery := db.Model(&SystemTenant{}).Where("deleted_at IS NULL")
@@ -102,8 +126,8 @@ func FindTenantList(tenant *SystemTenant, page, pageSize int) ([]SystemTenant, i
 }
 
 // FindAllTenants 查询所有租户
-func FindAllTenants(tenants *[]SystemTenant) error {
-	if err := pgdb.GetClient().Find(tenants).Error; err != nil {
+func FindAllTenants(ctx context.Context, tenants *[]SystemTenant) error {
+	if err := pgdb.GetClient().WithContext(ctx).Find(tenants).Error; err != nil {
 		zap.L().Error("failed to find all tenants", zap.Error(err))
 		return err
 	}
@@ -111,7 +135,7 @@ func FindAllTenants(tenants *[]SystemTenant) error {
 }
 
 // ValidateTenant 验证租户状态和权限
-func ValidateTenant(tenant *SystemTenant) error {
+func ValidateTenant(ctx context.Context, tenant *SystemTenant) error {
 	if tenant.Status != StatusEnabled {
 		return errors.New("tenant is disabled")
 	}
@@ -120,12 +144,12 @@ func ValidateTenant(tenant *SystemTenant) error {
 }
 
 // SuggestTenantByCode 根据代码进行模糊查询，返回前N条启用中的租户
-func SuggestTenantByCode(code string, limit int) ([]SystemTenant, error) {
+func SuggestTenantByCode(ctx context.Context, code string, limit int) ([]SystemTenant, error) {
 	var tenants []SystemTenant
 	if limit <= 0 {
 		limit = 10
 	}
-	db := pgdb.GetClient()
+	db := pgdb.GetClient().WithContext(ctx)
 	// 仅查询启用、未删除的租户，按创建时间倒序，模糊匹配code
 	err := db.Model(&SystemTenant{}).
 		Where("deleted_at IS NULL AND status = 1 AND code LIKE ?", "%"+code+"%").
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -10,12 +11,12 @@ import (
 )
 
 // GetTenantAuthScopeIDs 获取租户已授权的按钮权限ID集合
-func GetTenantAuthScopeIDs(tenantID uint) ([]uint, error) {
+func GetTenantAuthScopeIDs(ctx context.Context, tenantID uint) ([]uint, error) {
 	if tenantID == 0 {
 		return nil, nil
 	}
 	var scopes []SystemTenantAuthScope
-	if err := pgdb.GetClient().Where("tenant_id = ?", tenantID).Find(&scopes).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&scopes).Error; err != nil {
 		zap.L().Error("failed to get tenant auth scope", zap.Uint("tenantID", tenantID), zap.Error(err))
 		return nil, err
 	}
@@ -27,11 +28,11 @@ func GetTenantAuthScopeIDs(tenantID uint) ([]uint, error) {
 }
 
 // SaveTenantAuthScope 保存租户的按钮权限范围（全量覆盖）
-func SaveTenantAuthScope(tenantID uint, authIDs []uint) error {
+func SaveTenantAuthScope(ctx context.Context, tenantID uint, authIDs []uint) error {
 	if tenantID == 0 {
 		return fmt.Errorf("tenant id is required")
 	}
-	return pgdb.GetClient().Transaction(func(tx *gorm.DB) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("tenant_id = ?", tenantID).Delete(&SystemTenantAuthScope{}).Error; err != nil {
 			zap.L().Error("failed to clear tenant auth scope", zap.Uint("tenantID", tenantID), zap.Error(err))
 			return err
@@ -0,0 +1,65 @@
+package system
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+
+	"api-server/db/pgdb"
+)
+
+// FindAllTenantRateLimits 查询所有租户限流覆盖
+func FindAllTenantRateLimits(ctx context.Context, items *[]SystemTenantRateLimit) error {
+	if err := pgdb.GetClient().WithContext(ctx).Order("tenant_id, route_group").Find(items).Error; err != nil {
+		zap.L().Error("failed to find tenant rate limits", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindTenantRateLimits 查询指定租户的限流覆盖
+func FindTenantRateLimits(ctx context.Context, tenantID uint, items *[]SystemTenantRateLimit) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where("tenant_id = ?", tenantID).Order("route_group").Find(items).Error; err != nil {
+		zap.L().Error("failed to find tenant rate limits", zap.Uint("tenantID", tenantID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// SaveTenantRateLimit 新增或更新租户在某个路由组上的限流覆盖
+func SaveTenantRateLimit(ctx context.Context, item *SystemTenantRateLimit) error {
+	err := pgdb.GetClient().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "route_group"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tenant_rate", "tenant_burst", "user_rate", "user_burst", "updated_at"}),
+	}).Create(item).Error
+	if err != nil {
+		zap.L().Error("failed to save tenant rate limit", zap.Uint("tenantID", item.TenantID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteTenantRateLimit 删除租户在某个路由组上的限流覆盖（物理删除，便于再次新增）
+func DeleteTenantRateLimit(ctx context.Context, tenantID uint, routeGroup string) (int64, error) {
+	result := pgdb.GetClient().WithContext(ctx).Unscoped().
+		Where("tenant_id = ? AND route_group = ?", tenantID, routeGroup).
+		Delete(&SystemTenantRateLimit{})
+	if result.Error != nil {
+		zap.L().Error("failed to delete tenant rate limit", zap.Uint("tenantID", tenantID), zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteTenantRateLimits 删除租户的全部限流覆盖，用于租户删除后的清理
+func DeleteTenantRateLimits(ctx context.Context, tenantID uint) error {
+	err := pgdb.GetClient().WithContext(ctx).Unscoped().
+		Where("tenant_id = ?", tenantID).
+		Delete(&SystemTenantRateLimit{}).Error
+	if err != nil {
+		zap.L().Error("failed to delete tenant rate limits", zap.Uint("tenantID", tenantID), zap.Error(err))
+		return err
+	}
+	return nil
+}
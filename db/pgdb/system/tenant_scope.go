@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -9,12 +10,12 @@ import (
 	"api-server/db/pgdb"
 )
 
-func GetTenantMenuScopeIDs(tenantID uint) ([]uint, error) {
+func GetTenantMenuScopeIDs(ctx context.Context, tenantID uint) ([]uint, error) {
 	if tenantID == 0 {
 		return nil, nil
 	}
 	var scopes []SystemTenantMenuScope
-	if err := pgdb.GetClient().Where("tenant_id = ?", tenantID).Find(&scopes).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&scopes).Error; err != nil {
 		zap.L().Error("failed to get tenant menu scope", zap.Uint("tenantID", tenantID), zap.Error(err))
 		return nil, err
 	}
@@ -25,11 +26,11 @@ func GetTenantMenuScopeIDs(tenantID uint) ([]uint, error) {
 	return menuIDs, nil
 }
 
-func SaveTenantMenuScope(tenantID uint, menuIDs []uint) error {
+func SaveTenantMenuScope(ctx context.Context, tenantID uint, menuIDs []uint) error {
 	if tenantID == 0 {
 		return fmt.Errorf("tenant id is required")
 	}
-	return pgdb.GetClient().Transaction(func(tx *gorm.DB) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("tenant_id = ?", tenantID).Delete(&SystemTenantMenuScope{}).Error; err != nil {
 			zap.L().Error("failed to clear tenant menu scope", zap.Uint("tenantID", tenantID), zap.Error(err))
 			return err
@@ -62,11 +63,11 @@ func SaveTenantMenuScope(tenantID uint, menuIDs []uint) error {
 
 // PruneTenantRoleAssociations 当平台调整租户的菜单/按钮范围后，
 // 自动清理该租户下所有角色中超出范围的角色-菜单/角色-按钮关联。
-func PruneTenantRoleAssociations(tenantID uint, allowedMenuIDs []uint, allowedAuthIDs []uint) error {
+func PruneTenantRoleAssociations(ctx context.Context, tenantID uint, allowedMenuIDs []uint, allowedAuthIDs []uint) error {
 	if tenantID == 0 {
 		return fmt.Errorf("tenant id is required")
 	}
-	return pgdb.GetClient().Transaction(func(tx *gorm.DB) error {
+	return pgdb.GetClient().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 查找该租户下所有角色ID
 		var roleIDs []uint
 		if err := tx.Model(&SystemRole{}).Where("tenant_id = ?", tenantID).Pluck("id", &roleIDs).Error; err != nil {
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 
 	"go.uber.org/zap"
@@ -24,12 +25,12 @@ func VerifyPassword(password, hashedPassword, passwordType string) bool {
 }
 
 // VerifyUser 验证用户登录（多租户版本）
-func VerifyUser(tenantCode, account, password string) (SystemUser, SystemTenant, error) {
+func VerifyUser(ctx context.Context, tenantCode, account, password string) (SystemUser, SystemTenant, error) {
 	user := SystemUser{}
 	tenant := SystemTenant{}
 
 	// 首先验证租户
-	tenant, err := GetTenantByCode(tenantCode)
+	tenant, err := GetTenantByCode(ctx, tenantCode)
 	if err != nil {
 		zap.L().Error("failed to get tenant", zap.Error(err))
 		return user, tenant, err
@@ -40,13 +41,13 @@ func VerifyUser(tenantCode, account, password string) (SystemUser, SystemTenant,
 	}
 
 	// 验证租户状态
-	if err := ValidateTenant(&tenant); err != nil {
+	if err := ValidateTenant(ctx, &tenant); err != nil {
 		zap.L().Error("tenant validation failed", zap.Error(err))
 		return user, tenant, err
 	}
 
 	// 根据租户ID和账号查找用户
-	err = pgdb.GetClient().Where("tenant_id = ? AND account = ?", tenant.ID, account).First(&user).Error
+	err = pgdb.GetClient().WithContext(ctx).Where("tenant_id = ? AND account = ?", tenant.ID, account).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return user, tenant, nil // 返回空用户，ID为0表示未找到
@@ -65,8 +66,8 @@ func VerifyUser(tenantCode, account, password string) (SystemUser, SystemTenant,
 }
 
 // 记录用户登录日志
-func CreateLoginLog(log *SystemUserLoginLog) error {
-	if err := pgdb.GetClient().Create(log).Error; err != nil {
+func CreateLoginLog(ctx context.Context, log *SystemUserLoginLog) error {
+	if err := pgdb.GetClient().WithContext(ctx).Create(log).Error; err != nil {
 		zap.L().Error("failed to record login log", zap.Error(err))
 		return err
 	}
@@ -74,10 +75,11 @@ func CreateLoginLog(log *SystemUserLoginLog) error {
 }
 
 // FindLoginLogList 查询登录日志列表，支持分页和按用户名、IP查询
-func FindLoginLogList(loginLog *SystemUserLoginLog, page, pageSize int) ([]SystemUserLoginLog, int64, error) {
+func FindLoginLogList(ctx context.Context, loginLog *SystemUserLoginLog, page, pageSize int) ([]SystemUserLoginLog, int64, error) {
 	var loginLogs []SystemUserLoginLog
 	var total int64
-	db := pgdb.GetClient()
+	// 列表查询可容忍复制延迟，走只读副本
+	db := pgdb.Replica(ctx)
 
 	// 构建基础查询
 	baseQuery := db.Model(&SystemUserLoginLog{}).Where("deleted_at IS NULL")
@@ -119,10 +121,11 @@ type UserWithRelations struct {
 	DepartmentName string `json:"department_name"`
 }
 
-func FindUserList(user *SystemUser, page, pageSize int) ([]UserWithRelations, int64, error) {
+func FindUserList(ctx context.Context, user *SystemUser, page, pageSize int) ([]UserWithRelations, int64, error) {
 	var usersWithRelations []UserWithRelations
 	var total int64
-	db := pgdb.GetClient()
+	// 列表查询可容忍复制延迟，走只读副本
+	db := pgdb.Replica(ctx)
 	// 构建基础查询
 	baseQuery := db.Table("system_users").
 		Joins("left join system_roles on system_users.role_id = system_roles.id").
@@ -174,7 +177,7 @@ func FindUserList(user *SystemUser, page, pageSize int) ([]UserWithRelations, in
 	return usersWithRelations, total, nil
 }
 
-func AddUser(user *SystemUser) error {
+func AddUser(ctx context.Context, user *SystemUser) error {
 	// 新用户使用bcrypt加密
 	hashedPassword, err := HashPassword(user.Password)
 	if err != nil {
@@ -184,22 +187,22 @@ func AddUser(user *SystemUser) error {
 
 	user.Password = hashedPassword
 
-	if err := pgdb.GetClient().Create(user).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Create(user).Error; err != nil {
 		zap.L().Error("failed to add user", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func GetUser(user *SystemUser) error {
-	if err := pgdb.GetClient().Where(user).First(user).Error; err != nil {
+func GetUser(ctx context.Context, user *SystemUser) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where(user).First(user).Error; err != nil {
 		zap.L().Error("failed to get user", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func UpdateUser(user *SystemUser) error {
+func UpdateUser(ctx context.Context, user *SystemUser) error {
 	// 如果更新密码，使用bcrypt加密
 	if user.Password != "" {
 		hashedPassword, err := HashPassword(user.Password)
@@ -210,15 +213,15 @@ func UpdateUser(user *SystemUser) error {
 		user.Password = hashedPassword
 	}
 
-	if err := pgdb.GetClient().Updates(user).Error; err != nil {
+	if err := pgdb.GetClient().WithContext(ctx).Updates(user).Error; err != nil {
 		zap.L().Error("failed to update user", zap.Error(err))
 		return err
 	}
 	return nil
 }
 
-func DeleteUser(user *SystemUser) error {
-	if err := pgdb.GetClient().Delete(user).Error; err != nil {
+func DeleteUser(ctx context.Context, user *SystemUser) error {
+	if err := pgdb.GetClient().WithContext(ctx).Delete(user).Error; err != nil {
 		zap.L().Error("failed to delete user", zap.Error(err))
 		return err
 	}
@@ -226,10 +229,28 @@ func DeleteUser(user *SystemUser) error {
 }
 
 // FindAllUsers 查询所有用户
-func FindAllUsers(users *[]SystemUser) error {
-	if err := pgdb.GetClient().Find(users).Error; err != nil {
+func FindAllUsers(ctx context.Context, users *[]SystemUser) error {
+	if err := pgdb.GetClient().WithContext(ctx).Find(users).Error; err != nil {
 		zap.L().Error("failed to find all users", zap.Error(err))
 		return err
 	}
 	return nil
 }
+
+// FindUsersByRole 查询指定角色下的所有用户
+func FindUsersByRole(ctx context.Context, roleID uint, users *[]SystemUser) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where("role_id = ?", roleID).Find(users).Error; err != nil {
+		zap.L().Error("failed to find users by role", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// FindUsersByTenant 查询指定租户下的所有用户
+func FindUsersByTenant(ctx context.Context, tenantID uint, users *[]SystemUser) error {
+	if err := pgdb.GetClient().WithContext(ctx).Where("tenant_id = ?", tenantID).Find(users).Error; err != nil {
+		zap.L().Error("failed to find users by tenant", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -2,31 +2,40 @@ package rdb
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	"api-server/config"
 )
 
-// redisDB redis连接池
-var client *redis.Client
+// client redis 连接池；单机、哨兵、集群模式统一以 UniversalClient 暴露
+var client redis.UniversalClient
 
-// InitRedisClient 初始化客户端连接池
+// Init 按 redis.mode 初始化客户端连接池
 func Init() error {
-	client = redis.NewClient(&redis.Options{
-		Addr:         config.RedisHost,
-		Password:     config.RedisPassword,
-		PoolSize:     100,
-		MinIdleConns: 50,
-	})
+	c, err := newClient()
+	if err != nil {
+		zap.L().Error("redis配置无效", zap.Error(err))
+		return err
+	}
+	client = c
 	if err := client.Ping(context.Background()).Err(); err != nil {
-		zap.L().Error("redis连接失败", zap.Error(err))
+		zap.L().Error("redis连接失败",
+			zap.String("mode", config.RedisMode),
+			zap.Strings("addrs", config.RedisAddrList()),
+			zap.Error(err),
+		)
 		return err
 	}
 	return nil
 }
 
-func GetClient() *redis.Client {
+func GetClient() redis.UniversalClient {
 	if client == nil {
 		Init()
 	}
@@ -34,6 +43,98 @@ func GetClient() *redis.Client {
 }
 
 func CloseClient() {
+	if client == nil {
+		return
+	}
 	client.Close()
 	client = nil
 }
+
+// SetClient 替换全局客户端（用于测试注入 miniredis 等）
+func SetClient(c redis.UniversalClient) {
+	client = c
+}
+
+// ValidateConfig 校验 Redis 配置（地址、TLS 证书等）能否构建客户端，供启动阶段提前暴露配置错误
+func ValidateConfig() error {
+	_, err := universalOptions()
+	return err
+}
+
+// newClient 根据部署模式创建客户端。
+// 显式区分模式而不使用 redis.NewUniversalClient 的自动推断，避免单节点集群被误判为单机。
+func newClient() (redis.UniversalClient, error) {
+	opts, err := universalOptions()
+	if err != nil {
+		return nil, err
+	}
+	switch config.RedisMode {
+	case config.RedisModeSentinel:
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case config.RedisModeCluster:
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return redis.NewClient(opts.Simple()), nil
+	}
+}
+
+// universalOptions 将全局配置转换为 go-redis 连接参数
+func universalOptions() (*redis.UniversalOptions, error) {
+	addrs := config.RedisAddrList()
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("redis address is empty (redis.host / redis.addrs)")
+	}
+	opts := &redis.UniversalOptions{
+		Addrs:            addrs,
+		MasterName:       config.RedisMasterName,
+		Username:         config.RedisUsername,
+		Password:         config.RedisPassword,
+		DB:               config.RedisDB,
+		SentinelUsername: config.RedisSentinelUser,
+		SentinelPassword: config.RedisSentinelPass,
+		PoolSize:         config.RedisPoolSize,
+		MinIdleConns:     config.RedisMinIdleConns,
+		DialTimeout:      config.RedisDialTimeout,
+		ReadTimeout:      config.RedisReadTimeout,
+		WriteTimeout:     config.RedisWriteTimeout,
+		PoolTimeout:      config.RedisPoolTimeout,
+		// 使用调用方 ctx 的截止时间作为读写超时，请求取消后命令随之中止
+		ContextTimeoutEnabled: true,
+	}
+	if config.RedisTLSEnabled {
+		tlsConfig, err := tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsConfig
+	}
+	return opts, nil
+}
+
+// tlsConfig 构建 Redis TLS 配置：可选自定义 CA 与客户端证书
+func tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         config.RedisTLSServerName,
+		InsecureSkipVerify: config.RedisTLSInsecureSkipVerify,
+	}
+	if config.RedisTLSCAFile != "" {
+		pem, err := os.ReadFile(config.RedisTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate found in redis ca file %s", config.RedisTLSCAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if config.RedisTLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.RedisTLSCertFile, config.RedisTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package rdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"api-server/config"
+)
+
+func setRedisConfig(t *testing.T, mode string, addrs ...string) {
+	t.Helper()
+	prevMode, prevHost, prevAddrs, prevMaster := config.RedisMode, config.RedisHost, config.RedisAddrs, config.RedisMasterName
+	prevTLS, prevCA := config.RedisTLSEnabled, config.RedisTLSCAFile
+	t.Cleanup(func() {
+		config.RedisMode, config.RedisHost, config.RedisAddrs, config.RedisMasterName = prevMode, prevHost, prevAddrs, prevMaster
+		config.RedisTLSEnabled, config.RedisTLSCAFile = prevTLS, prevCA
+	})
+	config.RedisMode = mode
+	config.RedisHost = ""
+	config.RedisAddrs = addrs
+	config.RedisMasterName = "mymaster"
+	config.RedisTLSEnabled = false
+	config.RedisTLSCAFile = ""
+}
+
+func TestNewClientSelectsMode(t *testing.T) {
+	cases := []struct {
+		mode  string
+		addrs []string
+		check func(redis.UniversalClient) bool
+	}{
+		{config.RedisModeStandalone, []string{"127.0.0.1:6379"}, func(c redis.UniversalClient) bool { _, ok := c.(*redis.Client); return ok }},
+		{config.RedisModeSentinel, []string{"10.0.0.1:26379", "10.0.0.2:26379"}, func(c redis.UniversalClient) bool { _, ok := c.(*redis.Client); return ok }},
+		// 单节点集群也必须创建 ClusterClient
+		{config.RedisModeCluster, []string{"10.0.0.1:7000"}, func(c redis.UniversalClient) bool { _, ok := c.(*redis.ClusterClient); return ok }},
+	}
+	for _, tc := range cases {
+		setRedisConfig(t, tc.mode, tc.addrs...)
+		c, err := newClient()
+		if err != nil {
+			t.Fatalf("%s: newClient: %v", tc.mode, err)
+		}
+		if !tc.check(c) {
+			t.Errorf("%s: unexpected client type %T", tc.mode, c)
+		}
+		_ = c.Close()
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	setRedisConfig(t, config.RedisModeStandalone)
+	if err := ValidateConfig(); err == nil {
+		t.Fatal("ValidateConfig with empty address should fail")
+	}
+
+	config.RedisHost = "127.0.0.1:6379"
+	if err := ValidateConfig(); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+
+	config.RedisTLSEnabled = true
+	config.RedisTLSCAFile = filepath.Join(t.TempDir(), "missing.pem")
+	if err := ValidateConfig(); err == nil {
+		t.Fatal("ValidateConfig with missing CA file should fail")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config.RedisTLSCAFile = invalid
+	if err := ValidateConfig(); err == nil {
+		t.Fatal("ValidateConfig with invalid CA file should fail")
+	}
+}
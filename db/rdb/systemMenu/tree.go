@@ -0,0 +1,70 @@
+package systemmenu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"api-server/db/rdb"
+)
+
+const (
+	// MenuVersionKey 菜单版本号；菜单、按钮权限、角色菜单、租户菜单范围任一变化都会递增
+	MenuVersionKey = "system:menu:version"
+	// menuTreeKeyPrefix 用户菜单树缓存键前缀，键中包含版本号，版本递增后旧缓存自然失效
+	menuTreeKeyPrefix = "system:menu:tree:"
+	// MenuTreeExpiration 菜单树缓存过期时间
+	MenuTreeExpiration = time.Hour
+)
+
+// CachedTree 已计算的菜单树（JSON）及其 ETag
+type CachedTree struct {
+	ETag string          `json:"etag"`
+	Tree json.RawMessage `json:"tree"`
+}
+
+func treeKey(version int64, tenantID, roleID uint) string {
+	return fmt.Sprintf("%sv%d:t%d:r%d", menuTreeKeyPrefix, version, tenantID, roleID)
+}
+
+// GetVersion 读取当前菜单版本号，不存在时为 0
+func GetVersion(ctx context.Context) (int64, error) {
+	version, err := rdb.GetClient().Get(ctx, MenuVersionKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// BumpVersion 递增菜单版本号，使所有已缓存的菜单树失效
+func BumpVersion(ctx context.Context) (int64, error) {
+	return rdb.GetClient().Incr(ctx, MenuVersionKey).Result()
+}
+
+// GetTree 读取指定版本下租户角色的菜单树缓存；未命中时返回 nil
+func GetTree(ctx context.Context, version int64, tenantID, roleID uint) (*CachedTree, error) {
+	val, err := rdb.GetClient().Get(ctx, treeKey(version, tenantID, roleID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cached CachedTree
+	if err = json.Unmarshal(val, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// SetTree 写入指定版本下租户角色的菜单树缓存
+func SetTree(ctx context.Context, version int64, tenantID, roleID uint, tree CachedTree) error {
+	val, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return rdb.GetClient().Set(ctx, treeKey(version, tenantID, roleID), val, MenuTreeExpiration).Err()
+}
@@ -0,0 +1,61 @@
+package systemmenu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"api-server/db/rdb"
+)
+
+func setupRedis(t *testing.T) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	rdb.SetClient(client)
+	t.Cleanup(func() {
+		rdb.SetClient(nil)
+		_ = client.Close()
+	})
+}
+
+func TestTreeCacheInvalidatedByVersionBump(t *testing.T) {
+	setupRedis(t)
+	ctx := context.Background()
+
+	version, err := GetVersion(ctx)
+	if err != nil || version != 0 {
+		t.Fatalf("GetVersion = %d, %v; want 0, nil", version, err)
+	}
+
+	if cached, err := GetTree(ctx, version, 1, 2); err != nil || cached != nil {
+		t.Fatalf("GetTree on empty cache = %v, %v; want nil, nil", cached, err)
+	}
+
+	tree := CachedTree{ETag: `"v0"`, Tree: []byte(`[{"id":1}]`)}
+	if err := SetTree(ctx, version, 1, 2, tree); err != nil {
+		t.Fatalf("SetTree: %v", err)
+	}
+	cached, err := GetTree(ctx, version, 1, 2)
+	if err != nil || cached == nil {
+		t.Fatalf("GetTree = %v, %v; want hit", cached, err)
+	}
+	if cached.ETag != tree.ETag || string(cached.Tree) != string(tree.Tree) {
+		t.Fatalf("GetTree = %+v, want %+v", cached, tree)
+	}
+
+	// 其他角色不共享缓存
+	if other, _ := GetTree(ctx, version, 1, 3); other != nil {
+		t.Fatalf("GetTree for other role = %+v, want nil", other)
+	}
+
+	next, err := BumpVersion(ctx)
+	if err != nil || next != version+1 {
+		t.Fatalf("BumpVersion = %d, %v; want %d", next, err, version+1)
+	}
+	if cached, _ := GetTree(ctx, next, 1, 2); cached != nil {
+		t.Fatalf("GetTree after bump = %+v, want nil", cached)
+	}
+}
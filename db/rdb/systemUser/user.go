@@ -3,7 +3,11 @@ package systemuser
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,15 +18,26 @@ import (
 	"api-server/db/rdb"
 )
 
+// 用户缓存按租户分区，同一租户的键使用 {t<租户ID>} 哈希标签，保证在 Redis Cluster 中落在同一槽位：
+//
+//	system:user:{t1}:info    Hash，field 为用户ID，value 为用户信息 JSON
+//	system:user:{t1}:search  ZSet，score 为用户ID，member 为 "昵称\x00姓名\x00用户ID"，用于分页与服务端模糊匹配
+//	system:user:{t1}:ready   全量构建完成标记；不存在时说明索引可能只含增量写入的部分用户
 const (
-	// UserInfoKey 用户信息缓存键前缀
-	UserInfoKey = "system:user:info:"
-	// UserListKey 用户列表缓存键
-	UserListKey = "system:user:list"
+	userKeyPrefix = "system:user:"
 	// UserCacheExpiration 用户缓存过期时间（12小时）
 	UserCacheExpiration = 12 * time.Hour
+	// cacheBatchSize 全量对账时每批写入 Redis 的用户数，避免单个管道过大
+	cacheBatchSize = 1000
+	// searchScanCount 模糊搜索时每次 ZSCAN 的建议返回条数
+	searchScanCount = 1000
+	// searchSeparator 搜索成员中各字段的分隔符
+	searchSeparator = "\x00"
 )
 
+// ErrUserNotInTenant 用户不存在或不属于指定租户
+var ErrUserNotInTenant = errors.New("user not in tenant")
+
 // UserCacheInfo 用户缓存信息
 type UserCacheInfo struct {
 	ID       uint   `json:"id"`
@@ -32,175 +47,440 @@ type UserCacheInfo struct {
 	RoleName string `json:"role_name"`
 }
 
-// CacheAllUsers 缓存所有用户信息到Redis
-func CacheAllUsers() error {
-	client := rdb.GetClient()
-	ctx := context.Background()
+// CacheFilter 缓存列表模糊查询条件（子串匹配，区分大小写）
+type CacheFilter struct {
+	Username string
+	Name     string
+}
 
-	// 获取所有用户信息
-	var users []system.SystemUser
-	if err := system.FindAllUsers(&users); err != nil {
-		zap.L().Error("获取所有用户信息失败", zap.Error(err))
+func tenantKey(tenantID uint, suffix string) string {
+	return fmt.Sprintf("%s{t%d}:%s", userKeyPrefix, tenantID, suffix)
+}
+
+func infoKey(tenantID uint) string   { return tenantKey(tenantID, "info") }
+func searchKey(tenantID uint) string { return tenantKey(tenantID, "search") }
+func readyKey(tenantID uint) string  { return tenantKey(tenantID, "ready") }
+
+func userField(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
+
+func searchMember(info UserCacheInfo) string {
+	return strings.Join([]string{
+		strings.ReplaceAll(info.Username, searchSeparator, ""),
+		strings.ReplaceAll(info.Name, searchSeparator, ""),
+		userField(info.ID),
+	}, searchSeparator)
+}
+
+// memberUserID 从搜索成员中解析用户ID
+func memberUserID(member string) (uint, bool) {
+	idx := strings.LastIndex(member, searchSeparator)
+	if idx < 0 {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(member[idx+len(searchSeparator):], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// escapeGlob 转义 Redis MATCH 模式中的通配字符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ReplaceAll(s, searchSeparator, "") {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// searchPattern 构造 ZSCAN MATCH 模式：昵称段包含 username，且姓名段包含 name
+func searchPattern(filter CacheFilter) string {
+	username := "*"
+	if filter.Username != "" {
+		username = "*" + escapeGlob(filter.Username) + "*"
+	}
+	name := "*"
+	if filter.Name != "" {
+		name = "*" + escapeGlob(filter.Name) + "*"
+	}
+	return username + searchSeparator + name + searchSeparator + "*"
+}
+
+func newUserCacheInfo(user system.SystemUser, roleName string) UserCacheInfo {
+	return UserCacheInfo{
+		ID:       user.ID,
+		Username: user.Username,
+		Name:     user.Name,
+		RoleID:   user.RoleID,
+		RoleName: roleName,
+	}
+}
+
+// writeUser 在管道中写入（或覆盖）单个用户的缓存
+func writeUser(ctx context.Context, pipe redis.Pipeliner, tenantID uint, info UserCacheInfo) error {
+	userJSON, err := json.Marshal(info)
+	if err != nil {
 		return err
 	}
+	field := userField(info.ID)
+	pipe.HSet(ctx, infoKey(tenantID), field, userJSON)
+	// 先按 score 移除旧成员（昵称/姓名可能已变化），再写入新成员
+	pipe.ZRemRangeByScore(ctx, searchKey(tenantID), field, field)
+	pipe.ZAdd(ctx, searchKey(tenantID), redis.Z{Score: float64(info.ID), Member: searchMember(info)})
+	return nil
+}
 
-	// 获取所有角色信息，用于映射角色名称
-	var roles []system.SystemRole
-	if err := system.FindAllRoles(&roles); err != nil {
-		zap.L().Error("获取所有角色信息失败", zap.Error(err))
+// CacheAllUsers 全量对账：逐个租户重建用户缓存。
+// 日常变更由领域事件增量维护（见 RefreshUser / EvictUser），此函数仅用于启动与低频对账。
+func CacheAllUsers(ctx context.Context) error {
+	var tenants []system.SystemTenant
+	if err := system.FindAllTenants(ctx, &tenants); err != nil {
+		zap.L().Error("获取所有租户信息失败", zap.Error(err))
 		return err
 	}
 
-	// 创建角色映射表
-	roleMap := make(map[uint]string)
+	var firstErr error
+	total := 0
+	for _, tenant := range tenants {
+		count, err := CacheTenantUsers(ctx, tenant.ID)
+		if err != nil {
+			zap.L().Error("重建租户用户缓存失败", zap.Uint("tenant_id", tenant.ID), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		total += count
+	}
+
+	zap.L().Info("已成功缓存所有用户信息到Redis",
+		zap.Int("租户数量", len(tenants)),
+		zap.Int("用户数量", total),
+	)
+	return firstErr
+}
+
+// CacheTenantUsers 按数据库重建指定租户的用户缓存，并清理已不存在的用户，返回缓存的用户数
+func CacheTenantUsers(ctx context.Context, tenantID uint) (int, error) {
+	client := rdb.GetClient()
+
+	var users []system.SystemUser
+	if err := system.FindUsersByTenant(ctx, tenantID, &users); err != nil {
+		return 0, err
+	}
+
+	var roles []system.SystemRole
+	if err := system.FindRolesByTenant(ctx, tenantID, &roles); err != nil {
+		return 0, err
+	}
+	roleMap := make(map[uint]string, len(roles))
 	for _, role := range roles {
 		roleMap[role.ID] = role.Name
 	}
 
-	// 使用管道批量操作，提高效率
-	pipe := client.Pipeline()
-
-	// 缓存用户列表
-	var userList []UserCacheInfo
-	for _, user := range users {
-		// 创建用户缓存对象
-		userCache := UserCacheInfo{
-			ID:       user.ID,
-			Username: user.Username,
-			Name:     user.Name,
-			RoleID:   user.RoleID,
-			RoleName: roleMap[user.RoleID],
+	// 分批使用管道写入，避免一次性构造超大请求
+	alive := make(map[string]struct{}, len(users))
+	for start := 0; start < len(users); start += cacheBatchSize {
+		end := start + cacheBatchSize
+		if end > len(users) {
+			end = len(users)
 		}
 
-		// 将用户信息添加到列表
-		userList = append(userList, userCache)
-
-		// 单独缓存每个用户信息，方便通过ID快速获取
-		userJSON, err := json.Marshal(userCache)
-		if err != nil {
-			zap.L().Error("序列化用户信息失败", zap.Error(err))
-			continue
+		pipe := client.Pipeline()
+		for _, user := range users[start:end] {
+			if err := writeUser(ctx, pipe, tenantID, newUserCacheInfo(user, roleMap[user.RoleID])); err != nil {
+				zap.L().Error("序列化用户信息失败", zap.Error(err))
+				continue
+			}
+			alive[userField(user.ID)] = struct{}{}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, err
 		}
-		pipe.Set(ctx, UserInfoKey+strconv.FormatUint(uint64(user.ID), 10), userJSON, UserCacheExpiration)
 	}
 
-	// 缓存完整用户列表
-	listJSON, err := json.Marshal(userList)
+	// 清理已删除的用户
+	fields, err := client.HKeys(ctx, infoKey(tenantID)).Result()
 	if err != nil {
-		zap.L().Error("序列化用户列表失败", zap.Error(err))
-		return err
+		return 0, err
 	}
-	pipe.Set(ctx, UserListKey, listJSON, UserCacheExpiration)
+	pipe := client.Pipeline()
+	for _, field := range fields {
+		if _, ok := alive[field]; ok {
+			continue
+		}
+		pipe.HDel(ctx, infoKey(tenantID), field)
+		pipe.ZRemRangeByScore(ctx, searchKey(tenantID), field, field)
+	}
+	pipe.Expire(ctx, infoKey(tenantID), UserCacheExpiration)
+	pipe.Expire(ctx, searchKey(tenantID), UserCacheExpiration)
+	pipe.Set(ctx, readyKey(tenantID), time.Now().Unix(), UserCacheExpiration)
+	if _, err = pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return len(alive), nil
+}
 
-	// 执行管道操作
-	_, err = pipe.Exec(ctx)
+// ensureTenantCache 租户缓存未完成全量构建时先构建一次
+func ensureTenantCache(ctx context.Context, tenantID uint) error {
+	ready, err := rdb.GetClient().Exists(ctx, readyKey(tenantID)).Result()
 	if err != nil {
-		zap.L().Error("缓存用户信息到Redis失败", zap.Error(err))
 		return err
 	}
-
-	zap.L().Info("已成功缓存所有用户信息到Redis", zap.Int("用户数量", len(users)))
-	return nil
+	if ready == 0 {
+		_, err = CacheTenantUsers(ctx, tenantID)
+	}
+	return err
 }
 
-// GetUserFromCache 从缓存中获取用户信息
-func GetUserFromCache(userID uint) (*UserCacheInfo, error) {
+// GetUserFromCache 从缓存中获取指定租户下的用户信息；用户不属于该租户时返回 ErrUserNotInTenant
+func GetUserFromCache(ctx context.Context, tenantID, userID uint) (*UserCacheInfo, error) {
 	client := rdb.GetClient()
-	ctx := context.Background()
 
-	// 从Redis获取用户信息
-	val, err := client.Get(ctx, UserInfoKey+strconv.FormatUint(uint64(userID), 10)).Result()
+	val, err := client.HGet(ctx, infoKey(tenantID), userField(userID)).Result()
 	if err != nil {
 		if err == redis.Nil {
 			// 缓存未命中，尝试单独获取并缓存该用户
-			return cacheUserByID(userID)
+			return cacheUserByID(ctx, tenantID, userID)
 		}
 		zap.L().Error("从Redis获取用户信息失败", zap.Error(err))
 		return nil, err
 	}
 
-	// 反序列化用户信息
 	var userCache UserCacheInfo
 	if err = json.Unmarshal([]byte(val), &userCache); err != nil {
 		zap.L().Error("反序列化用户信息失败", zap.Error(err))
 		return nil, err
 	}
-
 	return &userCache, nil
 }
 
-// GetAllUsersFromCache 从缓存中获取所有用户列表
-func GetAllUsersFromCache() ([]UserCacheInfo, error) {
+// ListUsersFromCache 分页查询指定租户的用户缓存（按用户ID升序）。
+// 模糊匹配通过 ZSCAN MATCH 在 Redis 端完成，只回传命中的用户。
+func ListUsersFromCache(ctx context.Context, tenantID uint, filter CacheFilter, page, pageSize int) ([]UserCacheInfo, int, error) {
 	client := rdb.GetClient()
-	ctx := context.Background()
 
-	// 从Redis获取用户列表
-	val, err := client.Get(ctx, UserListKey).Result()
-	if err != nil {
-		if err == redis.Nil {
-			// 缓存未命中，重新缓存所有用户
-			if err = CacheAllUsers(); err != nil {
-				return nil, err
-			}
-			// 再次尝试获取
-			val, err = client.Get(ctx, UserListKey).Result()
-			if err != nil {
-				zap.L().Error("从Redis获取用户列表失败", zap.Error(err))
-				return nil, err
-			}
-		} else {
+	if err := ensureTenantCache(ctx, tenantID); err != nil {
+		zap.L().Error("构建租户用户缓存失败", zap.Uint("tenant_id", tenantID), zap.Error(err))
+		return nil, 0, err
+	}
+
+	start := (page - 1) * pageSize
+	if start < 0 {
+		start = 0
+	}
+
+	var (
+		ids   []uint
+		total int
+	)
+	if filter.Username == "" && filter.Name == "" {
+		count, err := client.ZCard(ctx, searchKey(tenantID)).Result()
+		if err != nil {
 			zap.L().Error("从Redis获取用户列表失败", zap.Error(err))
+			return nil, 0, err
+		}
+		total = int(count)
+		if total == 0 || start >= total {
+			return []UserCacheInfo{}, total, nil
+		}
+		members, err := client.ZRange(ctx, searchKey(tenantID), int64(start), int64(start+pageSize-1)).Result()
+		if err != nil {
+			zap.L().Error("从Redis获取用户列表失败", zap.Error(err))
+			return nil, 0, err
+		}
+		for _, member := range members {
+			if id, ok := memberUserID(member); ok {
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		matched, err := scanMatchedUserIDs(ctx, tenantID, searchPattern(filter))
+		if err != nil {
+			zap.L().Error("从Redis搜索用户失败", zap.Error(err))
+			return nil, 0, err
+		}
+		total = len(matched)
+		if total == 0 || start >= total {
+			return []UserCacheInfo{}, total, nil
+		}
+		end := start + pageSize
+		if end > total {
+			end = total
+		}
+		ids = matched[start:end]
+	}
+
+	users, err := loadUsers(ctx, tenantID, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// scanMatchedUserIDs 遍历租户搜索索引，返回匹配模式的用户ID（升序）
+func scanMatchedUserIDs(ctx context.Context, tenantID uint, pattern string) ([]uint, error) {
+	client := rdb.GetClient()
+
+	seen := make(map[uint]struct{})
+	var cursor uint64
+	for {
+		// ZSCAN 返回 member、score 交替排列
+		items, next, err := client.ZScan(ctx, searchKey(tenantID), cursor, pattern, searchScanCount).Result()
+		if err != nil {
 			return nil, err
 		}
+		for i := 0; i < len(items); i += 2 {
+			if id, ok := memberUserID(items[i]); ok {
+				seen[id] = struct{}{}
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	ids := make([]uint, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
 	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
 
-	// 反序列化用户列表
-	var userList []UserCacheInfo
-	if err = json.Unmarshal([]byte(val), &userList); err != nil {
-		zap.L().Error("反序列化用户列表失败", zap.Error(err))
+// loadUsers 按ID批量读取用户缓存，保持传入顺序
+func loadUsers(ctx context.Context, tenantID uint, ids []uint) ([]UserCacheInfo, error) {
+	if len(ids) == 0 {
+		return []UserCacheInfo{}, nil
+	}
+	fields := make([]string, 0, len(ids))
+	for _, id := range ids {
+		fields = append(fields, userField(id))
+	}
+	values, err := rdb.GetClient().HMGet(ctx, infoKey(tenantID), fields...).Result()
+	if err != nil {
+		zap.L().Error("从Redis获取用户信息失败", zap.Error(err))
 		return nil, err
 	}
 
-	return userList, nil
+	users := make([]UserCacheInfo, 0, len(values))
+	for _, val := range values {
+		raw, ok := val.(string)
+		if !ok {
+			// 索引与详情短暂不一致（如并发删除），跳过
+			continue
+		}
+		var userCache UserCacheInfo
+		if err = json.Unmarshal([]byte(raw), &userCache); err != nil {
+			zap.L().Error("反序列化用户信息失败", zap.Error(err))
+			return nil, err
+		}
+		users = append(users, userCache)
+	}
+	return users, nil
+}
+
+// RefreshUser 按数据库最新数据更新单个用户的缓存；用户已不存在时移除其缓存
+func RefreshUser(ctx context.Context, tenantID, userID uint) error {
+	if _, err := cacheUserByID(ctx, tenantID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrUserNotInTenant) {
+			if tenantID == 0 {
+				return nil
+			}
+			return EvictUser(ctx, tenantID, userID)
+		}
+		return err
+	}
+	return nil
+}
+
+// RefreshUsersByRole 角色变更（如改名）后更新该角色下所有用户的缓存
+func RefreshUsersByRole(ctx context.Context, roleID uint) error {
+	client := rdb.GetClient()
+
+	var users []system.SystemUser
+	if err := system.FindUsersByRole(ctx, roleID, &users); err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	role := system.SystemRole{Model: gorm.Model{ID: roleID}}
+	if err := system.GetRole(ctx, &role); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	for start := 0; start < len(users); start += cacheBatchSize {
+		end := start + cacheBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		pipe := client.Pipeline()
+		for _, user := range users[start:end] {
+			if err := writeUser(ctx, pipe, user.TenantID, newUserCacheInfo(user, role.Name)); err != nil {
+				zap.L().Error("序列化用户信息失败", zap.Error(err))
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			zap.L().Error("更新角色下用户缓存失败", zap.Uint("role_id", roleID), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// EvictUser 移除指定租户下单个用户的缓存
+func EvictUser(ctx context.Context, tenantID, userID uint) error {
+	client := rdb.GetClient()
+
+	field := userField(userID)
+	pipe := client.Pipeline()
+	pipe.HDel(ctx, infoKey(tenantID), field)
+	pipe.ZRemRangeByScore(ctx, searchKey(tenantID), field, field)
+	if _, err := pipe.Exec(ctx); err != nil {
+		zap.L().Error("移除用户缓存失败", zap.Uint("tenant_id", tenantID), zap.Uint("user_id", userID), zap.Error(err))
+		return err
+	}
+	return nil
 }
 
-// cacheUserByID 单独获取并缓存指定ID的用户
-func cacheUserByID(userID uint) (*UserCacheInfo, error) {
+// cacheUserByID 单独获取并缓存指定ID的用户。
+// tenantID 为 0 时以数据库中的租户为准，否则要求用户属于该租户。
+func cacheUserByID(ctx context.Context, tenantID, userID uint) (*UserCacheInfo, error) {
 	client := rdb.GetClient()
-	ctx := context.Background()
 
 	// 获取用户信息
 	user := system.SystemUser{Model: gorm.Model{ID: userID}}
-	if err := system.GetUser(&user); err != nil {
+	if err := system.GetUser(ctx, &user); err != nil {
 		zap.L().Error("获取用户信息失败", zap.Error(err))
 		return nil, err
 	}
+	if tenantID != 0 && user.TenantID != tenantID {
+		return nil, ErrUserNotInTenant
+	}
 
 	// 获取角色信息
 	role := system.SystemRole{Model: gorm.Model{ID: user.RoleID}}
-	if err := system.GetRole(&role); err != nil {
+	if err := system.GetRole(ctx, &role); err != nil {
 		zap.L().Error("获取角色信息失败", zap.Error(err))
 		// 继续执行，只是角色名称可能为空
 	}
 
-	// 创建用户缓存对象
-	userCache := UserCacheInfo{
-		ID:       user.ID,
-		Username: user.Username,
-		Name:     user.Name,
-		RoleID:   user.RoleID,
-		RoleName: role.Name,
-	}
-
-	// 序列化用户信息
-	userJSON, err := json.Marshal(userCache)
-	if err != nil {
+	userCache := newUserCacheInfo(user, role.Name)
+	pipe := client.Pipeline()
+	if err := writeUser(ctx, pipe, user.TenantID, userCache); err != nil {
 		zap.L().Error("序列化用户信息失败", zap.Error(err))
 		return nil, err
 	}
-
-	// 缓存用户信息
-	if err = client.Set(ctx, UserInfoKey+strconv.FormatUint(uint64(user.ID), 10), userJSON, UserCacheExpiration).Err(); err != nil {
+	if _, err := pipe.Exec(ctx); err != nil {
 		zap.L().Error("缓存用户信息到Redis失败", zap.Error(err))
 		return nil, err
 	}
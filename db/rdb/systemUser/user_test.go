@@ -0,0 +1,101 @@
+package systemuser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"api-server/db/rdb"
+)
+
+func setupRedis(t *testing.T) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	rdb.SetClient(client)
+	t.Cleanup(func() {
+		rdb.SetClient(nil)
+		_ = client.Close()
+	})
+}
+
+func seedTenant(t *testing.T, tenantID uint, users ...UserCacheInfo) {
+	t.Helper()
+	ctx := context.Background()
+	pipe := rdb.GetClient().Pipeline()
+	for _, u := range users {
+		if err := writeUser(ctx, pipe, tenantID, u); err != nil {
+			t.Fatalf("writeUser: %v", err)
+		}
+	}
+	pipe.Set(ctx, readyKey(tenantID), 1, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+func TestListUsersFromCacheIsTenantScoped(t *testing.T) {
+	setupRedis(t)
+	seedTenant(t, 1,
+		UserCacheInfo{ID: 3, Username: "carol", Name: "王五"},
+		UserCacheInfo{ID: 1, Username: "alice", Name: "张三"},
+		UserCacheInfo{ID: 2, Username: "bob", Name: "李四"},
+	)
+	seedTenant(t, 2, UserCacheInfo{ID: 10, Username: "alice-other", Name: "赵六"})
+
+	users, total, err := ListUsersFromCache(context.Background(), 1, CacheFilter{}, 1, 2)
+	if err != nil {
+		t.Fatalf("ListUsersFromCache: %v", err)
+	}
+	if total != 3 || len(users) != 2 || users[0].ID != 1 || users[1].ID != 2 {
+		t.Fatalf("unexpected page: total=%d users=%+v", total, users)
+	}
+
+	users, total, err = ListUsersFromCache(context.Background(), 1, CacheFilter{Username: "li"}, 1, 10)
+	if err != nil {
+		t.Fatalf("ListUsersFromCache: %v", err)
+	}
+	if total != 1 || users[0].ID != 1 {
+		t.Fatalf("username filter: total=%d users=%+v", total, users)
+	}
+
+	users, total, err = ListUsersFromCache(context.Background(), 1, CacheFilter{Name: "李"}, 1, 10)
+	if err != nil {
+		t.Fatalf("ListUsersFromCache: %v", err)
+	}
+	if total != 1 || users[0].ID != 2 {
+		t.Fatalf("name filter: total=%d users=%+v", total, users)
+	}
+
+	// 姓名中的关键字不会误命中昵称条件
+	if _, total, _ = ListUsersFromCache(context.Background(), 1, CacheFilter{Username: "张"}, 1, 10); total != 0 {
+		t.Fatalf("username filter matched name field, total=%d", total)
+	}
+	// 通配字符按字面量匹配
+	if _, total, _ = ListUsersFromCache(context.Background(), 1, CacheFilter{Username: "*"}, 1, 10); total != 0 {
+		t.Fatalf("glob characters should be escaped, total=%d", total)
+	}
+}
+
+func TestWriteUserReplacesSearchMemberAndEvict(t *testing.T) {
+	setupRedis(t)
+	seedTenant(t, 1, UserCacheInfo{ID: 1, Username: "alice", Name: "张三"})
+	seedTenant(t, 1, UserCacheInfo{ID: 1, Username: "alicia", Name: "张三"})
+
+	users, total, err := ListUsersFromCache(context.Background(), 1, CacheFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("ListUsersFromCache: %v", err)
+	}
+	if total != 1 || users[0].Username != "alicia" {
+		t.Fatalf("rename not applied: total=%d users=%+v", total, users)
+	}
+
+	if err = EvictUser(context.Background(), 1, 1); err != nil {
+		t.Fatalf("EvictUser: %v", err)
+	}
+	if _, total, _ = ListUsersFromCache(context.Background(), 1, CacheFilter{}, 1, 10); total != 0 {
+		t.Fatalf("evicted user still listed, total=%d", total)
+	}
+}
@@ -1,24 +1,36 @@
 package department
 
 import (
+	"context"
 	"errors"
 
 	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
 
 	"gorm.io/gorm"
 )
 
+// Service 部门领域服务
+type Service struct {
+	departments repo.DepartmentRepo
+	users       repo.UserRepo
+}
+
+func NewService(r repo.Repos) *Service {
+	return &Service{departments: r.Departments, users: r.Users}
+}
+
 type FindListQuery struct {
 	Name   string
 	Status uint
 }
 
-func FindDepartmentList(query FindListQuery, page, pageSize int) ([]system.SystemDepartment, int64, error) {
+func (s *Service) FindDepartmentList(ctx context.Context, query FindListQuery, page, pageSize int) ([]system.SystemDepartment, int64, error) {
 	filter := system.SystemDepartment{
 		Name:   query.Name,
 		Status: query.Status,
 	}
-	return system.FindDepartmentList(&filter, page, pageSize)
+	return s.departments.List(ctx, &filter, page, pageSize)
 }
 
 type AddInput struct {
@@ -27,13 +39,13 @@ type AddInput struct {
 	Sort   uint
 }
 
-func AddDepartment(input AddInput) (system.SystemDepartment, error) {
+func (s *Service) AddDepartment(ctx context.Context, input AddInput) (system.SystemDepartment, error) {
 	department := system.SystemDepartment{
 		Name:   input.Name,
 		Status: input.Status,
 		Sort:   input.Sort,
 	}
-	if err := system.AddDepartment(&department); err != nil {
+	if err := s.departments.Create(ctx, &department); err != nil {
 		return system.SystemDepartment{}, err
 	}
 	return department, nil
@@ -46,37 +58,37 @@ type UpdateInput struct {
 	Sort   uint
 }
 
-func UpdateDepartment(input UpdateInput) (system.SystemDepartment, error) {
+func (s *Service) UpdateDepartment(ctx context.Context, input UpdateInput) (system.SystemDepartment, error) {
 	department := system.SystemDepartment{
 		Model:  gorm.Model{ID: input.ID},
 		Name:   input.Name,
 		Status: input.Status,
 		Sort:   input.Sort,
 	}
-	if err := system.UpdateDepartment(&department); err != nil {
+	if err := s.departments.Update(ctx, &department); err != nil {
 		return system.SystemDepartment{}, err
 	}
 	return department, nil
 }
 
-func DeleteDepartment(id uint) (system.SystemDepartment, error) {
+func (s *Service) DeleteDepartment(ctx context.Context, id uint) (system.SystemDepartment, error) {
 	department := system.SystemDepartment{Model: gorm.Model{ID: id}}
-	if err := system.GetDepartment(&department); err != nil {
+	if err := s.departments.Get(ctx, &department); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return system.SystemDepartment{}, ErrDepartmentNotFound
 		}
 		return system.SystemDepartment{}, err
 	}
 
-	var userCount int64
-	if err := system.CountUsersByDepartmentID(id, &userCount); err != nil {
+	userCount, err := s.users.CountByDepartment(ctx, id)
+	if err != nil {
 		return system.SystemDepartment{}, err
 	}
 	if userCount > 0 {
 		return system.SystemDepartment{}, ErrDepartmentHasUsers
 	}
 
-	if err := system.DeleteDepartment(&department); err != nil {
+	if err := s.departments.Delete(ctx, &department); err != nil {
 		return system.SystemDepartment{}, err
 	}
 	return department, nil
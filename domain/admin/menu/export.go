@@ -0,0 +1,191 @@
+package menu
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"api-server/db/pgdb/system"
+)
+
+// MenuExport 菜单导出文件格式，用于在环境之间同步平台菜单与按钮权限。
+// 菜单按 ID 平铺，父子关系通过 ParentID 表达，按钮权限挂在所属菜单下。
+type MenuExport struct {
+	Menus []ExportedMenu `json:"menus"`
+}
+
+type ExportedMenu struct {
+	ID            uint           `json:"id"`
+	ParentID      uint           `json:"parent_id"`
+	Level         uint           `json:"level"`
+	Sort          uint           `json:"sort"`
+	Path          string         `json:"path"`
+	Name          string         `json:"name"`
+	Component     string         `json:"component"`
+	Title         string         `json:"title"`
+	Icon          string         `json:"icon,omitempty"`
+	ShowBadge     uint           `json:"show_badge,omitempty"`
+	ShowTextBadge string         `json:"show_text_badge,omitempty"`
+	IsHide        uint           `json:"is_hide,omitempty"`
+	IsHideTab     uint           `json:"is_hide_tab,omitempty"`
+	Link          string         `json:"link,omitempty"`
+	IsIframe      uint           `json:"is_iframe,omitempty"`
+	KeepAlive     uint           `json:"keep_alive,omitempty"`
+	IsFirstLevel  uint           `json:"is_in_main_container,omitempty"`
+	Status        uint           `json:"status"`
+	Auths         []ExportedAuth `json:"auths,omitempty"`
+}
+
+type ExportedAuth struct {
+	ID    uint   `json:"id"`
+	Mark  string `json:"mark"`
+	Title string `json:"title"`
+}
+
+// ImportResult 导入统计
+type ImportResult struct {
+	MenusCreated int
+	MenusUpdated int
+	AuthsCreated int
+	AuthsUpdated int
+}
+
+// ExportMenus 导出全部菜单与按钮权限，按 ID 排序以便对比差异
+func (s *Service) ExportMenus(ctx context.Context) (MenuExport, error) {
+	menus, auths, err := s.menus.All(ctx)
+	if err != nil {
+		return MenuExport{}, err
+	}
+
+	authsByMenu := make(map[uint][]ExportedAuth)
+	for _, a := range auths {
+		authsByMenu[a.MenuID] = append(authsByMenu[a.MenuID], ExportedAuth{ID: a.ID, Mark: a.Mark, Title: a.Title})
+	}
+
+	out := MenuExport{Menus: make([]ExportedMenu, 0, len(menus))}
+	for _, m := range menus {
+		items := authsByMenu[m.ID]
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+		out.Menus = append(out.Menus, ExportedMenu{
+			ID:            m.ID,
+			ParentID:      m.ParentID,
+			Level:         m.Level,
+			Sort:          m.Sort,
+			Path:          m.Path,
+			Name:          m.Name,
+			Component:     m.Component,
+			Title:         m.Title,
+			Icon:          m.Icon,
+			ShowBadge:     m.ShowBadge,
+			ShowTextBadge: m.ShowTextBadge,
+			IsHide:        m.IsHide,
+			IsHideTab:     m.IsHideTab,
+			Link:          m.Link,
+			IsIframe:      m.IsIframe,
+			KeepAlive:     m.KeepAlive,
+			IsFirstLevel:  m.IsFirstLevel,
+			Status:        m.Status,
+			Auths:         items,
+		})
+	}
+	sort.Slice(out.Menus, func(i, j int) bool { return out.Menus[i].ID < out.Menus[j].ID })
+	return out, nil
+}
+
+// ImportMenus 按 ID 导入菜单与按钮权限：已存在的记录更新，不存在的以原 ID 创建；
+// 文件中没有的菜单不会被删除。更新沿用接口层的语义，空值字段不会覆盖已有值。
+// 父级菜单必须存在于文件或数据库中，否则整体拒绝导入；写入在单个事务中完成，任一记录失败时全部回滚。
+// 无论成功与否都会使菜单树缓存失效，避免失败前读到的中间状态残留在缓存中。
+func (s *Service) ImportMenus(ctx context.Context, data MenuExport) (ImportResult, error) {
+	defer s.invalidateCache(ctx)
+
+	menus, auths, err := s.menus.All(ctx)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	existingMenus := make(map[uint]bool, len(menus))
+	for _, m := range menus {
+		existingMenus[m.ID] = true
+	}
+	existingAuths := make(map[uint]bool, len(auths))
+	for _, a := range auths {
+		existingAuths[a.ID] = true
+	}
+
+	imported := make(map[uint]bool, len(data.Menus))
+	for _, m := range data.Menus {
+		if m.ID == 0 {
+			return ImportResult{}, fmt.Errorf("menu %q: id is required", m.Name)
+		}
+		if imported[m.ID] {
+			return ImportResult{}, fmt.Errorf("duplicate menu id %d", m.ID)
+		}
+		imported[m.ID] = true
+	}
+	for _, m := range data.Menus {
+		if m.ParentID != 0 && !imported[m.ParentID] && !existingMenus[m.ParentID] {
+			return ImportResult{}, fmt.Errorf("%w: menu %d parent %d", ErrParentMenuNotFound, m.ID, m.ParentID)
+		}
+		for _, a := range m.Auths {
+			if a.ID == 0 {
+				return ImportResult{}, fmt.Errorf("auth %q of menu %d: id is required", a.Mark, m.ID)
+			}
+		}
+	}
+
+	var batch system.MenuImport
+	for _, m := range data.Menus {
+		menu := system.SystemMenu{
+			Path:          m.Path,
+			Name:          m.Name,
+			Component:     m.Component,
+			Title:         m.Title,
+			Icon:          m.Icon,
+			ShowBadge:     m.ShowBadge,
+			ShowTextBadge: m.ShowTextBadge,
+			IsHide:        m.IsHide,
+			IsHideTab:     m.IsHideTab,
+			Link:          m.Link,
+			IsIframe:      m.IsIframe,
+			KeepAlive:     m.KeepAlive,
+			IsFirstLevel:  m.IsFirstLevel,
+			Status:        m.Status,
+			Level:         m.Level,
+			ParentID:      m.ParentID,
+			Sort:          m.Sort,
+		}
+		menu.ID = m.ID
+		if existingMenus[m.ID] {
+			batch.UpdateMenus = append(batch.UpdateMenus, menu)
+		} else {
+			batch.CreateMenus = append(batch.CreateMenus, menu)
+		}
+
+		for _, a := range m.Auths {
+			auth := system.SystemMenuAuth{MenuID: m.ID, Mark: a.Mark, Title: a.Title}
+			auth.ID = a.ID
+			if existingAuths[a.ID] {
+				batch.UpdateAuths = append(batch.UpdateAuths, auth)
+			} else {
+				batch.CreateAuths = append(batch.CreateAuths, auth)
+			}
+		}
+	}
+	if err := s.menus.Import(ctx, batch); err != nil {
+		return ImportResult{}, err
+	}
+	return ImportResult{
+		MenusCreated: len(batch.CreateMenus),
+		MenusUpdated: len(batch.UpdateMenus),
+		AuthsCreated: len(batch.CreateAuths),
+		AuthsUpdated: len(batch.UpdateAuths),
+	}, nil
+}
+
+// InvalidateTreeCache 使全部用户菜单树缓存失效（递增版本号），供命令行重建缓存使用
+func (s *Service) InvalidateTreeCache(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.BumpVersion(ctx)
+}
@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"errors"
 
 	commonmenu "api-server/common/menu"
@@ -9,8 +10,8 @@ import (
 	"gorm.io/gorm"
 )
 
-func GetPlatformMenuTree() ([]commonmenu.MenuResponse, error) {
-	menus, allAuths, err := system.GetMenuData()
+func (s *Service) GetPlatformMenuTree(ctx context.Context) ([]commonmenu.MenuResponse, error) {
+	menus, allAuths, err := s.menus.All(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +37,7 @@ type AddMenuInput struct {
 	Sort          uint
 }
 
-func AddMenu(input AddMenuInput) (system.SystemMenu, error) {
+func (s *Service) AddMenu(ctx context.Context, input AddMenuInput) (system.SystemMenu, error) {
 	if input.ShowBadge == 0 {
 		input.ShowBadge = 2
 	}
@@ -44,7 +45,7 @@ func AddMenu(input AddMenuInput) (system.SystemMenu, error) {
 	var level uint = 1
 	if input.ParentID != 0 {
 		parentMenu := system.SystemMenu{Model: gorm.Model{ID: input.ParentID}}
-		if err := system.GetMenu(&parentMenu); err != nil {
+		if err := s.menus.Get(ctx, &parentMenu); err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return system.SystemMenu{}, ErrParentMenuNotFound
 			}
@@ -75,9 +76,10 @@ func AddMenu(input AddMenuInput) (system.SystemMenu, error) {
 		ParentID:      input.ParentID,
 		Sort:          input.Sort,
 	}
-	if err := system.AddMenu(&menu); err != nil {
+	if err := s.menus.Create(ctx, &menu); err != nil {
 		return system.SystemMenu{}, err
 	}
+	s.invalidateCache(ctx)
 	return menu, nil
 }
 
@@ -101,7 +103,7 @@ type UpdateMenuInput struct {
 	Sort          uint
 }
 
-func UpdateMenu(input UpdateMenuInput) (system.SystemMenu, error) {
+func (s *Service) UpdateMenu(ctx context.Context, input UpdateMenuInput) (system.SystemMenu, error) {
 	if input.ShowBadge == 0 {
 		input.ShowBadge = 2
 	}
@@ -109,7 +111,7 @@ func UpdateMenu(input UpdateMenuInput) (system.SystemMenu, error) {
 	var level uint = 1
 	if input.ParentID != 0 {
 		parent := system.SystemMenu{Model: gorm.Model{ID: input.ParentID}}
-		if err := system.GetMenu(&parent); err != nil {
+		if err := s.menus.Get(ctx, &parent); err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return system.SystemMenu{}, ErrParentMenuNotFound
 			}
@@ -122,7 +124,7 @@ func UpdateMenu(input UpdateMenuInput) (system.SystemMenu, error) {
 	}
 
 	if input.Status == system.StatusDisabled {
-		children, _, err := system.FindMenuList(&system.SystemMenu{ParentID: input.ID}, -1, -1)
+		children, _, err := s.menus.List(ctx, &system.SystemMenu{ParentID: input.ID}, -1, -1)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return system.SystemMenu{}, err
 		}
@@ -153,22 +155,23 @@ func UpdateMenu(input UpdateMenuInput) (system.SystemMenu, error) {
 		ParentID:      input.ParentID,
 		Sort:          input.Sort,
 	}
-	if err := system.UpdateMenu(&menu); err != nil {
+	if err := s.menus.Update(ctx, &menu); err != nil {
 		return system.SystemMenu{}, err
 	}
+	s.invalidateCache(ctx)
 	return menu, nil
 }
 
-func DeleteMenu(id uint) (system.SystemMenu, error) {
+func (s *Service) DeleteMenu(ctx context.Context, id uint) (system.SystemMenu, error) {
 	menu := system.SystemMenu{Model: gorm.Model{ID: id}}
-	if err := system.GetMenu(&menu); err != nil {
+	if err := s.menus.Get(ctx, &menu); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return system.SystemMenu{}, ErrMenuNotFound
 		}
 		return system.SystemMenu{}, err
 	}
 
-	children, _, err := system.FindMenuList(&system.SystemMenu{ParentID: menu.ID}, -1, -1)
+	children, _, err := s.menus.List(ctx, &system.SystemMenu{ParentID: menu.ID}, -1, -1)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return system.SystemMenu{}, err
 	}
@@ -176,15 +179,16 @@ func DeleteMenu(id uint) (system.SystemMenu, error) {
 		return system.SystemMenu{}, ErrMenuHasChildren
 	}
 
-	if err := system.DeleteMenu(&menu); err != nil {
+	if err := s.menus.Delete(ctx, &menu); err != nil {
 		return system.SystemMenu{}, err
 	}
+	s.invalidateCache(ctx)
 	return menu, nil
 }
 
-func GetMenuAuthList(menuID uint) ([]system.SystemMenuAuth, error) {
+func (s *Service) GetMenuAuthList(ctx context.Context, menuID uint) ([]system.SystemMenuAuth, error) {
 	auth := system.SystemMenuAuth{MenuID: menuID}
-	return system.FindMenuAuthList(&auth)
+	return s.menus.ListAuths(ctx, &auth)
 }
 
 type AddMenuAuthInput struct {
@@ -193,15 +197,16 @@ type AddMenuAuthInput struct {
 	Title  string
 }
 
-func AddMenuAuth(input AddMenuAuthInput) (system.SystemMenuAuth, error) {
+func (s *Service) AddMenuAuth(ctx context.Context, input AddMenuAuthInput) (system.SystemMenuAuth, error) {
 	auth := system.SystemMenuAuth{
 		MenuID: input.MenuID,
 		Mark:   input.Mark,
 		Title:  input.Title,
 	}
-	if err := system.AddMenuAuth(&auth); err != nil {
+	if err := s.menus.CreateAuth(ctx, &auth); err != nil {
 		return system.SystemMenuAuth{}, err
 	}
+	s.invalidateCache(ctx)
 	return auth, nil
 }
 
@@ -212,58 +217,61 @@ type UpdateMenuAuthInput struct {
 	MenuID uint
 }
 
-func UpdateMenuAuth(input UpdateMenuAuthInput) (system.SystemMenuAuth, error) {
+func (s *Service) UpdateMenuAuth(ctx context.Context, input UpdateMenuAuthInput) (system.SystemMenuAuth, error) {
 	auth := system.SystemMenuAuth{
 		Model:  gorm.Model{ID: input.ID},
 		Title:  input.Title,
 		Mark:   input.Mark,
 		MenuID: input.MenuID,
 	}
-	if err := system.UpdateMenuAuth(&auth); err != nil {
+	if err := s.menus.UpdateAuth(ctx, &auth); err != nil {
 		return system.SystemMenuAuth{}, err
 	}
+	s.invalidateCache(ctx)
 	return auth, nil
 }
 
-func DeleteMenuAuth(id uint) (system.SystemMenuAuth, error) {
+func (s *Service) DeleteMenuAuth(ctx context.Context, id uint) (system.SystemMenuAuth, error) {
 	auth := system.SystemMenuAuth{Model: gorm.Model{ID: id}}
-	if err := system.DeleteMenuAuth(&auth); err != nil {
+	if err := s.menus.DeleteAuth(ctx, &auth); err != nil {
 		return system.SystemMenuAuth{}, err
 	}
+	s.invalidateCache(ctx)
 	return auth, nil
 }
 
-func GetTenantMenuTree(tenantID uint) ([]commonmenu.MenuResponse, error) {
-	menus, allAuths, err := system.GetMenuData()
+func (s *Service) GetTenantMenuTree(ctx context.Context, tenantID uint) ([]commonmenu.MenuResponse, error) {
+	menus, allAuths, err := s.menus.All(ctx)
 	if err != nil {
 		return nil, err
 	}
-	scopeIDs, err := system.GetTenantMenuScopeIDs(tenantID)
+	scopeIDs, err := s.tenants.MenuScope(ctx, tenantID)
 	if err != nil {
 		return nil, err
 	}
-	authScopeIDs, err := system.GetTenantAuthScopeIDs(tenantID)
+	authScopeIDs, err := s.tenants.AuthScope(ctx, tenantID)
 	if err != nil {
 		return nil, err
 	}
 	return commonmenu.BuildMenuTreeWithPermission(menus, allAuths, scopeIDs, authScopeIDs, true), nil
 }
 
-func UpdateTenantMenuScope(tenantID uint, menuData []commonmenu.MenuResponse) ([]commonmenu.MenuResponse, error) {
+func (s *Service) UpdateTenantMenuScope(ctx context.Context, tenantID uint, menuData []commonmenu.MenuResponse) ([]commonmenu.MenuResponse, error) {
 	menuIDs := extractCheckedMenuIDs(menuData)
 	authIDs := extractCheckedAuthIDs(menuData)
 
-	if err := system.SaveTenantMenuScope(tenantID, menuIDs); err != nil {
+	if err := s.tenants.SaveMenuScope(ctx, tenantID, menuIDs); err != nil {
 		return nil, err
 	}
-	if err := system.SaveTenantAuthScope(tenantID, authIDs); err != nil {
+	if err := s.tenants.SaveAuthScope(ctx, tenantID, authIDs); err != nil {
 		return nil, err
 	}
-	if err := system.PruneTenantRoleAssociations(tenantID, menuIDs, authIDs); err != nil {
+	if err := s.tenants.PruneRoleAssociations(ctx, tenantID, menuIDs, authIDs); err != nil {
 		return nil, err
 	}
+	s.invalidateCache(ctx)
 
-	menus, allAuths, err := system.GetMenuData()
+	menus, allAuths, err := s.menus.All(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package menu
 
 import (
+	"context"
 	"errors"
 
 	commonmenu "api-server/common/menu"
@@ -9,9 +10,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func GetRoleMenuTree(roleID uint, actorTenantID uint, isSuperAdmin bool) ([]commonmenu.MenuResponse, error) {
+func (s *Service) GetRoleMenuTree(ctx context.Context, roleID uint, actorTenantID uint, isSuperAdmin bool) ([]commonmenu.MenuResponse, error) {
 	roleEntity := system.SystemRole{Model: gorm.Model{ID: roleID}}
-	if err := system.GetRole(&roleEntity); err != nil {
+	if err := s.roles.Get(ctx, &roleEntity); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrRoleNotFound
 		}
@@ -24,18 +25,18 @@ func GetRoleMenuTree(roleID uint, actorTenantID uint, isSuperAdmin bool) ([]comm
 		}
 	}
 
-	allMenus, allAuths, roleMenuIDs, roleAuthIDs, err := system.GetMenuDataByRoleID(roleID)
+	allMenus, allAuths, roleMenuIDs, roleAuthIDs, err := s.menus.ForRole(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
 
-	scopeIDs, err := system.GetTenantMenuScopeIDs(roleEntity.TenantID)
+	scopeIDs, err := s.tenants.MenuScope(ctx, roleEntity.TenantID)
 	if err != nil {
 		return nil, err
 	}
 	allMenus, allAuths = system.FilterMenusByIDs(allMenus, allAuths, scopeIDs)
 
-	authScopeIDs, err := system.GetTenantAuthScopeIDs(roleEntity.TenantID)
+	authScopeIDs, err := s.tenants.AuthScope(ctx, roleEntity.TenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -61,9 +62,9 @@ func GetRoleMenuTree(roleID uint, actorTenantID uint, isSuperAdmin bool) ([]comm
 	return commonmenu.BuildMenuTreeWithPermission(allMenus, allAuths, roleMenuIDs, roleAuthIDs, true), nil
 }
 
-func UpdateRoleMenu(roleID uint, menuData []commonmenu.MenuResponse, actorTenantID uint, isSuperAdmin bool) error {
+func (s *Service) UpdateRoleMenu(ctx context.Context, roleID uint, menuData []commonmenu.MenuResponse, actorTenantID uint, isSuperAdmin bool) error {
 	roleEntity := system.SystemRole{Model: gorm.Model{ID: roleID}}
-	if err := system.GetRole(&roleEntity); err != nil {
+	if err := s.roles.Get(ctx, &roleEntity); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrRoleNotFound
 		}
@@ -76,7 +77,7 @@ func UpdateRoleMenu(roleID uint, menuData []commonmenu.MenuResponse, actorTenant
 		}
 	}
 
-	scopeIDs, err := system.GetTenantMenuScopeIDs(roleEntity.TenantID)
+	scopeIDs, err := s.tenants.MenuScope(ctx, roleEntity.TenantID)
 	if err != nil {
 		return err
 	}
@@ -84,7 +85,7 @@ func UpdateRoleMenu(roleID uint, menuData []commonmenu.MenuResponse, actorTenant
 		return ErrMenuOutOfScope
 	}
 
-	authScopeIDs, err := system.GetTenantAuthScopeIDs(roleEntity.TenantID)
+	authScopeIDs, err := s.tenants.AuthScope(ctx, roleEntity.TenantID)
 	if err != nil {
 		return err
 	}
@@ -95,6 +96,10 @@ func UpdateRoleMenu(roleID uint, menuData []commonmenu.MenuResponse, actorTenant
 	menuIDs := extractCheckedMenuIDs(menuData)
 	authIDs := extractCheckedAuthIDs(menuData)
 
-	return system.SaveRoleMenuAssociations(roleID, menuIDs, authIDs)
+	if err := s.roles.SaveMenus(ctx, roleID, menuIDs, authIDs); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
 }
 
@@ -0,0 +1,64 @@
+package menu
+
+import (
+	"context"
+
+	systemmenu "api-server/db/rdb/systemMenu"
+	systemuser "api-server/db/rdb/systemUser"
+	"api-server/domain/admin/repo"
+)
+
+// TreeCache 用户菜单树缓存；为 nil 时每次请求都从仓储构建菜单树
+type TreeCache interface {
+	// RoleID 从用户缓存读取用户角色，用于定位菜单树缓存键
+	RoleID(ctx context.Context, tenantID, userID uint) (uint, bool)
+	Version(ctx context.Context) (int64, error)
+	BumpVersion(ctx context.Context) error
+	Get(ctx context.Context, version int64, tenantID, roleID uint) (*systemmenu.CachedTree, error)
+	Set(ctx context.Context, version int64, tenantID, roleID uint, tree systemmenu.CachedTree) error
+}
+
+// RedisTreeCache 基于 Redis 的菜单树缓存
+type RedisTreeCache struct{}
+
+func (RedisTreeCache) RoleID(ctx context.Context, tenantID, userID uint) (uint, bool) {
+	info, err := systemuser.GetUserFromCache(ctx, tenantID, userID)
+	if err != nil || info == nil {
+		return 0, false
+	}
+	return info.RoleID, true
+}
+
+func (RedisTreeCache) Version(ctx context.Context) (int64, error) {
+	return systemmenu.GetVersion(ctx)
+}
+
+func (RedisTreeCache) BumpVersion(ctx context.Context) error {
+	_, err := systemmenu.BumpVersion(ctx)
+	return err
+}
+
+func (RedisTreeCache) Get(ctx context.Context, version int64, tenantID, roleID uint) (*systemmenu.CachedTree, error) {
+	return systemmenu.GetTree(ctx, version, tenantID, roleID)
+}
+
+func (RedisTreeCache) Set(ctx context.Context, version int64, tenantID, roleID uint, tree systemmenu.CachedTree) error {
+	return systemmenu.SetTree(ctx, version, tenantID, roleID, tree)
+}
+
+// Service 菜单领域服务：平台菜单维护、租户菜单范围、角色菜单分配与用户菜单树
+type Service struct {
+	menus   repo.MenuRepo
+	roles   repo.RoleRepo
+	tenants repo.TenantRepo
+	cache   TreeCache
+}
+
+func NewService(r repo.Repos, cache TreeCache) *Service {
+	return &Service{
+		menus:   r.Menus,
+		roles:   r.Roles,
+		tenants: r.Tenants,
+		cache:   cache,
+	}
+}
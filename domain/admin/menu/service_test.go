@@ -0,0 +1,320 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	commonmenu "api-server/common/menu"
+	"api-server/db/pgdb/system"
+	systemmenu "api-server/db/rdb/systemMenu"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/repo/memory"
+)
+
+// scopeFixture 两个菜单各带一个按钮权限；租户 10 只开放菜单 m1 与按钮 a1
+type scopeFixture struct {
+	svc    *Service
+	repos  repo.Repos
+	m1, m2 uint
+	a1, a2 uint
+	roleA  uint // 租户 10 的角色
+	roleB  uint // 租户 20 的角色
+}
+
+func newScopeFixture(t *testing.T) scopeFixture {
+	t.Helper()
+	ctx := context.Background()
+	r := memory.New().Repos()
+	f := scopeFixture{svc: NewService(r, nil), repos: r}
+
+	for _, item := range []struct {
+		menu, auth *uint
+		name       string
+	}{{&f.m1, &f.a1, "m1"}, {&f.m2, &f.a2, "m2"}} {
+		m := system.SystemMenu{Name: item.name, Title: item.name, Status: system.StatusEnabled, Level: 1}
+		if err := r.Menus.Create(ctx, &m); err != nil {
+			t.Fatal(err)
+		}
+		a := system.SystemMenuAuth{MenuID: m.ID, Mark: item.name + ":add", Title: "add"}
+		if err := r.Menus.CreateAuth(ctx, &a); err != nil {
+			t.Fatal(err)
+		}
+		*item.menu, *item.auth = m.ID, a.ID
+	}
+	for _, item := range []struct {
+		tenant uint
+		role   *uint
+	}{{10, &f.roleA}, {20, &f.roleB}} {
+		role := system.SystemRole{TenantID: item.tenant, Name: "r", Status: system.StatusEnabled}
+		if err := r.Roles.Create(ctx, &role); err != nil {
+			t.Fatal(err)
+		}
+		*item.role = role.ID
+	}
+	if err := r.Tenants.SaveMenuScope(ctx, 10, []uint{f.m1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Tenants.SaveAuthScope(ctx, 10, []uint{f.a1}); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func checkedTree(menuID, authID uint) []commonmenu.MenuResponse {
+	return []commonmenu.MenuResponse{{
+		ID:            menuID,
+		HasPermission: true,
+		Meta: commonmenu.MenuMeta{AuthList: []commonmenu.MenuAuthResp{
+			{ID: authID, HasPermission: true},
+		}},
+	}}
+}
+
+func TestUpdateRoleMenuTenantScoping(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+
+	tests := []struct {
+		name          string
+		roleID        uint
+		tree          []commonmenu.MenuResponse
+		actorTenantID uint
+		isSuperAdmin  bool
+		want          error
+	}{
+		{"other tenant role", f.roleB, checkedTree(f.m1, f.a1), 10, false, ErrPermissionDenied},
+		{"missing tenant", f.roleA, checkedTree(f.m1, f.a1), 0, false, ErrPermissionDenied},
+		{"missing role", 999, checkedTree(f.m1, f.a1), 10, false, ErrRoleNotFound},
+		{"menu out of scope", f.roleA, checkedTree(f.m2, f.a1), 10, false, ErrMenuOutOfScope},
+		{"auth out of scope", f.roleA, checkedTree(f.m1, f.a2), 10, false, ErrAuthOutOfScope},
+		// 超级管理员同样受角色所属租户的菜单范围约束
+		{"super admin out of scope", f.roleA, checkedTree(f.m2, f.a2), 0, true, ErrMenuOutOfScope},
+		{"in scope", f.roleA, checkedTree(f.m1, f.a1), 10, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.svc.UpdateRoleMenu(ctx, tt.roleID, tt.tree, tt.actorTenantID, tt.isSuperAdmin)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("UpdateRoleMenu() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	_, _, menuIDs, authIDs, err := f.repos.Menus.ForRole(ctx, f.roleA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menuIDs) != 1 || menuIDs[0] != f.m1 || len(authIDs) != 1 || authIDs[0] != f.a1 {
+		t.Fatalf("role menus = %v auths = %v, want [%d] [%d]", menuIDs, authIDs, f.m1, f.a1)
+	}
+}
+
+func TestGetRoleMenuTreeFiltersByTenantScope(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+
+	if _, err := f.svc.GetRoleMenuTree(ctx, f.roleB, 10, false); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("GetRoleMenuTree() other tenant error = %v, want ErrPermissionDenied", err)
+	}
+
+	tree, err := f.svc.GetRoleMenuTree(ctx, f.roleA, 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 1 || tree[0].ID != f.m1 {
+		t.Fatalf("tree = %+v, want only menu m1", tree)
+	}
+	if auths := tree[0].Meta.AuthList; len(auths) != 1 || auths[0].ID != f.a1 {
+		t.Fatalf("auth list = %+v, want only a1", auths)
+	}
+}
+
+func TestUpdateTenantMenuScopePrunesRoles(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+
+	if err := f.svc.UpdateRoleMenu(ctx, f.roleA, checkedTree(f.m1, f.a1), 10, false); err != nil {
+		t.Fatal(err)
+	}
+	// 平台把租户范围缩小为 m2 后，角色上超出范围的 m1/a1 应被清理
+	if _, err := f.svc.UpdateTenantMenuScope(ctx, 10, checkedTree(f.m2, f.a2)); err != nil {
+		t.Fatal(err)
+	}
+	_, _, menuIDs, authIDs, err := f.repos.Menus.ForRole(ctx, f.roleA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menuIDs) != 0 || len(authIDs) != 0 {
+		t.Fatalf("role menus = %v auths = %v, want none", menuIDs, authIDs)
+	}
+
+	tree, err := f.svc.GetTenantMenuTree(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range tree {
+		if m.HasPermission != (m.ID == f.m2) {
+			t.Fatalf("menu %d HasPermission = %v", m.ID, m.HasPermission)
+		}
+	}
+}
+
+func TestAddMenuRequiresEnabledParent(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+
+	if _, err := f.svc.AddMenu(ctx, AddMenuInput{Name: "child", ParentID: 999}); !errors.Is(err, ErrParentMenuNotFound) {
+		t.Fatalf("AddMenu() missing parent error = %v, want ErrParentMenuNotFound", err)
+	}
+	child, err := f.svc.AddMenu(ctx, AddMenuInput{Name: "child", ParentID: f.m1, Status: system.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.Level != 2 {
+		t.Fatalf("child level = %d, want 2", child.Level)
+	}
+	if _, err := f.svc.DeleteMenu(ctx, f.m1); !errors.Is(err, ErrMenuHasChildren) {
+		t.Fatalf("DeleteMenu() error = %v, want ErrMenuHasChildren", err)
+	}
+	if _, err := f.svc.UpdateMenu(ctx, UpdateMenuInput{ID: f.m1, Name: "m1", Status: system.StatusDisabled}); !errors.Is(err, ErrDisableMenuWithEnabledChild) {
+		t.Fatalf("UpdateMenu() error = %v, want ErrDisableMenuWithEnabledChild", err)
+	}
+}
+
+func TestExportImportMenus(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+
+	exported, err := f.svc.ExportMenus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Menus) != 2 || len(exported.Menus[0].Auths) != 1 || exported.Menus[0].Auths[0].ID != f.a1 {
+		t.Fatalf("ExportMenus() = %+v", exported)
+	}
+
+	// 导入到空库：保留原 ID 与父子关系
+	target := memory.New().Repos()
+	svc := NewService(target, nil)
+	exported.Menus[1].ParentID = f.m1
+	result, err := svc.ImportMenus(ctx, exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MenusCreated != 2 || result.AuthsCreated != 2 || result.MenusUpdated != 0 {
+		t.Fatalf("first import result = %+v", result)
+	}
+	child := system.SystemMenu{}
+	child.ID = f.m2
+	if err := target.Menus.Get(ctx, &child); err != nil || child.ParentID != f.m1 {
+		t.Fatalf("imported child = %+v, %v", child, err)
+	}
+
+	// 再次导入为更新
+	exported.Menus[0].Title = "renamed"
+	result, err = svc.ImportMenus(ctx, exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MenusUpdated != 2 || result.AuthsUpdated != 2 || result.MenusCreated != 0 {
+		t.Fatalf("second import result = %+v", result)
+	}
+	parent := system.SystemMenu{}
+	parent.ID = f.m1
+	if err := target.Menus.Get(ctx, &parent); err != nil || parent.Title != "renamed" {
+		t.Fatalf("updated menu = %+v, %v", parent, err)
+	}
+
+	// 父级菜单既不在文件中也不在库中时整体拒绝
+	orphan := MenuExport{Menus: []ExportedMenu{{ID: 100, ParentID: 999, Name: "orphan"}}}
+	if _, err := svc.ImportMenus(ctx, orphan); !errors.Is(err, ErrParentMenuNotFound) {
+		t.Fatalf("orphan import error = %v, want ErrParentMenuNotFound", err)
+	}
+}
+
+// staleTreeCache 模拟用户缓存中的角色滞后于数据库的情况
+type staleTreeCache struct {
+	roleID uint
+	trees  map[uint]systemmenu.CachedTree // 按角色记录写入的菜单树
+	bumps  int
+}
+
+func (c *staleTreeCache) RoleID(context.Context, uint, uint) (uint, bool) { return c.roleID, true }
+func (c *staleTreeCache) Version(context.Context) (int64, error)          { return 1, nil }
+func (c *staleTreeCache) BumpVersion(context.Context) error               { c.bumps++; return nil }
+func (c *staleTreeCache) Get(context.Context, int64, uint, uint) (*systemmenu.CachedTree, error) {
+	return nil, nil
+}
+func (c *staleTreeCache) Set(_ context.Context, _ int64, _, roleID uint, tree systemmenu.CachedTree) error {
+	c.trees[roleID] = tree
+	return nil
+}
+
+func TestGetUserMenuTreeCachesTreeOfCacheKeyRole(t *testing.T) {
+	ctx := context.Background()
+	f := newScopeFixture(t)
+	if err := f.repos.Roles.SaveMenus(ctx, f.roleA, []uint{f.m1}, []uint{f.a1}); err != nil {
+		t.Fatal(err)
+	}
+	// 数据库中用户已改为租户 10 下没有任何菜单的角色
+	roleC := system.SystemRole{TenantID: 10, Name: "c", Status: system.StatusEnabled}
+	if err := f.repos.Roles.Create(ctx, &roleC); err != nil {
+		t.Fatal(err)
+	}
+	user := system.SystemUser{TenantID: 10, Username: "u", RoleID: roleC.ID}
+	if err := f.repos.Users.Create(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := &staleTreeCache{roleID: f.roleA, trees: map[uint]systemmenu.CachedTree{}}
+	svc := NewService(f.repos, cache)
+	result, err := svc.GetUserMenuTree(ctx, user.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Tree) != 1 || result.Tree[0].ID != f.m1 {
+		t.Fatalf("tree = %+v, want role A's menu m1", result.Tree)
+	}
+	if cached, ok := cache.trees[f.roleA]; !ok || cached.ETag != result.ETag {
+		t.Fatalf("cached trees = %+v, want role A's tree under role A", cache.trees)
+	}
+
+	// 用户缓存中的角色不属于该租户时按数据库中的角色构建，且不写入缓存
+	cache = &staleTreeCache{roleID: f.roleB, trees: map[uint]systemmenu.CachedTree{}}
+	svc = NewService(f.repos, cache)
+	if result, err = svc.GetUserMenuTree(ctx, user.ID, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Tree) != 0 || len(cache.trees) != 0 {
+		t.Fatalf("tree = %+v cached = %+v, want empty tree and no cache write", result.Tree, cache.trees)
+	}
+}
+
+// failingImportRepo 导入时返回错误，模拟事务中途失败
+type failingImportRepo struct {
+	repo.MenuRepo
+}
+
+func (failingImportRepo) Import(context.Context, system.MenuImport) error {
+	return errors.New("duplicate key")
+}
+
+func TestImportMenusInvalidatesCacheOnFailure(t *testing.T) {
+	ctx := context.Background()
+	r := memory.New().Repos()
+	r.Menus = failingImportRepo{MenuRepo: r.Menus}
+	cache := &staleTreeCache{trees: map[uint]systemmenu.CachedTree{}}
+	svc := NewService(r, cache)
+
+	data := MenuExport{Menus: []ExportedMenu{{ID: 1, Name: "m1", Auths: []ExportedAuth{{ID: 1, Mark: "m1:add"}}}}}
+	if _, err := svc.ImportMenus(ctx, data); err == nil {
+		t.Fatal("ImportMenus() error = nil, want import failure")
+	}
+	if cache.bumps != 1 {
+		t.Fatalf("cache bumps = %d, want 1 after failed import", cache.bumps)
+	}
+	if menus, _, err := r.Menus.All(ctx); err != nil || len(menus) != 0 {
+		t.Fatalf("menus after failed import = %+v, %v, want none", menus, err)
+	}
+}
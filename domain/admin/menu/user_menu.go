@@ -1,25 +1,136 @@
 package menu
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
 	commonmenu "api-server/common/menu"
 	"api-server/db/pgdb/system"
+	systemmenu "api-server/db/rdb/systemMenu"
 )
 
-func GetUserMenuTree(userID uint, tenantID uint) ([]commonmenu.MenuResponse, error) {
-	roleMenus, rolePermissions, err := system.GetUserMenuData(userID)
+// UserMenuTree 用户菜单树及其 ETag（菜单树 JSON 的摘要）
+type UserMenuTree struct {
+	Tree []commonmenu.MenuResponse
+	ETag string
+}
+
+// GetUserMenuTree 返回用户可见的菜单树。
+// 结果按“菜单版本 + 租户 + 角色”缓存在 Redis 中，菜单相关数据变化时递增版本号使缓存失效；
+// 未配置缓存或 Redis 不可用时直接查询数据库。
+// 缓存键中的角色来自用户缓存，可能滞后于数据库（角色变更后用户缓存尚未刷新），
+// 因此写入缓存的菜单树同样按该角色构建，保证同一键下始终是该角色的菜单。
+func (s *Service) GetUserMenuTree(ctx context.Context, userID uint, tenantID uint) (UserMenuTree, error) {
+	if s.cache == nil {
+		return s.buildUserMenuTree(ctx, userID, tenantID)
+	}
+	roleID, ok := s.cache.RoleID(ctx, tenantID, userID)
+	if !ok {
+		return s.buildUserMenuTree(ctx, userID, tenantID)
+	}
+
+	// 先读取版本号再查询数据库：计算期间若版本递增，结果只会写入旧版本键，不会污染新版本
+	version, err := s.cache.Version(ctx)
+	if err != nil {
+		zap.L().Warn("读取菜单版本失败，跳过菜单缓存", zap.Error(err))
+		return s.buildUserMenuTree(ctx, userID, tenantID)
+	}
+	if cached, err := s.cache.Get(ctx, version, tenantID, roleID); err != nil {
+		zap.L().Warn("读取菜单树缓存失败", zap.Error(err))
+	} else if cached != nil {
+		var tree []commonmenu.MenuResponse
+		if err = json.Unmarshal(cached.Tree, &tree); err == nil {
+			return UserMenuTree{Tree: tree, ETag: cached.ETag}, nil
+		}
+		zap.L().Warn("菜单树缓存内容无效", zap.Error(err))
+	}
+
+	result, ok, err := s.buildRoleMenuTree(ctx, roleID, tenantID)
+	if err != nil {
+		return UserMenuTree{}, err
+	}
+	if !ok {
+		// 用户缓存中的角色已不属于该租户或已禁用，直接按数据库中的当前角色构建且不写入缓存
+		return s.buildUserMenuTree(ctx, userID, tenantID)
+	}
+	if raw, err := json.Marshal(result.Tree); err == nil {
+		if err = s.cache.Set(ctx, version, tenantID, roleID, systemmenu.CachedTree{ETag: result.ETag, Tree: raw}); err != nil {
+			zap.L().Warn("写入菜单树缓存失败", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+// buildUserMenuTree 按数据库中用户当前的角色构建菜单树
+func (s *Service) buildUserMenuTree(ctx context.Context, userID uint, tenantID uint) (UserMenuTree, error) {
+	roleMenus, rolePermissions, err := s.menus.ForUser(ctx, userID)
+	if err != nil {
+		return UserMenuTree{}, err
+	}
+	return s.scopedMenuTree(ctx, tenantID, roleMenus, rolePermissions)
+}
+
+// buildRoleMenuTree 按指定角色构建菜单树；角色不存在、不属于该租户或已禁用时返回 ok=false
+func (s *Service) buildRoleMenuTree(ctx context.Context, roleID uint, tenantID uint) (UserMenuTree, bool, error) {
+	role := system.SystemRole{Model: gorm.Model{ID: roleID}}
+	if err := s.roles.Get(ctx, &role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return UserMenuTree{}, false, nil
+		}
+		return UserMenuTree{}, false, err
+	}
+	if role.TenantID != tenantID || role.Status != system.StatusEnabled {
+		return UserMenuTree{}, false, nil
+	}
+
+	allMenus, allAuths, roleMenuIDs, roleAuthIDs, err := s.menus.ForRole(ctx, roleID)
 	if err != nil {
-		return nil, err
+		return UserMenuTree{}, false, err
+	}
+	menuSet := idSet(roleMenuIDs)
+	roleMenus := make([]system.SystemMenu, 0, len(roleMenuIDs))
+	for _, m := range allMenus {
+		if _, ok := menuSet[m.ID]; ok {
+			roleMenus = append(roleMenus, m)
+		}
+	}
+	authSet := idSet(roleAuthIDs)
+	rolePermissions := make([]system.SystemMenuAuth, 0, len(roleAuthIDs))
+	for _, a := range allAuths {
+		if _, ok := authSet[a.ID]; ok {
+			rolePermissions = append(rolePermissions, a)
+		}
 	}
 
-	scopeIDs, err := system.GetTenantMenuScopeIDs(tenantID)
+	tree, err := s.scopedMenuTree(ctx, tenantID, roleMenus, rolePermissions)
+	return tree, err == nil, err
+}
+
+func idSet(ids []uint) map[uint]struct{} {
+	set := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// scopedMenuTree 按租户的菜单、按钮权限范围过滤角色菜单后构建菜单树
+func (s *Service) scopedMenuTree(ctx context.Context, tenantID uint, roleMenus []system.SystemMenu, rolePermissions []system.SystemMenuAuth) (UserMenuTree, error) {
+	scopeIDs, err := s.tenants.MenuScope(ctx, tenantID)
 	if err != nil {
-		return nil, err
+		return UserMenuTree{}, err
 	}
 	roleMenus, rolePermissions = system.FilterMenusByIDs(roleMenus, rolePermissions, scopeIDs)
 
-	authScopeIDs, err := system.GetTenantAuthScopeIDs(tenantID)
+	authScopeIDs, err := s.tenants.AuthScope(ctx, tenantID)
 	if err != nil {
-		return nil, err
+		return UserMenuTree{}, err
 	}
 	rolePermissions = filterAuthsByScope(rolePermissions, authScopeIDs)
 
@@ -32,6 +143,27 @@ func GetUserMenuTree(userID uint, tenantID uint) ([]commonmenu.MenuResponse, err
 		roleAuthIDs = append(roleAuthIDs, a.ID)
 	}
 
-	return commonmenu.BuildMenuTreeWithPermission(roleMenus, rolePermissions, roleMenuIDs, roleAuthIDs, false), nil
+	tree := commonmenu.BuildMenuTreeWithPermission(roleMenus, rolePermissions, roleMenuIDs, roleAuthIDs, false)
+	return UserMenuTree{Tree: tree, ETag: menuETag(tree)}, nil
 }
 
+// menuETag 以菜单树 JSON 的 SHA-256 前 16 字节作为强 ETag
+func menuETag(tree []commonmenu.MenuResponse) string {
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// invalidateCache 菜单相关数据变化后递增版本号；失败只记录日志，缓存最多在过期后自愈
+func (s *Service) invalidateCache(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	// 数据已提交，版本递增不应因请求取消而放弃
+	if err := s.cache.BumpVersion(context.WithoutCancel(ctx)); err != nil {
+		zap.L().Warn("递增菜单版本失败，菜单树缓存将在过期后刷新", zap.Error(err))
+	}
+}
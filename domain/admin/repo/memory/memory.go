@@ -0,0 +1,918 @@
+// Package memory 提供 repo 中各仓储接口的内存实现，用于在没有 PostgreSQL 的情况下测试领域服务。
+// 行为尽量与 GORM 实现保持一致：记录不存在返回 gorm.ErrRecordNotFound，Update 只更新非零字段，
+// 新增/修改用户时对密码做 bcrypt 哈希。
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+)
+
+// Store 保存全部内存数据；同一个 Store 返回的各仓储共享数据，与同一数据库上的 GORM 实现一致
+type Store struct {
+	mu     sync.Mutex
+	nextID uint
+
+	users       map[uint]system.SystemUser
+	loginLogs   []system.SystemUserLoginLog
+	roles       map[uint]system.SystemRole
+	tenants     map[uint]system.SystemTenant
+	departments map[uint]system.SystemDepartment
+	menus       map[uint]system.SystemMenu
+	auths       map[uint]system.SystemMenuAuth
+	ipRules     []system.SystemIPRule
+	rateLimits  []system.SystemTenantRateLimit
+
+	tenantMenus map[uint][]uint // 租户菜单范围
+	tenantAuths map[uint][]uint // 租户按钮权限范围
+	roleMenus   map[uint][]uint // 角色-菜单关联
+	roleAuths   map[uint][]uint // 角色-按钮权限关联
+}
+
+func New() *Store {
+	return &Store{
+		users:       make(map[uint]system.SystemUser),
+		roles:       make(map[uint]system.SystemRole),
+		tenants:     make(map[uint]system.SystemTenant),
+		departments: make(map[uint]system.SystemDepartment),
+		menus:       make(map[uint]system.SystemMenu),
+		auths:       make(map[uint]system.SystemMenuAuth),
+		tenantMenus: make(map[uint][]uint),
+		tenantAuths: make(map[uint][]uint),
+		roleMenus:   make(map[uint][]uint),
+		roleAuths:   make(map[uint][]uint),
+	}
+}
+
+// Repos 返回基于该 Store 的全部仓储
+func (s *Store) Repos() repo.Repos {
+	return repo.Repos{
+		Users:       userRepo{s},
+		LoginLogs:   loginLogRepo{s},
+		Roles:       roleRepo{s},
+		Tenants:     tenantRepo{s},
+		Departments: departmentRepo{s},
+		Menus:       menuRepo{s},
+		IPRules:     ipRuleRepo{s},
+		RateLimits:  rateLimitRepo{s},
+	}
+}
+
+// newModel 分配自增 ID；所有表共用一个序列，便于测试中区分不同实体。
+// id 非零时沿用调用方指定的主键（对应 GORM 插入显式 ID 的行为）
+func (s *Store) newModel(id uint) gorm.Model {
+	if id == 0 {
+		s.nextID++
+		id = s.nextID
+	} else if id > s.nextID {
+		s.nextID = id
+	}
+	now := time.Now()
+	return gorm.Model{ID: id, CreatedAt: now, UpdatedAt: now}
+}
+
+// mergeNonZero 将 src 中的非零字段覆盖到 dst，对应 GORM 以结构体调用 Updates 的语义；
+// gorm.Model 与关联切片不参与合并
+func mergeNonZero[T any](dst *T, src T) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src)
+	for i := 0; i < sv.NumField(); i++ {
+		field := sv.Type().Field(i)
+		if field.Anonymous || field.Type.Kind() == reflect.Slice {
+			continue
+		}
+		if v := sv.Field(i); !v.IsZero() {
+			dv.Field(i).Set(v)
+		}
+	}
+	dv.FieldByName("UpdatedAt").Set(reflect.ValueOf(time.Now()))
+}
+
+// paginate 与 GORM 实现一致：page/pageSize 均为 config.CancelPage 时返回全部
+func paginate[T any](items []T, page, pageSize int) []T {
+	if page == config.CancelPage && pageSize == config.CancelPageSize {
+		return items
+	}
+	start := (page - 1) * pageSize
+	if start < 0 || start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+pageSize, len(items))]
+}
+
+func sortedValues[T any](m map[uint]T) []T {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	out := make([]T, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, m[id])
+	}
+	return out
+}
+
+func contains(s, substr string) bool {
+	return substr == "" || strings.Contains(s, substr)
+}
+
+var errTenantRequired = errors.New("tenant id is required")
+
+type userRepo struct{ s *Store }
+
+func (r userRepo) List(ctx context.Context, filter *system.SystemUser, page, pageSize int) ([]system.UserWithRelations, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.UserWithRelations
+	for _, u := range sortedValues(r.s.users) {
+		if filter.TenantID != 0 && u.TenantID != filter.TenantID {
+			continue
+		}
+		if filter.RoleID != 0 && u.RoleID != filter.RoleID {
+			continue
+		}
+		if filter.DepartmentID != 0 && u.DepartmentID != filter.DepartmentID {
+			continue
+		}
+		if !contains(u.Username, filter.Username) || !contains(u.Name, filter.Name) || !contains(u.Phone, filter.Phone) {
+			continue
+		}
+		role := r.s.roles[u.RoleID]
+		matched = append(matched, system.UserWithRelations{
+			SystemUser:     u,
+			RoleName:       role.Name,
+			RoleDesc:       role.Desc,
+			DepartmentName: r.s.departments[u.DepartmentID].Name,
+		})
+	}
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+func (r userRepo) Get(ctx context.Context, user *system.SystemUser) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range sortedValues(r.s.users) {
+		if (user.ID == 0 || u.ID == user.ID) &&
+			(user.TenantID == 0 || u.TenantID == user.TenantID) &&
+			(user.Account == "" || u.Account == user.Account) {
+			*user = u
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r userRepo) Create(ctx context.Context, user *system.SystemUser) error {
+	hashed, err := system.HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.users {
+		if u.TenantID == user.TenantID && u.Account == user.Account {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	user.Password = hashed
+	user.Model = r.s.newModel(user.ID)
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r userRepo) Update(ctx context.Context, user *system.SystemUser) error {
+	if user.Password != "" {
+		hashed, err := system.HashPassword(user.Password)
+		if err != nil {
+			return err
+		}
+		user.Password = hashed
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.users[user.ID]
+	if !ok {
+		return nil
+	}
+	mergeNonZero(&existing, *user)
+	r.s.users[user.ID] = existing
+	return nil
+}
+
+func (r userRepo) Delete(ctx context.Context, user *system.SystemUser) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.users, user.ID)
+	return nil
+}
+
+func (r userRepo) Verify(ctx context.Context, tenantCode, account, password string) (system.SystemUser, system.SystemTenant, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var tenant system.SystemTenant
+	for _, t := range r.s.tenants {
+		if t.Code == tenantCode && t.Status == system.StatusEnabled {
+			tenant = t
+			break
+		}
+	}
+	if tenant.ID == 0 {
+		return system.SystemUser{}, tenant, nil
+	}
+	for _, u := range r.s.users {
+		if u.TenantID == tenant.ID && u.Account == account {
+			if !system.VerifyPassword(password, u.Password, "bcrypt") {
+				return system.SystemUser{}, tenant, nil
+			}
+			return u, tenant, nil
+		}
+	}
+	return system.SystemUser{}, tenant, nil
+}
+
+func (r userRepo) CountByDepartment(ctx context.Context, departmentID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var count int64
+	for _, u := range r.s.users {
+		if u.DepartmentID == departmentID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type loginLogRepo struct{ s *Store }
+
+func (r loginLogRepo) Create(ctx context.Context, log *system.SystemUserLoginLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	log.Model = r.s.newModel(log.ID)
+	r.s.loginLogs = append(r.s.loginLogs, *log)
+	return nil
+}
+
+func (r loginLogRepo) List(ctx context.Context, filter *system.SystemUserLoginLog, page, pageSize int) ([]system.SystemUserLoginLog, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemUserLoginLog
+	// 与 GORM 实现一致，按创建时间倒序
+	for i := len(r.s.loginLogs) - 1; i >= 0; i-- {
+		log := r.s.loginLogs[i]
+		if contains(log.UserName, filter.UserName) && contains(log.IP, filter.IP) {
+			matched = append(matched, log)
+		}
+	}
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+type roleRepo struct{ s *Store }
+
+func (r roleRepo) List(ctx context.Context, filter *system.SystemRole, page, pageSize int) ([]system.SystemRole, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemRole
+	roles := sortedValues(r.s.roles)
+	// 与 GORM 实现一致，按 ID 倒序
+	slices.Reverse(roles)
+	for _, role := range roles {
+		if filter.TenantID != 0 && role.TenantID != filter.TenantID {
+			continue
+		}
+		if filter.Status != 0 && role.Status != filter.Status {
+			continue
+		}
+		if !contains(role.Name, filter.Name) {
+			continue
+		}
+		matched = append(matched, role)
+	}
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+func (r roleRepo) Get(ctx context.Context, role *system.SystemRole) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, item := range sortedValues(r.s.roles) {
+		if (role.ID == 0 || item.ID == role.ID) && (role.TenantID == 0 || item.TenantID == role.TenantID) {
+			*role = item
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r roleRepo) Create(ctx context.Context, role *system.SystemRole) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	role.Model = r.s.newModel(role.ID)
+	r.s.roles[role.ID] = *role
+	return nil
+}
+
+func (r roleRepo) Update(ctx context.Context, role *system.SystemRole) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.roles[role.ID]; ok {
+		mergeNonZero(&existing, *role)
+		r.s.roles[role.ID] = existing
+	}
+	return nil
+}
+
+func (r roleRepo) Delete(ctx context.Context, role *system.SystemRole) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.roles, role.ID)
+	delete(r.s.roleMenus, role.ID)
+	delete(r.s.roleAuths, role.ID)
+	return nil
+}
+
+func (r roleRepo) SaveMenus(ctx context.Context, roleID uint, menuIDs, authIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.roles[roleID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.s.roleMenus[roleID] = slices.Clone(menuIDs)
+	r.s.roleAuths[roleID] = slices.Clone(authIDs)
+	return nil
+}
+
+type tenantRepo struct{ s *Store }
+
+func (r tenantRepo) List(ctx context.Context, filter *system.SystemTenant, page, pageSize int) ([]system.SystemTenant, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemTenant
+	tenants := sortedValues(r.s.tenants)
+	slices.Reverse(tenants)
+	for _, t := range tenants {
+		if filter.Status != 0 && t.Status != filter.Status {
+			continue
+		}
+		if !contains(t.Code, filter.Code) || !contains(t.Name, filter.Name) {
+			continue
+		}
+		matched = append(matched, t)
+	}
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+func (r tenantRepo) Get(ctx context.Context, tenant *system.SystemTenant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, t := range sortedValues(r.s.tenants) {
+		if (tenant.ID == 0 || t.ID == tenant.ID) && (tenant.Code == "" || t.Code == tenant.Code) {
+			*tenant = t
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r tenantRepo) Create(ctx context.Context, tenant *system.SystemTenant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, t := range r.s.tenants {
+		if t.Code == tenant.Code {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if tenant.Status == 0 {
+		tenant.Status = system.StatusEnabled
+	}
+	tenant.Model = r.s.newModel(tenant.ID)
+	r.s.tenants[tenant.ID] = *tenant
+	return nil
+}
+
+func (r tenantRepo) Update(ctx context.Context, tenant *system.SystemTenant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.tenants[tenant.ID]; ok {
+		mergeNonZero(&existing, *tenant)
+		r.s.tenants[tenant.ID] = existing
+	}
+	return nil
+}
+
+func (r tenantRepo) Delete(ctx context.Context, tenant *system.SystemTenant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.tenants, tenant.ID)
+	return nil
+}
+
+func (r tenantRepo) SetAllowedOrigins(ctx context.Context, tenantID uint, origins string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.tenants[tenantID]; ok {
+		existing.AllowedOrigins = origins
+		r.s.tenants[tenantID] = existing
+	}
+	return nil
+}
+
+func (r tenantRepo) AllowedOrigins(ctx context.Context) ([]system.SystemTenant, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemTenant
+	for _, t := range r.s.tenants {
+		if t.Status == system.StatusEnabled && t.AllowedOrigins != "" {
+			matched = append(matched, system.SystemTenant{Model: gorm.Model{ID: t.ID}, AllowedOrigins: t.AllowedOrigins})
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+	return matched, nil
+}
+
+func (r tenantRepo) SuggestByCode(ctx context.Context, code string, limit int) ([]system.SystemTenant, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemTenant
+	tenants := sortedValues(r.s.tenants)
+	slices.Reverse(tenants)
+	for _, t := range tenants {
+		if t.Status == system.StatusEnabled && contains(t.Code, code) {
+			matched = append(matched, t)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+func (r tenantRepo) MenuScope(ctx context.Context, tenantID uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return slices.Clone(r.s.tenantMenus[tenantID]), nil
+}
+
+func (r tenantRepo) AuthScope(ctx context.Context, tenantID uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return slices.Clone(r.s.tenantAuths[tenantID]), nil
+}
+
+func (r tenantRepo) SaveMenuScope(ctx context.Context, tenantID uint, menuIDs []uint) error {
+	if tenantID == 0 {
+		return errTenantRequired
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range menuIDs {
+		if _, ok := r.s.menus[id]; !ok {
+			return errors.New("invalid menu ids provided")
+		}
+	}
+	r.s.tenantMenus[tenantID] = slices.Clone(menuIDs)
+	return nil
+}
+
+func (r tenantRepo) SaveAuthScope(ctx context.Context, tenantID uint, authIDs []uint) error {
+	if tenantID == 0 {
+		return errTenantRequired
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range authIDs {
+		if _, ok := r.s.auths[id]; !ok {
+			return errors.New("invalid auth ids provided")
+		}
+	}
+	r.s.tenantAuths[tenantID] = slices.Clone(authIDs)
+	return nil
+}
+
+func (r tenantRepo) PruneRoleAssociations(ctx context.Context, tenantID uint, menuIDs, authIDs []uint) error {
+	if tenantID == 0 {
+		return errTenantRequired
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, role := range r.s.roles {
+		if role.TenantID != tenantID {
+			continue
+		}
+		r.s.roleMenus[id] = keep(r.s.roleMenus[id], menuIDs)
+		r.s.roleAuths[id] = keep(r.s.roleAuths[id], authIDs)
+	}
+	return nil
+}
+
+// keep 返回 ids 中同时出现在 allowed 中的元素
+func keep(ids, allowed []uint) []uint {
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if slices.Contains(allowed, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+type departmentRepo struct{ s *Store }
+
+func (r departmentRepo) List(ctx context.Context, filter *system.SystemDepartment, page, pageSize int) ([]system.SystemDepartment, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemDepartment
+	for _, d := range sortedValues(r.s.departments) {
+		if filter.Status != 0 && d.Status != filter.Status {
+			continue
+		}
+		if !contains(d.Name, filter.Name) {
+			continue
+		}
+		matched = append(matched, d)
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Sort < matched[j].Sort })
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+func (r departmentRepo) Get(ctx context.Context, department *system.SystemDepartment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if d, ok := r.s.departments[department.ID]; ok {
+		*department = d
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r departmentRepo) Create(ctx context.Context, department *system.SystemDepartment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	department.Model = r.s.newModel(department.ID)
+	r.s.departments[department.ID] = *department
+	return nil
+}
+
+func (r departmentRepo) Update(ctx context.Context, department *system.SystemDepartment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.departments[department.ID]; ok {
+		mergeNonZero(&existing, *department)
+		r.s.departments[department.ID] = existing
+	}
+	return nil
+}
+
+func (r departmentRepo) Delete(ctx context.Context, department *system.SystemDepartment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.departments, department.ID)
+	return nil
+}
+
+type menuRepo struct{ s *Store }
+
+func (r menuRepo) All(ctx context.Context) ([]system.SystemMenu, []system.SystemMenuAuth, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return sortedValues(r.s.menus), sortedValues(r.s.auths), nil
+}
+
+func (r menuRepo) ForUser(ctx context.Context, userID uint) ([]system.SystemMenu, []system.SystemMenuAuth, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	if _, ok := r.s.roles[user.RoleID]; !ok {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	var menus []system.SystemMenu
+	for _, id := range r.s.roleMenus[user.RoleID] {
+		if m, ok := r.s.menus[id]; ok {
+			menus = append(menus, m)
+		}
+	}
+	var auths []system.SystemMenuAuth
+	for _, id := range r.s.roleAuths[user.RoleID] {
+		if a, ok := r.s.auths[id]; ok {
+			auths = append(auths, a)
+		}
+	}
+	return menus, auths, nil
+}
+
+func (r menuRepo) ForRole(ctx context.Context, roleID uint) ([]system.SystemMenu, []system.SystemMenuAuth, []uint, []uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.roles[roleID]; !ok {
+		return nil, nil, nil, nil, gorm.ErrRecordNotFound
+	}
+	return sortedValues(r.s.menus), sortedValues(r.s.auths),
+		slices.Clone(r.s.roleMenus[roleID]), slices.Clone(r.s.roleAuths[roleID]), nil
+}
+
+func (r menuRepo) List(ctx context.Context, filter *system.SystemMenu, page, pageSize int) ([]system.SystemMenu, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemMenu
+	for _, m := range sortedValues(r.s.menus) {
+		if filter.ParentID != 0 && m.ParentID != filter.ParentID {
+			continue
+		}
+		if filter.Status != 0 && m.Status != filter.Status {
+			continue
+		}
+		if !contains(m.Title, filter.Title) || !contains(m.Name, filter.Name) || !contains(m.Path, filter.Path) {
+			continue
+		}
+		matched = append(matched, m)
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Sort < matched[j].Sort })
+	return paginate(matched, page, pageSize), int64(len(matched)), nil
+}
+
+func (r menuRepo) Get(ctx context.Context, menu *system.SystemMenu) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if m, ok := r.s.menus[menu.ID]; ok {
+		*menu = m
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r menuRepo) Create(ctx context.Context, menu *system.SystemMenu) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	menu.Model = r.s.newModel(menu.ID)
+	r.s.menus[menu.ID] = *menu
+	return nil
+}
+
+func (r menuRepo) Update(ctx context.Context, menu *system.SystemMenu) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.menus[menu.ID]; ok {
+		mergeNonZero(&existing, *menu)
+		r.s.menus[menu.ID] = existing
+	}
+	return nil
+}
+
+func (r menuRepo) Delete(ctx context.Context, menu *system.SystemMenu) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.menus, menu.ID)
+	return nil
+}
+
+// Import 持有锁一次性写入，内存实现不会中途失败，整体即为原子操作
+func (r menuRepo) Import(ctx context.Context, data system.MenuImport) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, menu := range data.CreateMenus {
+		menu.Model = r.s.newModel(menu.ID)
+		r.s.menus[menu.ID] = menu
+	}
+	for _, menu := range data.UpdateMenus {
+		if existing, ok := r.s.menus[menu.ID]; ok {
+			mergeNonZero(&existing, menu)
+			r.s.menus[menu.ID] = existing
+		}
+	}
+	for _, auth := range data.CreateAuths {
+		auth.Model = r.s.newModel(auth.ID)
+		r.s.auths[auth.ID] = auth
+	}
+	for _, auth := range data.UpdateAuths {
+		if existing, ok := r.s.auths[auth.ID]; ok {
+			mergeNonZero(&existing, auth)
+			r.s.auths[auth.ID] = existing
+		}
+	}
+	return nil
+}
+
+func (r menuRepo) ListAuths(ctx context.Context, filter *system.SystemMenuAuth) ([]system.SystemMenuAuth, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemMenuAuth
+	for _, a := range sortedValues(r.s.auths) {
+		if (filter.MenuID == 0 || a.MenuID == filter.MenuID) &&
+			(filter.Mark == "" || a.Mark == filter.Mark) &&
+			(filter.Title == "" || a.Title == filter.Title) {
+			matched = append(matched, a)
+		}
+	}
+	return matched, nil
+}
+
+func (r menuRepo) CreateAuth(ctx context.Context, auth *system.SystemMenuAuth) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	auth.Model = r.s.newModel(auth.ID)
+	r.s.auths[auth.ID] = *auth
+	return nil
+}
+
+func (r menuRepo) UpdateAuth(ctx context.Context, auth *system.SystemMenuAuth) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.auths[auth.ID]; ok {
+		mergeNonZero(&existing, *auth)
+		r.s.auths[auth.ID] = existing
+	}
+	return nil
+}
+
+func (r menuRepo) DeleteAuth(ctx context.Context, auth *system.SystemMenuAuth) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.auths, auth.ID)
+	return nil
+}
+
+type ipRuleRepo struct{ s *Store }
+
+func (r ipRuleRepo) All(ctx context.Context) ([]system.SystemIPRule, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	items := slices.Clone(r.s.ipRules)
+	sortIPRules(items)
+	return items, nil
+}
+
+func (r ipRuleRepo) Find(ctx context.Context, scope string, tenantID uint) ([]system.SystemIPRule, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var matched []system.SystemIPRule
+	for _, item := range r.s.ipRules {
+		if item.Scope == scope && item.TenantID == tenantID {
+			matched = append(matched, item)
+		}
+	}
+	sortIPRules(matched)
+	return matched, nil
+}
+
+func (r ipRuleRepo) Replace(ctx context.Context, scope string, tenantID uint, items []system.SystemIPRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	kept := r.s.ipRules[:0:0]
+	for _, item := range r.s.ipRules {
+		if item.Scope != scope || item.TenantID != tenantID {
+			kept = append(kept, item)
+		}
+	}
+	for _, item := range items {
+		item.Model = r.s.newModel(0)
+		kept = append(kept, item)
+	}
+	r.s.ipRules = kept
+	return nil
+}
+
+// sortIPRules 与 GORM 实现的查询顺序一致：scope, tenant_id, action, cidr
+func sortIPRules(items []system.SystemIPRule) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.TenantID != b.TenantID {
+			return a.TenantID < b.TenantID
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		return a.CIDR < b.CIDR
+	})
+}
+
+type rateLimitRepo struct{ s *Store }
+
+// All 与 GORM 实现一致按 tenant_id, route_group 排序
+func (r rateLimitRepo) All(ctx context.Context) ([]system.SystemTenantRateLimit, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	items := slices.Clone(r.s.rateLimits)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TenantID != items[j].TenantID {
+			return items[i].TenantID < items[j].TenantID
+		}
+		return items[i].RouteGroup < items[j].RouteGroup
+	})
+	return items, nil
+}
+
+func (r rateLimitRepo) ForTenant(ctx context.Context, tenantID uint) ([]system.SystemTenantRateLimit, error) {
+	all, _ := r.All(ctx)
+	var matched []system.SystemTenantRateLimit
+	for _, item := range all {
+		if item.TenantID == tenantID {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+func (r rateLimitRepo) Save(ctx context.Context, item *system.SystemTenantRateLimit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i, existing := range r.s.rateLimits {
+		if existing.TenantID == item.TenantID && existing.RouteGroup == item.RouteGroup {
+			item.Model = existing.Model
+			item.UpdatedAt = time.Now()
+			r.s.rateLimits[i] = *item
+			return nil
+		}
+	}
+	item.Model = r.s.newModel(item.ID)
+	r.s.rateLimits = append(r.s.rateLimits, *item)
+	return nil
+}
+
+func (r rateLimitRepo) Delete(ctx context.Context, tenantID uint, routeGroup string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	before := len(r.s.rateLimits)
+	r.s.rateLimits = slices.DeleteFunc(r.s.rateLimits, func(item system.SystemTenantRateLimit) bool {
+		return item.TenantID == tenantID && item.RouteGroup == routeGroup
+	})
+	return int64(before - len(r.s.rateLimits)), nil
+}
+
+func (r rateLimitRepo) DeleteTenant(ctx context.Context, tenantID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.rateLimits = slices.DeleteFunc(r.s.rateLimits, func(item system.SystemTenantRateLimit) bool {
+		return item.TenantID == tenantID
+	})
+	return nil
+}
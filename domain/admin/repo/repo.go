@@ -0,0 +1,142 @@
+// Package repo 定义 admin 领域服务依赖的数据访问接口。
+// 生产环境使用 db/pgdb/system 中基于 GORM 的实现（见 NewGormRepos），
+// 单元测试使用 repo/memory 中的内存实现，领域服务通过构造函数注入而非直接访问全局数据库连接。
+package repo
+
+import (
+	"context"
+
+	"api-server/db/pgdb/system"
+)
+
+// UserRepo 用户数据访问
+type UserRepo interface {
+	// List 按条件分页查询用户（含角色、部门名称）
+	List(ctx context.Context, filter *system.SystemUser, page, pageSize int) ([]system.UserWithRelations, int64, error)
+	// Get 按 user 中的非零字段查询单个用户并回填；不存在时返回 gorm.ErrRecordNotFound
+	Get(ctx context.Context, user *system.SystemUser) error
+	Create(ctx context.Context, user *system.SystemUser) error
+	Update(ctx context.Context, user *system.SystemUser) error
+	Delete(ctx context.Context, user *system.SystemUser) error
+	// Verify 校验租户与账号密码；账号或密码错误时返回 ID 为 0 的用户
+	Verify(ctx context.Context, tenantCode, account, password string) (system.SystemUser, system.SystemTenant, error)
+	// CountByDepartment 统计部门下的用户数量
+	CountByDepartment(ctx context.Context, departmentID uint) (int64, error)
+}
+
+// LoginLogRepo 登录日志数据访问
+type LoginLogRepo interface {
+	Create(ctx context.Context, log *system.SystemUserLoginLog) error
+	List(ctx context.Context, filter *system.SystemUserLoginLog, page, pageSize int) ([]system.SystemUserLoginLog, int64, error)
+}
+
+// RoleRepo 角色数据访问
+type RoleRepo interface {
+	List(ctx context.Context, filter *system.SystemRole, page, pageSize int) ([]system.SystemRole, int64, error)
+	Get(ctx context.Context, role *system.SystemRole) error
+	Create(ctx context.Context, role *system.SystemRole) error
+	Update(ctx context.Context, role *system.SystemRole) error
+	Delete(ctx context.Context, role *system.SystemRole) error
+	// SaveMenus 全量覆盖角色的菜单与按钮权限关联
+	SaveMenus(ctx context.Context, roleID uint, menuIDs, authIDs []uint) error
+}
+
+// TenantRepo 租户及其菜单/按钮权限范围数据访问
+type TenantRepo interface {
+	List(ctx context.Context, filter *system.SystemTenant, page, pageSize int) ([]system.SystemTenant, int64, error)
+	Get(ctx context.Context, tenant *system.SystemTenant) error
+	Create(ctx context.Context, tenant *system.SystemTenant) error
+	Update(ctx context.Context, tenant *system.SystemTenant) error
+	Delete(ctx context.Context, tenant *system.SystemTenant) error
+	// SetAllowedOrigins 覆盖租户允许跨域的来源（逗号分隔），空字符串表示清空
+	SetAllowedOrigins(ctx context.Context, tenantID uint, origins string) error
+	// AllowedOrigins 返回登记了跨域来源的启用租户（仅含 ID 与 AllowedOrigins）
+	AllowedOrigins(ctx context.Context) ([]system.SystemTenant, error)
+	// SuggestByCode 按代码模糊匹配启用中的租户
+	SuggestByCode(ctx context.Context, code string, limit int) ([]system.SystemTenant, error)
+	MenuScope(ctx context.Context, tenantID uint) ([]uint, error)
+	AuthScope(ctx context.Context, tenantID uint) ([]uint, error)
+	SaveMenuScope(ctx context.Context, tenantID uint, menuIDs []uint) error
+	SaveAuthScope(ctx context.Context, tenantID uint, authIDs []uint) error
+	// PruneRoleAssociations 移除租户下角色超出范围的菜单/按钮权限关联
+	PruneRoleAssociations(ctx context.Context, tenantID uint, menuIDs, authIDs []uint) error
+}
+
+// DepartmentRepo 部门数据访问
+type DepartmentRepo interface {
+	List(ctx context.Context, filter *system.SystemDepartment, page, pageSize int) ([]system.SystemDepartment, int64, error)
+	Get(ctx context.Context, department *system.SystemDepartment) error
+	Create(ctx context.Context, department *system.SystemDepartment) error
+	Update(ctx context.Context, department *system.SystemDepartment) error
+	Delete(ctx context.Context, department *system.SystemDepartment) error
+}
+
+// MenuRepo 菜单与按钮权限数据访问
+type MenuRepo interface {
+	// All 返回全部菜单与按钮权限
+	All(ctx context.Context) ([]system.SystemMenu, []system.SystemMenuAuth, error)
+	// ForUser 返回用户所属角色关联的菜单与按钮权限
+	ForUser(ctx context.Context, userID uint) ([]system.SystemMenu, []system.SystemMenuAuth, error)
+	// ForRole 返回全部菜单、按钮权限以及角色已关联的菜单 ID、按钮权限 ID
+	ForRole(ctx context.Context, roleID uint) ([]system.SystemMenu, []system.SystemMenuAuth, []uint, []uint, error)
+	List(ctx context.Context, filter *system.SystemMenu, page, pageSize int) ([]system.SystemMenu, int64, error)
+	Get(ctx context.Context, menu *system.SystemMenu) error
+	Create(ctx context.Context, menu *system.SystemMenu) error
+	Update(ctx context.Context, menu *system.SystemMenu) error
+	Delete(ctx context.Context, menu *system.SystemMenu) error
+	// Import 在单个事务中以显式 ID 新建、更新菜单与按钮权限，失败时不保留任何写入
+	Import(ctx context.Context, data system.MenuImport) error
+	ListAuths(ctx context.Context, filter *system.SystemMenuAuth) ([]system.SystemMenuAuth, error)
+	CreateAuth(ctx context.Context, auth *system.SystemMenuAuth) error
+	UpdateAuth(ctx context.Context, auth *system.SystemMenuAuth) error
+	DeleteAuth(ctx context.Context, auth *system.SystemMenuAuth) error
+}
+
+// IPRuleRepo IP 访问控制规则数据访问
+type IPRuleRepo interface {
+	// All 返回所有作用域的规则
+	All(ctx context.Context) ([]system.SystemIPRule, error)
+	// Find 返回指定作用域的规则，非 tenant 作用域的 tenantID 为 0
+	Find(ctx context.Context, scope string, tenantID uint) ([]system.SystemIPRule, error)
+	// Replace 全量覆盖指定作用域的规则，items 为空表示清空
+	Replace(ctx context.Context, scope string, tenantID uint, items []system.SystemIPRule) error
+}
+
+// RateLimitRepo 租户限流覆盖数据访问
+type RateLimitRepo interface {
+	// All 返回所有租户的覆盖
+	All(ctx context.Context) ([]system.SystemTenantRateLimit, error)
+	ForTenant(ctx context.Context, tenantID uint) ([]system.SystemTenantRateLimit, error)
+	// Save 按租户与路由组新增或更新覆盖
+	Save(ctx context.Context, item *system.SystemTenantRateLimit) error
+	// Delete 删除租户在路由组上的覆盖，返回删除的行数
+	Delete(ctx context.Context, tenantID uint, routeGroup string) (int64, error)
+	// DeleteTenant 删除租户的全部覆盖
+	DeleteTenant(ctx context.Context, tenantID uint) error
+}
+
+// Repos 领域服务所需的全部数据访问依赖
+type Repos struct {
+	Users       UserRepo
+	LoginLogs   LoginLogRepo
+	Roles       RoleRepo
+	Tenants     TenantRepo
+	Departments DepartmentRepo
+	Menus       MenuRepo
+	IPRules     IPRuleRepo
+	RateLimits  RateLimitRepo
+}
+
+// NewGormRepos 返回基于 PostgreSQL（GORM）的实现
+func NewGormRepos() Repos {
+	return Repos{
+		Users:       system.UserRepo{},
+		LoginLogs:   system.LoginLogRepo{},
+		Roles:       system.RoleRepo{},
+		Tenants:     system.TenantRepo{},
+		Departments: system.DepartmentRepo{},
+		Menus:       system.MenuRepo{},
+		IPRules:     system.IPRuleRepo{},
+		RateLimits:  system.RateLimitRepo{},
+	}
+}
@@ -5,5 +5,7 @@ import "errors"
 var (
 	// ErrRoleNotFound 角色不存在
 	ErrRoleNotFound = errors.New("role not found")
+	// ErrPermissionDenied 无权操作其它租户的角色
+	ErrPermissionDenied = errors.New("permission denied")
 )
 
@@ -1,22 +1,34 @@
 package role
 
 import (
+	"context"
 	"errors"
 
 	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/event"
 
 	"gorm.io/gorm"
 )
 
+// Service 角色领域服务
+type Service struct {
+	roles repo.RoleRepo
+}
+
+func NewService(r repo.Repos) *Service {
+	return &Service{roles: r.Roles}
+}
+
 type FindListQuery struct {
 	TenantID uint
 	Name     string
 	Status   uint
 }
 
-func GetRole(id uint) (system.SystemRole, error) {
+func (s *Service) GetRole(ctx context.Context, id uint) (system.SystemRole, error) {
 	role := system.SystemRole{Model: gorm.Model{ID: id}}
-	if err := system.GetRole(&role); err != nil {
+	if err := s.roles.Get(ctx, &role); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return system.SystemRole{}, ErrRoleNotFound
 		}
@@ -25,13 +37,13 @@ func GetRole(id uint) (system.SystemRole, error) {
 	return role, nil
 }
 
-func FindRoleList(query FindListQuery, page, pageSize int) ([]system.SystemRole, int64, error) {
+func (s *Service) FindRoleList(ctx context.Context, query FindListQuery, page, pageSize int) ([]system.SystemRole, int64, error) {
 	filter := system.SystemRole{
 		TenantID: query.TenantID,
 		Name:     query.Name,
 		Status:   query.Status,
 	}
-	return system.FindRoleList(&filter, page, pageSize)
+	return s.roles.List(ctx, &filter, page, pageSize)
 }
 
 type AddInput struct {
@@ -41,14 +53,14 @@ type AddInput struct {
 	Desc     string
 }
 
-func AddRole(input AddInput) (system.SystemRole, error) {
+func (s *Service) AddRole(ctx context.Context, input AddInput) (system.SystemRole, error) {
 	role := system.SystemRole{
 		TenantID: input.TenantID,
 		Name:     input.Name,
 		Status:   input.Status,
 		Desc:     input.Desc,
 	}
-	if err := system.AddRole(&role); err != nil {
+	if err := s.roles.Create(ctx, &role); err != nil {
 		return system.SystemRole{}, err
 	}
 	return role, nil
@@ -56,20 +68,24 @@ func AddRole(input AddInput) (system.SystemRole, error) {
 
 type UpdateInput struct {
 	ID       uint
-	TenantID uint
+	TenantID uint // 目标租户，仅超级管理员可调整；为 0 时保持原租户
 	Name     string
 	Status   uint
 	Desc     string
 }
 
-func UpdateRole(input UpdateInput) (system.SystemRole, error) {
-	existing, err := GetRole(input.ID)
+// UpdateRole 更新角色。非超级管理员只能修改本租户的角色，且不能调整角色归属租户。
+func (s *Service) UpdateRole(ctx context.Context, input UpdateInput, actorTenantID uint, isSuperAdmin bool) (system.SystemRole, error) {
+	existing, err := s.GetRole(ctx, input.ID)
 	if err != nil {
 		return system.SystemRole{}, err
 	}
+	if !canManage(existing, actorTenantID, isSuperAdmin) {
+		return system.SystemRole{}, ErrPermissionDenied
+	}
 
 	targetTenantID := existing.TenantID
-	if input.TenantID != 0 {
+	if isSuperAdmin && input.TenantID != 0 {
 		targetTenantID = input.TenantID
 	}
 
@@ -80,19 +96,32 @@ func UpdateRole(input UpdateInput) (system.SystemRole, error) {
 		Status:   input.Status,
 		Desc:     input.Desc,
 	}
-	if err := system.UpdateRole(&role); err != nil {
+	if err := s.roles.Update(ctx, &role); err != nil {
 		return system.SystemRole{}, err
 	}
+	event.Publish(ctx, event.Event{Type: event.RoleUpdated, TenantID: targetTenantID, RoleID: role.ID})
 	return role, nil
 }
 
-func DeleteRole(id uint) error {
-	role := system.SystemRole{Model: gorm.Model{ID: id}}
-	if err := system.GetRole(&role); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrRoleNotFound
-		}
-		return err
+// DeleteRole 删除角色并返回被删除的角色。非超级管理员只能删除本租户的角色。
+func (s *Service) DeleteRole(ctx context.Context, id uint, actorTenantID uint, isSuperAdmin bool) (system.SystemRole, error) {
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return system.SystemRole{}, err
+	}
+	if !canManage(role, actorTenantID, isSuperAdmin) {
+		return system.SystemRole{}, ErrPermissionDenied
+	}
+	if err := s.roles.Delete(ctx, &role); err != nil {
+		return system.SystemRole{}, err
+	}
+	event.Publish(ctx, event.Event{Type: event.RoleDeleted, TenantID: role.TenantID, RoleID: id})
+	return role, nil
+}
+
+func canManage(role system.SystemRole, actorTenantID uint, isSuperAdmin bool) bool {
+	if isSuperAdmin {
+		return true
 	}
-	return system.DeleteRole(&role)
+	return actorTenantID != 0 && role.TenantID == actorTenantID
 }
@@ -0,0 +1,105 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo/memory"
+)
+
+func newRoles(t *testing.T) (*Service, system.SystemRole, system.SystemRole) {
+	t.Helper()
+	svc := NewService(memory.New().Repos())
+	a, err := svc.AddRole(context.Background(), AddInput{TenantID: 10, Name: "a", Status: system.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := svc.AddRole(context.Background(), AddInput{TenantID: 20, Name: "b", Status: system.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, a, b
+}
+
+func TestUpdateRoleTenantScoping(t *testing.T) {
+	ctx := context.Background()
+	svc, a, b := newRoles(t)
+
+	if _, err := svc.UpdateRole(ctx, UpdateInput{ID: b.ID, Name: "x"}, a.TenantID, false); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("update other tenant role error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := svc.UpdateRole(ctx, UpdateInput{ID: a.ID, Name: "x"}, 0, false); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("update without tenant error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := svc.UpdateRole(ctx, UpdateInput{ID: 999, Name: "x"}, a.TenantID, false); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("update missing role error = %v, want ErrRoleNotFound", err)
+	}
+
+	// 租户管理员传入 tenant_id 也不能调整角色归属
+	updated, err := svc.UpdateRole(ctx, UpdateInput{ID: a.ID, TenantID: b.TenantID, Name: "renamed"}, a.TenantID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.TenantID != a.TenantID {
+		t.Fatalf("tenant admin moved role to tenant %d", updated.TenantID)
+	}
+	got, err := svc.GetRole(ctx, a.ID)
+	if err != nil || got.Name != "renamed" || got.TenantID != a.TenantID {
+		t.Fatalf("GetRole() = %+v, %v", got, err)
+	}
+}
+
+func TestUpdateRoleSuperAdminMovesTenant(t *testing.T) {
+	ctx := context.Background()
+	svc, a, b := newRoles(t)
+
+	if _, err := svc.UpdateRole(ctx, UpdateInput{ID: a.ID, TenantID: b.TenantID, Name: "moved"}, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := svc.GetRole(ctx, a.ID)
+	if got.TenantID != b.TenantID {
+		t.Fatalf("TenantID = %d, want %d", got.TenantID, b.TenantID)
+	}
+
+	// 未指定目标租户时保持原租户
+	if _, err := svc.UpdateRole(ctx, UpdateInput{ID: b.ID, Name: "kept"}, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = svc.GetRole(ctx, b.ID)
+	if got.TenantID != b.TenantID {
+		t.Fatalf("TenantID = %d, want %d", got.TenantID, b.TenantID)
+	}
+}
+
+func TestDeleteRoleTenantScoping(t *testing.T) {
+	ctx := context.Background()
+	svc, a, b := newRoles(t)
+
+	if _, err := svc.DeleteRole(ctx, b.ID, a.TenantID, false); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("delete other tenant role error = %v, want ErrPermissionDenied", err)
+	}
+	deleted, err := svc.DeleteRole(ctx, a.ID, a.TenantID, false)
+	if err != nil || deleted.ID != a.ID {
+		t.Fatalf("DeleteRole() = %+v, %v", deleted, err)
+	}
+	if _, err := svc.GetRole(ctx, a.ID); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("GetRole() after delete error = %v, want ErrRoleNotFound", err)
+	}
+	if _, err := svc.DeleteRole(ctx, b.ID, 0, true); err != nil {
+		t.Fatalf("super admin delete error = %v", err)
+	}
+}
+
+func TestFindRoleListIsTenantScoped(t *testing.T) {
+	svc, a, _ := newRoles(t)
+
+	roles, total, err := svc.FindRoleList(context.Background(), FindListQuery{TenantID: a.TenantID}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(roles) != 1 || roles[0].ID != a.ID {
+		t.Fatalf("FindRoleList() = %+v (total %d), want only role a", roles, total)
+	}
+}
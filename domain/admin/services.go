@@ -0,0 +1,31 @@
+// Package admin 汇总系统管理相关的领域服务，供启动时统一构造并注入到接口层。
+package admin
+
+import (
+	"api-server/domain/admin/department"
+	"api-server/domain/admin/menu"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/role"
+	"api-server/domain/admin/tenant"
+	"api-server/domain/admin/user"
+)
+
+// Services 系统管理领域服务集合
+type Services struct {
+	Users       *user.Service
+	Roles       *role.Service
+	Tenants     *tenant.Service
+	Departments *department.Service
+	Menus       *menu.Service
+}
+
+// NewServices 使用给定仓储构造全部领域服务；menuCache 为 nil 时不缓存用户菜单树
+func NewServices(r repo.Repos, menuCache menu.TreeCache) Services {
+	return Services{
+		Users:       user.NewService(r),
+		Roles:       role.NewService(r),
+		Tenants:     tenant.NewService(r),
+		Departments: department.NewService(r),
+		Menus:       menu.NewService(r, menuCache),
+	}
+}
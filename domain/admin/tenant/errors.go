@@ -5,5 +5,11 @@ import "errors"
 var (
 	// ErrTenantNotFound 租户不存在
 	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrCannotDisablePlatformTenant 不能禁用平台租户
+	ErrCannotDisablePlatformTenant = errors.New("cannot disable platform tenant")
+	// ErrInvalidStatus 状态值无效
+	ErrInvalidStatus = errors.New("invalid status")
+	// ErrInvalidOrigin 跨域来源格式无效
+	ErrInvalidOrigin = errors.New("invalid origin")
 )
 
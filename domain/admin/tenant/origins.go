@@ -0,0 +1,59 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/event"
+	"api-server/util/origin"
+)
+
+// GetAllowedOrigins 返回租户前端允许跨域的来源
+func (s *Service) GetAllowedOrigins(ctx context.Context, id uint) ([]string, error) {
+	tenant := system.SystemTenant{Model: gorm.Model{ID: id}}
+	if err := s.tenants.Get(ctx, &tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return origin.Split(tenant.AllowedOrigins), nil
+}
+
+// SetAllowedOrigins 覆盖租户前端允许跨域的来源（精确来源或 https://*.example.com 通配子域名），
+// 传入空列表表示清空。租户级来源不允许使用 "*"。
+func (s *Service) SetAllowedOrigins(ctx context.Context, id uint, origins []string) error {
+	normalized := make([]string, 0, len(origins))
+	seen := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		o = origin.Normalize(o)
+		if o == origin.Any || strings.Contains(o, ",") {
+			return fmt.Errorf("%w: %q", ErrInvalidOrigin, o)
+		}
+		if err := origin.Validate(o); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOrigin, err)
+		}
+		if !seen[o] {
+			seen[o] = true
+			normalized = append(normalized, o)
+		}
+	}
+
+	tenant := system.SystemTenant{Model: gorm.Model{ID: id}}
+	if err := s.tenants.Get(ctx, &tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTenantNotFound
+		}
+		return err
+	}
+	if err := s.tenants.SetAllowedOrigins(ctx, id, strings.Join(normalized, ",")); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.TenantUpdated, TenantID: id})
+	return nil
+}
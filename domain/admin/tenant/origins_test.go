@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/event"
+)
+
+func TestSetAllowedOrigins(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(event.Reset)
+	svc := NewService(memory.New().Repos())
+	tenant, err := svc.AddTenant(ctx, AddTenantInput{Code: "a", Name: "A", Status: system.StatusEnabled})
+	if err != nil {
+		t.Fatalf("AddTenant() error = %v", err)
+	}
+
+	var published []event.Event
+	event.Subscribe(event.TenantUpdated, func(_ context.Context, e event.Event) { published = append(published, e) })
+
+	err = svc.SetAllowedOrigins(ctx, tenant.ID, []string{"https://A.example.com/", "https://*.tenant-a.com", "https://a.example.com"})
+	if err != nil {
+		t.Fatalf("SetAllowedOrigins() error = %v", err)
+	}
+	got, err := svc.GetAllowedOrigins(ctx, tenant.ID)
+	if err != nil {
+		t.Fatalf("GetAllowedOrigins() error = %v", err)
+	}
+	if want := []string{"https://a.example.com", "https://*.tenant-a.com"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetAllowedOrigins() = %v, want %v", got, want)
+	}
+	if len(published) != 1 || published[0].TenantID != tenant.ID {
+		t.Fatalf("published events = %+v, want one TenantUpdated for tenant %d", published, tenant.ID)
+	}
+
+	for _, bad := range [][]string{{"*"}, {"example.com"}, {"https://a.example.com/path"}} {
+		if err := svc.SetAllowedOrigins(ctx, tenant.ID, bad); !errors.Is(err, ErrInvalidOrigin) {
+			t.Errorf("SetAllowedOrigins(%v) error = %v, want ErrInvalidOrigin", bad, err)
+		}
+	}
+	if err := svc.SetAllowedOrigins(ctx, 999, nil); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("unknown tenant error = %v, want ErrTenantNotFound", err)
+	}
+
+	if err := svc.SetAllowedOrigins(ctx, tenant.ID, nil); err != nil {
+		t.Fatalf("clear origins error = %v", err)
+	}
+	if got, _ := svc.GetAllowedOrigins(ctx, tenant.ID); len(got) != 0 {
+		t.Fatalf("origins after clear = %v, want empty", got)
+	}
+}
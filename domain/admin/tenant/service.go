@@ -1,26 +1,41 @@
 package tenant
 
 import (
+	"context"
 	"errors"
 
 	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/event"
 
 	"gorm.io/gorm"
 )
 
+// PlatformTenantID 平台租户 ID，由初始化数据创建，超级管理员属于该租户
+const PlatformTenantID uint = 1
+
+// Service 租户领域服务
+type Service struct {
+	tenants repo.TenantRepo
+}
+
+func NewService(r repo.Repos) *Service {
+	return &Service{tenants: r.Tenants}
+}
+
 type FindListQuery struct {
 	Code   string
 	Name   string
 	Status uint
 }
 
-func FindTenantList(query FindListQuery, page, pageSize int) ([]system.SystemTenant, int64, error) {
+func (s *Service) FindTenantList(ctx context.Context, query FindListQuery, page, pageSize int) ([]system.SystemTenant, int64, error) {
 	filter := system.SystemTenant{
 		Code:   query.Code,
 		Name:   query.Name,
 		Status: query.Status,
 	}
-	return system.FindTenantList(&filter, page, pageSize)
+	return s.tenants.List(ctx, &filter, page, pageSize)
 }
 
 type AddTenantInput struct {
@@ -32,7 +47,7 @@ type AddTenantInput struct {
 	Status  uint
 }
 
-func AddTenant(input AddTenantInput) (system.SystemTenant, error) {
+func (s *Service) AddTenant(ctx context.Context, input AddTenantInput) (system.SystemTenant, error) {
 	tenant := system.SystemTenant{
 		Code:    input.Code,
 		Name:    input.Name,
@@ -42,7 +57,7 @@ func AddTenant(input AddTenantInput) (system.SystemTenant, error) {
 		Status:  input.Status,
 	}
 
-	if err := system.AddTenant(&tenant); err != nil {
+	if err := s.tenants.Create(ctx, &tenant); err != nil {
 		return system.SystemTenant{}, err
 	}
 	return tenant, nil
@@ -58,7 +73,7 @@ type UpdateTenantInput struct {
 	Status  uint
 }
 
-func UpdateTenant(input UpdateTenantInput) error {
+func (s *Service) UpdateTenant(ctx context.Context, input UpdateTenantInput) error {
 	tenant := system.SystemTenant{
 		Model:   gorm.Model{ID: input.ID},
 		Code:    input.Code,
@@ -69,20 +84,61 @@ func UpdateTenant(input UpdateTenantInput) error {
 		Status:  input.Status,
 	}
 
-	if err := system.UpdateTenant(&tenant); err != nil {
+	if err := s.tenants.Update(ctx, &tenant); err != nil {
 		return err
 	}
+	event.Publish(ctx, event.Event{Type: event.TenantUpdated, TenantID: input.ID})
 	return nil
 }
 
-func DeleteTenant(id uint) error {
+func (s *Service) DeleteTenant(ctx context.Context, id uint) error {
 	tenant := system.SystemTenant{Model: gorm.Model{ID: id}}
-	if err := system.GetTenant(&tenant); err != nil {
+	if err := s.tenants.Get(ctx, &tenant); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrTenantNotFound
 		}
 		return err
 	}
-	return system.DeleteTenant(&tenant)
+	if err := s.tenants.Delete(ctx, &tenant); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.TenantDeleted, TenantID: id})
+	return nil
+}
+
+// FindTenantByCode 按租户代码查询租户
+func (s *Service) FindTenantByCode(ctx context.Context, code string) (system.SystemTenant, error) {
+	if code == "" {
+		return system.SystemTenant{}, ErrTenantNotFound
+	}
+	tenant := system.SystemTenant{Code: code}
+	if err := s.tenants.Get(ctx, &tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return system.SystemTenant{}, ErrTenantNotFound
+		}
+		return system.SystemTenant{}, err
+	}
+	return tenant, nil
 }
 
+// SetTenantStatus 启用或禁用租户；禁用后该租户下的用户无法登录。平台租户（超级管理员所在租户）不能被禁用
+func (s *Service) SetTenantStatus(ctx context.Context, id, status uint) error {
+	if status != system.StatusEnabled && status != system.StatusDisabled {
+		return ErrInvalidStatus
+	}
+	if id == PlatformTenantID && status == system.StatusDisabled {
+		return ErrCannotDisablePlatformTenant
+	}
+	tenant := system.SystemTenant{Model: gorm.Model{ID: id}}
+	if err := s.tenants.Get(ctx, &tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTenantNotFound
+		}
+		return err
+	}
+	if err := s.tenants.Update(ctx, &system.SystemTenant{Model: gorm.Model{ID: id}, Status: status}); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.TenantUpdated, TenantID: id})
+	return nil
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/event"
+
+	"gorm.io/gorm"
+)
+
+// 以下为运维操作，供命令行子命令使用（如唯一的平台管理员忘记密码时重置），不经过接口层的权限校验。
+
+// FindUserByAccount 按租户与登录账号查询用户
+func (s *Service) FindUserByAccount(ctx context.Context, tenantID uint, account string) (system.SystemUser, error) {
+	if account == "" {
+		return system.SystemUser{}, ErrUserNotFound
+	}
+	u := system.SystemUser{TenantID: tenantID, Account: account}
+	if err := s.users.Get(ctx, &u); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return system.SystemUser{}, ErrUserNotFound
+		}
+		return system.SystemUser{}, err
+	}
+	return u, nil
+}
+
+// ResetPassword 重置用户密码
+func (s *Service) ResetPassword(ctx context.Context, tenantID, id uint, password string) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	if _, err := s.getTenantUser(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.users.Update(ctx, &system.SystemUser{Model: gorm.Model{ID: id}, Password: password}); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.UserUpdated, TenantID: tenantID, UserID: id})
+	return nil
+}
+
+// SetUserStatus 启用或禁用用户；禁用后用户无法登录。超级管理员不能被禁用
+func (s *Service) SetUserStatus(ctx context.Context, tenantID, id, status uint) error {
+	if status != system.StatusEnabled && status != system.StatusDisabled {
+		return ErrInvalidStatus
+	}
+	if id == 1 && status == system.StatusDisabled {
+		return ErrCannotDisableSuperAdmin
+	}
+	if _, err := s.getTenantUser(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.users.Update(ctx, &system.SystemUser{Model: gorm.Model{ID: id}, Status: status}); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.UserUpdated, TenantID: tenantID, UserID: id})
+	return nil
+}
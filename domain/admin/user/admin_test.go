@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"api-server/db/pgdb/system"
+)
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, _, f := newFixture(t)
+
+	if err := svc.ResetPassword(ctx, f.tenantA, f.userB, "new-secret"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("cross-tenant ResetPassword() error = %v, want ErrUserNotFound", err)
+	}
+	if err := svc.ResetPassword(ctx, f.tenantA, f.userA, ""); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("empty password error = %v, want ErrPasswordRequired", err)
+	}
+	if err := svc.ResetPassword(ctx, f.tenantA, f.userA, "new-secret"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "new-secret"}); err != nil {
+		t.Fatalf("login with new password error = %v", err)
+	}
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "secret"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("login with old password error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestSetUserStatus(t *testing.T) {
+	ctx := context.Background()
+	svc, _, f := newFixture(t)
+
+	u, err := svc.FindUserByAccount(ctx, f.tenantA, "user-a")
+	if err != nil || u.ID != f.userA {
+		t.Fatalf("FindUserByAccount() = %+v, %v", u, err)
+	}
+	if _, err := svc.FindUserByAccount(ctx, f.tenantA, "user-b"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("cross-tenant FindUserByAccount() error = %v, want ErrUserNotFound", err)
+	}
+
+	if err := svc.SetUserStatus(ctx, f.tenantA, f.userA, system.StatusDisabled); err != nil {
+		t.Fatalf("disable error = %v", err)
+	}
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "secret"}); !errors.Is(err, ErrUserDisabled) {
+		t.Fatalf("login after disable error = %v, want ErrUserDisabled", err)
+	}
+	if err := svc.SetUserStatus(ctx, f.tenantA, f.userA, system.StatusEnabled); err != nil {
+		t.Fatalf("enable error = %v", err)
+	}
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "secret"}); err != nil {
+		t.Fatalf("login after enable error = %v", err)
+	}
+
+	if err := svc.SetUserStatus(ctx, f.tenantA, 1, system.StatusDisabled); !errors.Is(err, ErrCannotDisableSuperAdmin) {
+		t.Fatalf("disable super admin error = %v, want ErrCannotDisableSuperAdmin", err)
+	}
+	if err := svc.SetUserStatus(ctx, f.tenantA, f.userA, 0); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("invalid status error = %v, want ErrInvalidStatus", err)
+	}
+}
@@ -1,9 +1,14 @@
 package user
 
 import (
-	"strings"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	systemuser "api-server/db/rdb/systemUser"
+	"api-server/domain/event"
 )
 
 type CacheFilter struct {
@@ -11,9 +16,13 @@ type CacheFilter struct {
 	Name     string
 }
 
-func GetUserFromCache(id uint) (systemuser.UserCacheInfo, error) {
-	item, err := systemuser.GetUserFromCache(id)
+// GetUserFromCache 从缓存读取当前租户下的用户；其它租户的用户视为不存在
+func GetUserFromCache(ctx context.Context, tenantID, id uint) (systemuser.UserCacheInfo, error) {
+	item, err := systemuser.GetUserFromCache(ctx, tenantID, id)
 	if err != nil {
+		if errors.Is(err, systemuser.ErrUserNotInTenant) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return systemuser.UserCacheInfo{}, ErrUserNotFound
+		}
 		return systemuser.UserCacheInfo{}, err
 	}
 	if item == nil {
@@ -22,41 +31,39 @@ func GetUserFromCache(id uint) (systemuser.UserCacheInfo, error) {
 	return *item, nil
 }
 
-func ListUsersFromCache(filter CacheFilter, page, pageSize int) ([]systemuser.UserCacheInfo, int, error) {
-	userList, err := systemuser.GetAllUsersFromCache()
-	if err != nil {
-		return nil, 0, err
-	}
+// ListUsersFromCache 分页查询当前租户的用户缓存，昵称/姓名模糊匹配在 Redis 端完成
+func ListUsersFromCache(ctx context.Context, tenantID uint, filter CacheFilter, page, pageSize int) ([]systemuser.UserCacheInfo, int, error) {
+	return systemuser.ListUsersFromCache(ctx, tenantID, systemuser.CacheFilter{
+		Username: filter.Username,
+		Name:     filter.Name,
+	}, page, pageSize)
+}
 
-	var filteredList []systemuser.UserCacheInfo
-	if filter.Username != "" || filter.Name != "" {
-		filteredList = make([]systemuser.UserCacheInfo, 0, len(userList))
-		for _, user := range userList {
-			if filter.Username != "" && !strings.Contains(user.Username, filter.Username) {
-				continue
-			}
-			if filter.Name != "" && !strings.Contains(user.Name, filter.Name) {
-				continue
-			}
-			filteredList = append(filteredList, user)
+// RegisterCacheHandlers 订阅用户/角色变更事件，增量维护按租户分区的用户缓存。
+// 缓存更新失败只记录日志，由定时全量对账兜底。
+func RegisterCacheHandlers() {
+	refresh := func(ctx context.Context, e event.Event) {
+		if err := systemuser.RefreshUser(ctx, e.TenantID, e.UserID); err != nil {
+			zap.L().Warn("增量更新用户缓存失败", zap.String("event", string(e.Type)), zap.Uint("user_id", e.UserID), zap.Error(err))
 		}
-	} else {
-		filteredList = userList
 	}
+	event.Subscribe(event.UserCreated, refresh)
+	event.Subscribe(event.UserUpdated, refresh)
 
-	total := len(filteredList)
-	if total == 0 {
-		return []systemuser.UserCacheInfo{}, 0, nil
-	}
+	event.Subscribe(event.UserDeleted, func(ctx context.Context, e event.Event) {
+		if e.TenantID == 0 {
+			return
+		}
+		if err := systemuser.EvictUser(ctx, e.TenantID, e.UserID); err != nil {
+			zap.L().Warn("移除用户缓存失败", zap.Uint("user_id", e.UserID), zap.Error(err))
+		}
+	})
 
-	start := (page - 1) * pageSize
-	if start >= total {
-		return []systemuser.UserCacheInfo{}, total, nil
-	}
-	end := start + pageSize
-	if end > total {
-		end = total
+	refreshRole := func(ctx context.Context, e event.Event) {
+		if err := systemuser.RefreshUsersByRole(ctx, e.RoleID); err != nil {
+			zap.L().Warn("更新角色下用户缓存失败", zap.String("event", string(e.Type)), zap.Uint("role_id", e.RoleID), zap.Error(err))
+		}
 	}
-
-	return filteredList[start:end], total, nil
+	event.Subscribe(event.RoleUpdated, refreshRole)
+	event.Subscribe(event.RoleDeleted, refreshRole)
 }
@@ -13,6 +13,12 @@ var (
 	ErrRoleNotInTenant = errors.New("role not in tenant")
 	// ErrCannotDeleteSuperAdmin 不能删除超级管理员
 	ErrCannotDeleteSuperAdmin = errors.New("cannot delete super admin")
+	// ErrCannotDisableSuperAdmin 不能禁用超级管理员
+	ErrCannotDisableSuperAdmin = errors.New("cannot disable super admin")
+	// ErrPasswordRequired 密码不能为空
+	ErrPasswordRequired = errors.New("password required")
+	// ErrInvalidStatus 状态值无效
+	ErrInvalidStatus = errors.New("invalid status")
 	// ErrTenantQueryTooShort 登录页租户搜索输入过短
 	ErrTenantQueryTooShort = errors.New("tenant query too short")
 )
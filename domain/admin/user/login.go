@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"unicode/utf8"
 
 	"api-server/config"
@@ -13,8 +14,8 @@ type LoginInput struct {
 	Password   string
 }
 
-func VerifyLogin(input LoginInput) (system.SystemUser, system.SystemTenant, error) {
-	user, tenant, err := system.VerifyUser(input.TenantCode, input.Account, input.Password)
+func (s *Service) VerifyLogin(ctx context.Context, input LoginInput) (system.SystemUser, system.SystemTenant, error) {
+	user, tenant, err := s.users.Verify(ctx, input.TenantCode, input.Account, input.Password)
 	if err != nil {
 		return system.SystemUser{}, system.SystemTenant{}, err
 	}
@@ -27,8 +28,8 @@ func VerifyLogin(input LoginInput) (system.SystemUser, system.SystemTenant, erro
 	return user, tenant, nil
 }
 
-func CreateLoginLog(item *system.SystemUserLoginLog) error {
-	return system.CreateLoginLog(item)
+func (s *Service) CreateLoginLog(ctx context.Context, item *system.SystemUserLoginLog) error {
+	return s.loginLogs.Create(ctx, item)
 }
 
 type LoginLogInput struct {
@@ -38,7 +39,7 @@ type LoginLogInput struct {
 	LoginStatus string
 }
 
-func CreateLoginLogFromInput(input LoginLogInput) error {
+func (s *Service) CreateLoginLogFromInput(ctx context.Context, input LoginLogInput) error {
 	log := system.SystemUserLoginLog{
 		TenantCode:  input.TenantCode,
 		UserName:    input.UserName,
@@ -46,7 +47,7 @@ func CreateLoginLogFromInput(input LoginLogInput) error {
 		IP:          input.IP,
 		LoginStatus: input.LoginStatus,
 	}
-	return CreateLoginLog(&log)
+	return s.CreateLoginLog(ctx, &log)
 }
 
 type FindLoginLogQuery struct {
@@ -54,12 +55,12 @@ type FindLoginLogQuery struct {
 	Username string
 }
 
-func FindLoginLogList(query FindLoginLogQuery, page, pageSize int) ([]system.SystemUserLoginLog, int64, error) {
+func (s *Service) FindLoginLogList(ctx context.Context, query FindLoginLogQuery, page, pageSize int) ([]system.SystemUserLoginLog, int64, error) {
 	filter := system.SystemUserLoginLog{
 		IP:       query.IP,
 		UserName: query.Username,
 	}
-	return system.FindLoginLogList(&filter, page, pageSize)
+	return s.loginLogs.List(ctx, &filter, page, pageSize)
 }
 
 type TenantSuggestion struct {
@@ -68,12 +69,12 @@ type TenantSuggestion struct {
 	Name string `json:"name"`
 }
 
-func SuggestTenantForLogin(code string, limit int) ([]TenantSuggestion, error) {
-	if utf8.RuneCountInString(code) < config.TenantMinQueryLength {
+func (s *Service) SuggestTenantForLogin(ctx context.Context, code string, limit int) ([]TenantSuggestion, error) {
+	if utf8.RuneCountInString(code) < config.Current().TenantMinQueryLength {
 		return nil, ErrTenantQueryTooShort
 	}
 
-	tenants, err := system.SuggestTenantByCode(code, limit)
+	tenants, err := s.tenants.SuggestByCode(ctx, code, limit)
 	if err != nil {
 		return nil, err
 	}
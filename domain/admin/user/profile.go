@@ -1,9 +1,11 @@
 package user
 
 import (
+	"context"
 	"errors"
 
 	"api-server/db/pgdb/system"
+	"api-server/domain/event"
 
 	"gorm.io/gorm"
 )
@@ -16,7 +18,7 @@ type UpdateProfileInput struct {
 	Gender   uint
 }
 
-func UpdateUserProfile(input UpdateProfileInput) error {
+func (s *Service) UpdateUserProfile(ctx context.Context, input UpdateProfileInput) error {
 	u := system.SystemUser{
 		Model:    gorm.Model{ID: input.UserID},
 		Username: input.Username,
@@ -26,12 +28,16 @@ func UpdateUserProfile(input UpdateProfileInput) error {
 	if input.Password != "" {
 		u.Password = input.Password
 	}
-	return system.UpdateUser(&u)
+	if err := s.users.Update(ctx, &u); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.UserUpdated, UserID: input.UserID})
+	return nil
 }
 
-func GetUserProfile(userID uint) (system.SystemUser, error) {
+func (s *Service) GetUserProfile(ctx context.Context, userID uint) (system.SystemUser, error) {
 	user := system.SystemUser{Model: gorm.Model{ID: userID}}
-	if err := system.GetUser(&user); err != nil {
+	if err := s.users.Get(ctx, &user); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return system.SystemUser{}, ErrUserNotFound
 		}
@@ -0,0 +1,20 @@
+package user
+
+import "api-server/domain/admin/repo"
+
+// Service 用户领域服务，数据访问通过构造时注入的仓储完成
+type Service struct {
+	users     repo.UserRepo
+	loginLogs repo.LoginLogRepo
+	roles     repo.RoleRepo
+	tenants   repo.TenantRepo
+}
+
+func NewService(r repo.Repos) *Service {
+	return &Service{
+		users:     r.Users,
+		loginLogs: r.LoginLogs,
+		roles:     r.Roles,
+		tenants:   r.Tenants,
+	}
+}
@@ -1,9 +1,11 @@
 package user
 
 import (
+	"context"
 	"errors"
 
 	"api-server/db/pgdb/system"
+	"api-server/domain/event"
 
 	"gorm.io/gorm"
 )
@@ -16,7 +18,7 @@ type FindUserQuery struct {
 	RoleID       uint
 }
 
-func FindUserList(tenantID uint, query FindUserQuery, page, pageSize int) ([]system.UserWithRelations, int64, error) {
+func (s *Service) FindUserList(ctx context.Context, tenantID uint, query FindUserQuery, page, pageSize int) ([]system.UserWithRelations, int64, error) {
 	filter := system.SystemUser{
 		TenantID:     tenantID,
 		Username:     query.Username,
@@ -25,7 +27,7 @@ func FindUserList(tenantID uint, query FindUserQuery, page, pageSize int) ([]sys
 		DepartmentID: query.DepartmentID,
 		RoleID:       query.RoleID,
 	}
-	return system.FindUserList(&filter, page, pageSize)
+	return s.users.List(ctx, &filter, page, pageSize)
 }
 
 type AddUserInput struct {
@@ -40,9 +42,9 @@ type AddUserInput struct {
 	DepartmentID uint
 }
 
-func AddUser(tenantID uint, input AddUserInput) error {
+func (s *Service) AddUser(ctx context.Context, tenantID uint, input AddUserInput) error {
 	roleEntity := system.SystemRole{Model: gorm.Model{ID: input.RoleID}}
-	if err := system.GetRole(&roleEntity); err != nil || roleEntity.TenantID != tenantID {
+	if err := s.roles.Get(ctx, &roleEntity); err != nil || roleEntity.TenantID != tenantID {
 		return ErrRoleNotInTenant
 	}
 
@@ -58,7 +60,11 @@ func AddUser(tenantID uint, input AddUserInput) error {
 		RoleID:       input.RoleID,
 		DepartmentID: input.DepartmentID,
 	}
-	return system.AddUser(&u)
+	if err := s.users.Create(ctx, &u); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.UserCreated, TenantID: tenantID, UserID: u.ID})
+	return nil
 }
 
 type UpdateUserInput struct {
@@ -74,9 +80,12 @@ type UpdateUserInput struct {
 	DepartmentID uint
 }
 
-func UpdateUser(tenantID uint, input UpdateUserInput) error {
+func (s *Service) UpdateUser(ctx context.Context, tenantID uint, input UpdateUserInput) error {
+	if _, err := s.getTenantUser(ctx, tenantID, input.ID); err != nil {
+		return err
+	}
 	roleEntity := system.SystemRole{Model: gorm.Model{ID: input.RoleID}}
-	if err := system.GetRole(&roleEntity); err != nil || roleEntity.TenantID != tenantID {
+	if err := s.roles.Get(ctx, &roleEntity); err != nil || roleEntity.TenantID != tenantID {
 		return ErrRoleNotInTenant
 	}
 
@@ -95,22 +104,43 @@ func UpdateUser(tenantID uint, input UpdateUserInput) error {
 	if input.Password != "" {
 		u.Password = input.Password
 	}
-	return system.UpdateUser(&u)
+	if err := s.users.Update(ctx, &u); err != nil {
+		return err
+	}
+	event.Publish(ctx, event.Event{Type: event.UserUpdated, TenantID: tenantID, UserID: input.ID})
+	return nil
 }
 
-func DeleteUser(id uint) error {
+func (s *Service) DeleteUser(ctx context.Context, tenantID, id uint) error {
 	if id == 1 {
 		return ErrCannotDeleteSuperAdmin
 	}
-	u := system.SystemUser{
-		Model: gorm.Model{ID: id},
+	existing, err := s.getTenantUser(ctx, tenantID, id)
+	if err != nil {
+		return err
 	}
-	if err := system.DeleteUser(&u); err != nil {
+	if err := s.users.Delete(ctx, &existing); err != nil {
 		return err
 	}
+	event.Publish(ctx, event.Event{Type: event.UserDeleted, TenantID: existing.TenantID, UserID: id})
 	return nil
 }
 
+// getTenantUser 查询当前租户下的用户；其它租户的用户视为不存在，避免跨租户修改或删除
+func (s *Service) getTenantUser(ctx context.Context, tenantID, id uint) (system.SystemUser, error) {
+	u := system.SystemUser{Model: gorm.Model{ID: id}}
+	if err := s.users.Get(ctx, &u); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return system.SystemUser{}, ErrUserNotFound
+		}
+		return system.SystemUser{}, err
+	}
+	if u.TenantID != tenantID {
+		return system.SystemUser{}, ErrUserNotFound
+	}
+	return u, nil
+}
+
 func IsRoleNotFound(err error) bool {
 	return errors.Is(err, gorm.ErrRecordNotFound)
 }
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo/memory"
+)
+
+// fixture 两个租户各有一个角色和一个用户
+type fixture struct {
+	tenantA, tenantB uint
+	roleA, roleB     uint
+	userA, userB     uint
+}
+
+func newFixture(t *testing.T) (*Service, *memory.Store, fixture) {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.New()
+	r := store.Repos()
+
+	var f fixture
+	for _, item := range []struct {
+		code   string
+		tenant *uint
+		role   *uint
+		user   *uint
+	}{
+		{"a", &f.tenantA, &f.roleA, &f.userA},
+		{"b", &f.tenantB, &f.roleB, &f.userB},
+	} {
+		tenant := system.SystemTenant{Code: item.code, Name: item.code, Status: system.StatusEnabled}
+		if err := r.Tenants.Create(ctx, &tenant); err != nil {
+			t.Fatal(err)
+		}
+		role := system.SystemRole{TenantID: tenant.ID, Name: "admin", Status: system.StatusEnabled}
+		if err := r.Roles.Create(ctx, &role); err != nil {
+			t.Fatal(err)
+		}
+		u := system.SystemUser{TenantID: tenant.ID, RoleID: role.ID, Account: "user-" + item.code, Name: "user " + item.code, Password: "secret", Status: system.StatusEnabled}
+		if err := r.Users.Create(ctx, &u); err != nil {
+			t.Fatal(err)
+		}
+		*item.tenant, *item.role, *item.user = tenant.ID, role.ID, u.ID
+	}
+	return NewService(r), store, f
+}
+
+func TestUpdateUserRejectsOtherTenant(t *testing.T) {
+	ctx := context.Background()
+	svc, store, f := newFixture(t)
+
+	// 租户 A 的管理员尝试修改租户 B 的用户，并使用自己租户的角色把该用户“拉”到租户 A
+	err := svc.UpdateUser(ctx, f.tenantA, UpdateUserInput{ID: f.userB, Name: "hijacked", RoleID: f.roleA})
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("UpdateUser() error = %v, want ErrUserNotFound", err)
+	}
+
+	got := system.SystemUser{}
+	got.ID = f.userB
+	if err := store.Repos().Users.Get(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.TenantID != f.tenantB || got.Name != "user b" {
+		t.Fatalf("user B changed: tenant=%d name=%q", got.TenantID, got.Name)
+	}
+}
+
+func TestUpdateUserRequiresRoleInTenant(t *testing.T) {
+	svc, _, f := newFixture(t)
+
+	err := svc.UpdateUser(context.Background(), f.tenantA, UpdateUserInput{ID: f.userA, Name: "x", RoleID: f.roleB})
+	if !errors.Is(err, ErrRoleNotInTenant) {
+		t.Fatalf("UpdateUser() error = %v, want ErrRoleNotInTenant", err)
+	}
+}
+
+func TestUpdateUserSameTenant(t *testing.T) {
+	ctx := context.Background()
+	svc, store, f := newFixture(t)
+
+	if err := svc.UpdateUser(ctx, f.tenantA, UpdateUserInput{ID: f.userA, Name: "renamed", RoleID: f.roleA}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	got := system.SystemUser{}
+	got.ID = f.userA
+	if err := store.Repos().Users.Get(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "renamed" || got.Account != "user-a" {
+		t.Fatalf("user A = %+v, want name renamed and account unchanged", got)
+	}
+}
+
+func TestDeleteUserRejectsOtherTenant(t *testing.T) {
+	ctx := context.Background()
+	svc, store, f := newFixture(t)
+
+	if err := svc.DeleteUser(ctx, f.tenantA, f.userB); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("DeleteUser() error = %v, want ErrUserNotFound", err)
+	}
+	got := system.SystemUser{}
+	got.ID = f.userB
+	if err := store.Repos().Users.Get(ctx, &got); err != nil {
+		t.Fatalf("user B should still exist: %v", err)
+	}
+
+	if err := svc.DeleteUser(ctx, f.tenantB, f.userB); err != nil {
+		t.Fatalf("DeleteUser() same tenant error = %v", err)
+	}
+	if err := store.Repos().Users.Get(ctx, &got); err == nil {
+		t.Fatal("user B should be deleted")
+	}
+}
+
+func TestDeleteSuperAdmin(t *testing.T) {
+	svc, _, f := newFixture(t)
+	if err := svc.DeleteUser(context.Background(), f.tenantA, 1); !errors.Is(err, ErrCannotDeleteSuperAdmin) {
+		t.Fatalf("DeleteUser(1) error = %v, want ErrCannotDeleteSuperAdmin", err)
+	}
+}
+
+func TestFindUserListIsTenantScoped(t *testing.T) {
+	svc, _, f := newFixture(t)
+
+	users, total, err := svc.FindUserList(context.Background(), f.tenantA, FindUserQuery{}, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(users) != 1 || users[0].ID != f.userA {
+		t.Fatalf("FindUserList() = %d users (total %d), want only user A", len(users), total)
+	}
+	if users[0].RoleName != "admin" {
+		t.Fatalf("RoleName = %q, want admin", users[0].RoleName)
+	}
+}
+
+func TestVerifyLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newFixture(t)
+
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "wrong"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+	// 其它租户的账号不能通过当前租户登录
+	if _, _, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-b", Password: "secret"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("cross-tenant account error = %v, want ErrInvalidCredentials", err)
+	}
+	u, tenant, err := svc.VerifyLogin(ctx, LoginInput{TenantCode: "a", Account: "user-a", Password: "secret"})
+	if err != nil || u.Account != "user-a" || tenant.Code != "a" {
+		t.Fatalf("VerifyLogin() = %+v, %+v, %v", u, tenant, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Package cors 维护租户级跨域来源。
+// 每个租户的前端可以部署在独立域名上，来源存于 SystemTenant.AllowedOrigins，
+// 与配置文件 cors.allow_origins 合并生效。预检请求不携带凭据、无法确定租户，
+// 因此任一启用租户登记的来源都会被放行，具体的数据隔离仍由鉴权保证。
+package cors
+
+import (
+	"context"
+	"sync/atomic"
+
+	"api-server/domain/admin/repo"
+	"api-server/domain/event"
+	"api-server/util/origin"
+	"api-server/util/snapshot"
+)
+
+// origins 各启用租户登记的来源，按租户 ID 分组；Init 之前为 nil，此时不放行任何租户来源
+var origins atomic.Pointer[snapshot.Snapshot[map[uint][]string]]
+
+// Init 指定读取租户来源的仓储
+func Init(r repo.Repos) {
+	tenants := r.Tenants
+	origins.Store(snapshot.New("租户跨域来源", func(ctx context.Context) (map[uint][]string, error) {
+		items, err := tenants.AllowedOrigins(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[uint][]string, len(items))
+		for _, t := range items {
+			result[t.ID] = origin.Split(t.AllowedOrigins)
+		}
+		return result, nil
+	}))
+}
+
+// TenantOriginAllowed 判断来源是否登记在任一启用租户下；缓存过期时在后台刷新，不阻塞当前请求
+func TenantOriginAllowed(o string) bool {
+	s := origins.Load()
+	if s == nil {
+		return false
+	}
+	for _, list := range s.Get() {
+		if origin.MatchAny(list, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload 立即重新加载租户来源；失败时保留旧数据
+func Reload(ctx context.Context) error {
+	if s := origins.Load(); s != nil {
+		return s.Reload(ctx)
+	}
+	return nil
+}
+
+// RegisterHandlers 订阅租户变更事件，当前实例立即刷新租户来源（其它实例按刷新间隔同步）
+func RegisterHandlers() {
+	reload := func(ctx context.Context, e event.Event) {
+		_ = Reload(ctx)
+	}
+	event.Subscribe(event.TenantUpdated, reload)
+	event.Subscribe(event.TenantDeleted, reload)
+}
@@ -0,0 +1,33 @@
+package diagnostics
+
+import "runtime"
+
+// BuildInfo 构建版本信息
+type BuildInfo struct {
+	Version   string
+	BuildTime string
+	GitCommit string
+	GoVersion string
+}
+
+var buildInfo = BuildInfo{
+	Version:   "dev",
+	BuildTime: "unknown",
+	GitCommit: "unknown",
+}
+
+// SetBuildInfo 记录构建版本信息（由 main 在启动时注入 -ldflags 写入的值）
+func SetBuildInfo(version, buildTime, gitCommit string) {
+	buildInfo = BuildInfo{
+		Version:   version,
+		BuildTime: buildTime,
+		GitCommit: gitCommit,
+	}
+}
+
+// GetBuildInfo 返回构建版本信息
+func GetBuildInfo() BuildInfo {
+	info := buildInfo
+	info.GoVersion = runtime.Version()
+	return info
+}
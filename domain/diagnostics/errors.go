@@ -0,0 +1,16 @@
+package diagnostics
+
+import "errors"
+
+var (
+	// ErrDatabaseUnavailable 数据库连接池不可用
+	ErrDatabaseUnavailable = errors.New("database unavailable")
+	// ErrRedisUnavailable Redis 连接池不可用
+	ErrRedisUnavailable = errors.New("redis unavailable")
+	// ErrUnknownLogger 不支持动态调整级别的 logger 名称
+	ErrUnknownLogger = errors.New("unknown logger")
+	// ErrInvalidLogLevel 日志级别无效
+	ErrInvalidLogLevel = errors.New("invalid log level")
+	// ErrInvalidOverrideDuration 临时调整时长超出范围
+	ErrInvalidOverrideDuration = errors.New("invalid override duration")
+)
@@ -0,0 +1,39 @@
+package diagnostics
+
+import (
+	"time"
+
+	"api-server/util/log"
+)
+
+// MaxLogLevelOverride 临时调整日志级别的最长时长，避免忘记恢复导致长期输出 Debug 日志
+const MaxLogLevelOverride = 24 * time.Hour
+
+// ListLogLevels 返回各具名 logger 的级别状态
+func ListLogLevels() []log.LevelStatus {
+	return log.ListLevels()
+}
+
+// SetLogLevel 临时调整某个 logger 的级别，minutes 分钟后自动恢复为配置级别
+func SetLogLevel(name, level string, minutes int) (log.LevelStatus, error) {
+	if _, ok := log.AtomicLevel(name); !ok {
+		return log.LevelStatus{}, ErrUnknownLogger
+	}
+	parsed, err := log.ParseLevel(level)
+	if err != nil {
+		return log.LevelStatus{}, ErrInvalidLogLevel
+	}
+	duration := time.Duration(minutes) * time.Minute
+	if duration <= 0 || duration > MaxLogLevelOverride {
+		return log.LevelStatus{}, ErrInvalidOverrideDuration
+	}
+	return log.SetLevelTemporarily(name, parsed, duration)
+}
+
+// ResetLogLevel 立即撤销临时调整
+func ResetLogLevel(name string) (log.LevelStatus, error) {
+	if _, ok := log.AtomicLevel(name); !ok {
+		return log.LevelStatus{}, ErrUnknownLogger
+	}
+	return log.ResetLevel(name)
+}
@@ -0,0 +1,108 @@
+package diagnostics
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"api-server/config"
+	"api-server/cron"
+	"api-server/db/pgdb"
+	"api-server/db/rdb"
+)
+
+var startTime = time.Now()
+
+// ProcessInfo 进程运行状态
+type ProcessInfo struct {
+	Goroutines int
+	NumCPU     int
+	GOMAXPROCS int
+	Uptime     time.Duration
+	Memory     MemoryInfo
+}
+
+// MemoryInfo 内存与 GC（垃圾回收）统计
+type MemoryInfo struct {
+	Alloc        uint64
+	TotalAlloc   uint64
+	Sys          uint64
+	HeapAlloc    uint64
+	HeapInuse    uint64
+	HeapObjects  uint64
+	StackInuse   uint64
+	NumGC        uint32
+	PauseTotal   time.Duration
+	LastGC       time.Time
+	NextGCTarget uint64
+}
+
+// GetConfig 返回当前生效配置（敏感项已脱敏）
+func GetConfig() map[string]interface{} {
+	return config.MaskedSettings()
+}
+
+// PendingRestart 返回已修改但需重启服务才能生效的配置键
+func PendingRestart() []string {
+	return config.PendingRestart()
+}
+
+// GetDatabaseStats 返回 Postgres 连接池统计
+func GetDatabaseStats() (sql.DBStats, error) {
+	stats, err := pgdb.Stats()
+	if err != nil {
+		return sql.DBStats{}, fmt.Errorf("%w: %v", ErrDatabaseUnavailable, err)
+	}
+	return stats, nil
+}
+
+// GetRedisStats 返回 Redis 连接池统计
+func GetRedisStats() (redis.PoolStats, error) {
+	client := rdb.GetClient()
+	if client == nil {
+		return redis.PoolStats{}, ErrRedisUnavailable
+	}
+	stats := client.PoolStats()
+	if stats == nil {
+		return redis.PoolStats{}, ErrRedisUnavailable
+	}
+	return *stats, nil
+}
+
+// GetProcessInfo 返回 goroutine 数量与内存统计
+func GetProcessInfo() ProcessInfo {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	memory := MemoryInfo{
+		Alloc:        m.Alloc,
+		TotalAlloc:   m.TotalAlloc,
+		Sys:          m.Sys,
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		StackInuse:   m.StackInuse,
+		NumGC:        m.NumGC,
+		PauseTotal:   time.Duration(m.PauseTotalNs),
+		NextGCTarget: m.NextGC,
+	}
+	if m.LastGC > 0 {
+		memory.LastGC = time.Unix(0, int64(m.LastGC))
+	}
+
+	return ProcessInfo{
+		Goroutines: runtime.NumGoroutine(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Uptime:     time.Since(startTime),
+		Memory:     memory,
+	}
+}
+
+// ListCronJobs 返回已注册的定时任务
+func ListCronJobs() []cron.JobInfo {
+	return cron.ListJobs()
+}
@@ -0,0 +1,84 @@
+// Package event 提供进程内的领域事件发布/订阅，用于在数据变更后同步维护缓存等派生数据。
+package event
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Type 领域事件类型
+type Type string
+
+const (
+	UserCreated Type = "user.created"
+	UserUpdated Type = "user.updated"
+	UserDeleted Type = "user.deleted"
+	RoleUpdated Type = "role.updated"
+	RoleDeleted Type = "role.deleted"
+
+	// TenantUpdated 租户信息、状态或跨域来源变更；TenantDeleted 租户删除
+	TenantUpdated Type = "tenant.updated"
+	TenantDeleted Type = "tenant.deleted"
+)
+
+// Event 领域事件，仅携带受影响实体的标识，订阅方按需回查最新数据
+type Event struct {
+	Type     Type
+	TenantID uint
+	UserID   uint
+	RoleID   uint
+}
+
+// Handler 事件处理函数；处理失败应自行记录日志，不影响发布方
+type Handler func(ctx context.Context, e Event)
+
+var (
+	mu       sync.RWMutex
+	handlers = map[Type][]Handler{}
+)
+
+// Subscribe 订阅指定类型的事件
+func Subscribe(t Type, h Handler) {
+	if h == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[t] = append(handlers[t], h)
+}
+
+// Publish 同步通知所有订阅方。
+// 在数据库写入成功后调用，保证后续读取（如缓存）能立即看到变更；单个订阅方 panic 不影响其它订阅方。
+func Publish(ctx context.Context, e Event) {
+	// 事件在数据已提交后发布，处理器不应因请求取消而中断（保留 trace_id 等上下文值）
+	ctx = context.WithoutCancel(ctx)
+
+	mu.RLock()
+	subs := append([]Handler(nil), handlers[e.Type]...)
+	mu.RUnlock()
+
+	for _, h := range subs {
+		dispatch(ctx, h, e)
+	}
+}
+
+func dispatch(ctx context.Context, h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			zap.L().Error("领域事件处理异常",
+				zap.String("event", string(e.Type)),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+	h(ctx, e)
+}
+
+// Reset 清空所有订阅（仅用于测试）
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = map[Type][]Handler{}
+}
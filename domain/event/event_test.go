@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPublishDispatchesBySubscribedType(t *testing.T) {
+	Reset()
+	t.Cleanup(Reset)
+
+	var got []Event
+	Subscribe(UserUpdated, func(ctx context.Context, e Event) {
+		got = append(got, e)
+	})
+	Subscribe(UserUpdated, func(ctx context.Context, e Event) {
+		panic("boom")
+	})
+	Subscribe(UserUpdated, func(ctx context.Context, e Event) {
+		got = append(got, e)
+	})
+
+	Publish(context.Background(), Event{Type: UserDeleted, UserID: 1})
+	Publish(context.Background(), Event{Type: UserUpdated, UserID: 2})
+
+	// 未订阅的事件类型不触发；中间订阅方 panic 不影响后续订阅方
+	if len(got) != 2 || got[0].UserID != 2 || got[1].UserID != 2 {
+		t.Fatalf("unexpected dispatched events: %+v", got)
+	}
+}
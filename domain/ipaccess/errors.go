@@ -0,0 +1,18 @@
+package ipaccess
+
+import "errors"
+
+var (
+	// ErrInvalidScope 作用域不存在
+	ErrInvalidScope = errors.New("invalid ip access scope")
+	// ErrTenantRequired tenant 作用域未指定租户
+	ErrTenantRequired = errors.New("tenant id is required for tenant scope")
+	// ErrInvalidCIDR 网段或 IP 格式无效
+	ErrInvalidCIDR = errors.New("invalid cidr")
+	// ErrTenantNotFound 租户不存在
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrSelfLockout 保存后发起修改的管理员当前地址将被拒绝
+	ErrSelfLockout = errors.New("rules would block the caller's current ip")
+	// ErrNotInitialized 未调用 Init 设置名单仓储
+	ErrNotInitialized = errors.New("ip access rules are not initialized")
+)
@@ -0,0 +1,178 @@
+// Package ipaccess 维护基于 CIDR 的 IP 访问控制名单。
+// 名单按作用域划分：global 作用于所有已登录请求，tenant 仅作用于该租户的请求，
+// platform 额外作用于平台管理接口（/platform）。同一作用域内拒绝名单优先；
+// 允许名单非空时只放行命中允许名单的地址。请求需依次通过所有适用作用域的校验。
+package ipaccess
+
+import (
+	"context"
+	"net/netip"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"api-server/domain/admin/repo"
+	"api-server/domain/event"
+	"api-server/util/snapshot"
+)
+
+// 名单作用域
+const (
+	ScopeGlobal   = "global"   // 所有已登录请求
+	ScopeTenant   = "tenant"   // 指定租户的请求
+	ScopePlatform = "platform" // 平台管理接口
+)
+
+// 名单类型
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+// Scopes 支持的作用域
+var Scopes = []string{ScopeGlobal, ScopeTenant, ScopePlatform}
+
+// listKey 名单标识，非 tenant 作用域的 tenantID 为 0
+type listKey struct {
+	scope    string
+	tenantID uint
+}
+
+// list 单个作用域的允许、拒绝名单
+type list struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// Decision 校验结果；Allowed 为 false 时 Scope/TenantID 为拒绝请求的名单，
+// Rule 为命中的拒绝规则（未命中允许名单时为空）
+type Decision struct {
+	Allowed  bool
+	Scope    string
+	TenantID uint
+	Rule     string
+}
+
+// store 名单仓储与按作用域解析后的名单缓存
+type store struct {
+	rules   repo.IPRuleRepo
+	tenants repo.TenantRepo
+	lists   *snapshot.Snapshot[map[listKey]list]
+}
+
+// current 由 Init 设置；为 nil 时不拦截任何请求
+var current atomic.Pointer[store]
+
+// Init 注入规则与租户仓储；启动时先于 Reload 调用，测试中可传入内存仓储
+func Init(r repo.Repos) {
+	rules := r.IPRules
+	current.Store(&store{
+		rules:   rules,
+		tenants: r.Tenants,
+		lists: snapshot.New("IP 访问控制名单", func(ctx context.Context) (map[listKey]list, error) {
+			items, err := rules.All(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[listKey]list)
+			for _, item := range items {
+				p, err := parsePrefix(item.CIDR)
+				if err != nil {
+					zap.L().Warn("忽略无效的 IP 访问控制规则", zap.Uint("id", item.ID), zap.String("cidr", item.CIDR))
+					continue
+				}
+				key := listKey{scope: item.Scope, tenantID: item.TenantID}
+				l := result[key]
+				if item.Action == ActionDeny {
+					l.deny = append(l.deny, p)
+				} else {
+					l.allow = append(l.allow, p)
+				}
+				result[key] = l
+			}
+			return result, nil
+		}),
+	})
+}
+
+// currentLists 返回当前名单，缓存过期时在后台刷新
+func currentLists() map[listKey]list {
+	if st := current.Load(); st != nil {
+		return st.lists.Get()
+	}
+	return nil
+}
+
+// IsScope 判断作用域名称是否有效
+func IsScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 依次按全局名单与租户名单（tenantID 非 0 时）校验客户端地址；缓存过期时在后台刷新，不阻塞当前请求
+func Check(ip netip.Addr, tenantID uint) Decision {
+	keys := []listKey{{scope: ScopeGlobal}}
+	if tenantID != 0 {
+		keys = append(keys, listKey{scope: ScopeTenant, tenantID: tenantID})
+	}
+	return evaluate(currentLists(), ip, keys...)
+}
+
+// CheckPlatform 按平台管理接口名单校验客户端地址，需与 Check 配合使用
+func CheckPlatform(ip netip.Addr) Decision {
+	return evaluate(currentLists(), ip, listKey{scope: ScopePlatform})
+}
+
+// evaluate 按顺序校验各名单，返回第一个拒绝结果
+func evaluate(lists map[listKey]list, ip netip.Addr, keys ...listKey) Decision {
+	ip = ip.Unmap()
+	for _, key := range keys {
+		l, ok := lists[key]
+		if !ok {
+			continue
+		}
+		if p, hit := match(l.deny, ip); hit {
+			return Decision{Scope: key.scope, TenantID: key.tenantID, Rule: p.String()}
+		}
+		if _, hit := match(l.allow, ip); len(l.allow) > 0 && !hit {
+			return Decision{Scope: key.scope, TenantID: key.tenantID}
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+func match(prefixes []netip.Prefix, ip netip.Addr) (netip.Prefix, bool) {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return p, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// Reload 同步刷新名单缓存，用于启动预加载与名单变更后；未 Init 时为空操作
+func Reload(ctx context.Context) error {
+	if st := current.Load(); st != nil {
+		return st.lists.Reload(ctx)
+	}
+	return nil
+}
+
+// RegisterHandlers 订阅租户删除事件，清理该租户的名单并刷新本实例缓存
+func RegisterHandlers() {
+	event.Subscribe(event.TenantDeleted, func(ctx context.Context, e event.Event) {
+		st := current.Load()
+		if st == nil {
+			return
+		}
+		if err := st.rules.Replace(ctx, ScopeTenant, e.TenantID, nil); err != nil {
+			zap.L().Warn("清理已删除租户的 IP 访问控制名单失败", zap.Uint("tenant_id", e.TenantID), zap.Error(err))
+			return
+		}
+		_ = Reload(ctx)
+	})
+}
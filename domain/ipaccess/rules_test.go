@@ -0,0 +1,159 @@
+package ipaccess
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/event"
+)
+
+// useRules 以内存仓储初始化名单，返回该仓储供测试继续修改
+func useRules(t *testing.T, items ...Rules) repo.Repos {
+	t.Helper()
+	ctx := context.Background()
+	r := memory.New().Repos()
+	for _, item := range items {
+		var rows []system.SystemIPRule
+		for _, cidr := range item.Allow {
+			rows = append(rows, system.SystemIPRule{Scope: item.Scope, TenantID: item.TenantID, Action: ActionAllow, CIDR: cidr})
+		}
+		for _, cidr := range item.Deny {
+			rows = append(rows, system.SystemIPRule{Scope: item.Scope, TenantID: item.TenantID, Action: ActionDeny, CIDR: cidr})
+		}
+		if err := r.IPRules.Replace(ctx, item.Scope, item.TenantID, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	Init(r)
+	if err := Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { current.Store(nil) })
+	return r
+}
+
+func TestCheck(t *testing.T) {
+	useRules(t,
+		Rules{Scope: ScopeGlobal, Deny: []string{"198.51.100.0/24"}},
+		Rules{Scope: ScopeTenant, TenantID: 7, Allow: []string{"203.0.113.0/24"}, Deny: []string{"203.0.113.66"}},
+		Rules{Scope: ScopePlatform, Allow: []string{"10.0.0.0/8"}},
+	)
+
+	tests := []struct {
+		name     string
+		ip       string
+		tenantID uint
+		platform bool
+		want     Decision
+	}{
+		{"no tenant rules", "192.0.2.1", 8, false, Decision{Allowed: true}},
+		{"global deny", "198.51.100.9", 8, false, Decision{Scope: ScopeGlobal, Rule: "198.51.100.0/24"}},
+		{"tenant allowlist hit", "203.0.113.10", 7, false, Decision{Allowed: true}},
+		{"tenant allowlist miss", "192.0.2.1", 7, false, Decision{Scope: ScopeTenant, TenantID: 7}},
+		{"deny wins over allow", "203.0.113.66", 7, false, Decision{Scope: ScopeTenant, TenantID: 7, Rule: "203.0.113.66/32"}},
+		{"ipv4-mapped address", "::ffff:203.0.113.10", 7, false, Decision{Allowed: true}},
+		{"platform allowlist miss", "192.0.2.1", 0, true, Decision{Scope: ScopePlatform}},
+		{"platform allowlist hit", "10.1.2.3", 0, true, Decision{Allowed: true}},
+	}
+	for _, tt := range tests {
+		ip := netip.MustParseAddr(tt.ip)
+		got := Check(ip, tt.tenantID)
+		if tt.platform {
+			got = CheckPlatform(ip)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeList(t *testing.T) {
+	got, err := normalizeList([]string{" 203.0.113.7/24 ", "192.0.2.1", "", "203.0.113.0/24", "2001:db8::1", "::ffff:192.0.2.9"})
+	if err != nil {
+		t.Fatalf("normalizeList() error = %v", err)
+	}
+	want := []string{"203.0.113.0/24", "192.0.2.1/32", "2001:db8::1/128", "192.0.2.9/32"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("normalizeList() = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"office", "10.0.0.0/33", "fe80::1%eth0"} {
+		if _, err := normalizeList([]string{bad}); !errors.Is(err, ErrInvalidCIDR) {
+			t.Errorf("normalizeList(%q) error = %v, want ErrInvalidCIDR", bad, err)
+		}
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	if key, err := normalizeKey(ScopePlatform, 9); err != nil || key != (listKey{scope: ScopePlatform}) {
+		t.Errorf("normalizeKey(platform, 9) = %+v, %v, want tenant id dropped", key, err)
+	}
+	if _, err := normalizeKey(ScopeTenant, 0); !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("normalizeKey(tenant, 0) error = %v, want ErrTenantRequired", err)
+	}
+	if _, err := normalizeKey("office", 0); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("normalizeKey(office) error = %v, want ErrInvalidScope", err)
+	}
+}
+
+func TestWouldBlock(t *testing.T) {
+	useRules(t, Rules{Scope: ScopeTenant, TenantID: 1, Allow: []string{"10.0.0.0/8"}})
+	caller := Caller{IP: netip.MustParseAddr("10.1.2.3"), TenantID: 1}
+
+	office, _ := toList(Rules{Allow: []string{"10.0.0.0/8"}})
+	if wouldBlock(listKey{scope: ScopePlatform}, office, caller) {
+		t.Error("allowlist containing caller should not block")
+	}
+	vpn, _ := toList(Rules{Allow: []string{"192.0.2.0/24"}})
+	if !wouldBlock(listKey{scope: ScopePlatform}, vpn, caller) {
+		t.Error("platform allowlist without caller should block")
+	}
+	// 修改其它租户的名单不影响调用方
+	if wouldBlock(listKey{scope: ScopeTenant, tenantID: 2}, vpn, caller) {
+		t.Error("other tenant's allowlist should not block caller")
+	}
+	// 调用方所在租户已有的名单同样参与判断
+	deny, _ := toList(Rules{Deny: []string{"10.1.0.0/16"}})
+	if !wouldBlock(listKey{scope: ScopeGlobal}, deny, caller) {
+		t.Error("global deny covering caller should block")
+	}
+}
+
+func TestSaveRulesAndTenantDeleted(t *testing.T) {
+	ctx := context.Background()
+	r := useRules(t)
+	event.Reset()
+	t.Cleanup(event.Reset)
+	RegisterHandlers()
+
+	input := Rules{Scope: ScopeTenant, TenantID: 5, Allow: []string{"203.0.113.0/24"}}
+	if _, err := SaveRules(ctx, input, Caller{}); !errors.Is(err, ErrTenantNotFound) {
+		t.Fatalf("SaveRules() missing tenant error = %v, want ErrTenantNotFound", err)
+	}
+	tenant := system.SystemTenant{Code: "t5"}
+	tenant.ID = 5
+	if err := r.Tenants.Create(ctx, &tenant); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveRules(ctx, input, Caller{}); err != nil {
+		t.Fatal(err)
+	}
+	ip := netip.MustParseAddr("192.0.2.1")
+	if d := Check(ip, 5); d.Allowed {
+		t.Fatal("saved tenant allowlist should take effect immediately")
+	}
+
+	// 删除租户后清理其名单并刷新缓存
+	event.Publish(ctx, event.Event{Type: event.TenantDeleted, TenantID: 5})
+	if items, err := r.IPRules.Find(ctx, ScopeTenant, 5); err != nil || len(items) != 0 {
+		t.Fatalf("rules after tenant deleted = %+v, %v, want none", items, err)
+	}
+	if d := Check(ip, 5); !d.Allowed {
+		t.Fatalf("Check() after tenant deleted = %+v, want allowed", d)
+	}
+}
@@ -0,0 +1,217 @@
+package ipaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"api-server/db/pgdb/system"
+)
+
+// Rules 单个作用域的名单，条目为 CIDR 或单个 IP（保存时规范化为 CIDR）
+type Rules struct {
+	Scope    string
+	TenantID uint
+	Allow    []string
+	Deny     []string
+}
+
+// Caller 修改名单的管理员，用于拒绝会把其当前地址挡在外面的修改
+type Caller struct {
+	IP       netip.Addr
+	TenantID uint
+}
+
+// ListRules 查询名单；scope 为空时返回所有已配置的名单，否则返回指定作用域（未配置时为空名单）
+func ListRules(ctx context.Context, scope string, tenantID uint) ([]Rules, error) {
+	st := current.Load()
+	if st == nil {
+		return nil, ErrNotInitialized
+	}
+	if scope == "" {
+		items, err := st.rules.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return groupRules(items), nil
+	}
+
+	key, err := normalizeKey(scope, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := st.rules.Find(ctx, key.scope, key.tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if grouped := groupRules(items); len(grouped) > 0 {
+		return grouped, nil
+	}
+	return []Rules{{Scope: key.scope, TenantID: key.tenantID, Allow: []string{}, Deny: []string{}}}, nil
+}
+
+// SaveRules 覆盖指定作用域的名单，传入空列表表示清空；保存后立即刷新本实例缓存。
+// 保存后 caller 的当前地址将被拒绝时返回 ErrSelfLockout，避免管理员误操作后无法再修改名单。
+func SaveRules(ctx context.Context, input Rules, caller Caller) (Rules, error) {
+	st := current.Load()
+	if st == nil {
+		return Rules{}, ErrNotInitialized
+	}
+	key, err := normalizeKey(input.Scope, input.TenantID)
+	if err != nil {
+		return Rules{}, err
+	}
+	normalized := Rules{Scope: key.scope, TenantID: key.tenantID}
+	if normalized.Allow, err = normalizeList(input.Allow); err != nil {
+		return Rules{}, err
+	}
+	if normalized.Deny, err = normalizeList(input.Deny); err != nil {
+		return Rules{}, err
+	}
+
+	if key.scope == ScopeTenant {
+		tenant := system.SystemTenant{}
+		tenant.ID = key.tenantID
+		if err := st.tenants.Get(ctx, &tenant); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return Rules{}, ErrTenantNotFound
+			}
+			return Rules{}, err
+		}
+	}
+
+	if caller.IP.IsValid() {
+		l, _ := toList(normalized)
+		if wouldBlock(key, l, caller) {
+			return Rules{}, ErrSelfLockout
+		}
+	}
+
+	items := make([]system.SystemIPRule, 0, len(normalized.Allow)+len(normalized.Deny))
+	for _, cidr := range normalized.Allow {
+		items = append(items, system.SystemIPRule{Scope: key.scope, TenantID: key.tenantID, Action: ActionAllow, CIDR: cidr})
+	}
+	for _, cidr := range normalized.Deny {
+		items = append(items, system.SystemIPRule{Scope: key.scope, TenantID: key.tenantID, Action: ActionDeny, CIDR: cidr})
+	}
+	if err := st.rules.Replace(ctx, key.scope, key.tenantID, items); err != nil {
+		return Rules{}, err
+	}
+	_ = st.lists.Reload(context.WithoutCancel(ctx))
+	return normalized, nil
+}
+
+// wouldBlock 以当前缓存为基础替换 key 对应的名单，判断 caller 访问平台管理接口时是否会被拒绝
+func wouldBlock(key listKey, l list, caller Caller) bool {
+	next := maps.Clone(currentLists())
+	if next == nil {
+		next = map[listKey]list{}
+	}
+	next[key] = l
+
+	keys := []listKey{{scope: ScopeGlobal}}
+	if caller.TenantID != 0 {
+		keys = append(keys, listKey{scope: ScopeTenant, tenantID: caller.TenantID})
+	}
+	keys = append(keys, listKey{scope: ScopePlatform})
+	return !evaluate(next, caller.IP, keys...).Allowed
+}
+
+func normalizeKey(scope string, tenantID uint) (listKey, error) {
+	if !IsScope(scope) {
+		return listKey{}, ErrInvalidScope
+	}
+	if scope != ScopeTenant {
+		return listKey{scope: scope}, nil
+	}
+	if tenantID == 0 {
+		return listKey{}, ErrTenantRequired
+	}
+	return listKey{scope: scope, tenantID: tenantID}, nil
+}
+
+// normalizeList 校验并规范化条目（去除空白与重复，单个 IP 转为 /32 或 /128），保持原有顺序
+func normalizeList(items []string) ([]string, error) {
+	result := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		p, err := parsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCIDR, item)
+		}
+		if s := p.String(); !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// parsePrefix 解析 CIDR（203.0.113.0/24）或单个 IP
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("zoned address %q", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func toList(r Rules) (list, error) {
+	var l list
+	for _, s := range r.Allow {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return list{}, err
+		}
+		l.allow = append(l.allow, p)
+	}
+	for _, s := range r.Deny {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return list{}, err
+		}
+		l.deny = append(l.deny, p)
+	}
+	return l, nil
+}
+
+// groupRules 按作用域聚合数据库记录，保持查询顺序
+func groupRules(items []system.SystemIPRule) []Rules {
+	var result []Rules
+	index := make(map[listKey]int)
+	for _, item := range items {
+		key := listKey{scope: item.Scope, tenantID: item.TenantID}
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, Rules{Scope: item.Scope, TenantID: item.TenantID, Allow: []string{}, Deny: []string{}})
+		}
+		if item.Action == ActionDeny {
+			result[i].Deny = append(result[i].Deny, item.CIDR)
+		} else {
+			result[i].Allow = append(result[i].Allow, item.CIDR)
+		}
+	}
+	return result
+}
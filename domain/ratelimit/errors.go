@@ -0,0 +1,16 @@
+package ratelimit
+
+import "errors"
+
+var (
+	// ErrUnknownRouteGroup 路由组不存在
+	ErrUnknownRouteGroup = errors.New("unknown route group")
+	// ErrInvalidRule 限流规则无效（速率为负或突发容量不足）
+	ErrInvalidRule = errors.New("invalid rate limit rule")
+	// ErrTenantNotFound 租户不存在
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrOverrideNotFound 租户限流覆盖不存在
+	ErrOverrideNotFound = errors.New("rate limit override not found")
+	// ErrNotInitialized 未调用 Init 设置覆盖仓储
+	ErrNotInitialized = errors.New("rate limit overrides are not initialized")
+)
@@ -0,0 +1,109 @@
+// Package ratelimit 解析按路由组划分的租户级、用户级限流策略。
+// 策略来源优先级：平台为租户设置的覆盖（数据库）> 配置文件 rate_limit.policies。
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/event"
+	"api-server/util/snapshot"
+)
+
+// 路由组名称
+const (
+	GroupSystem   = "system"   // /api/v1/private/admin/system 租户控制台接口
+	GroupPlatform = "platform" // /api/v1/private/admin/platform 平台管理接口
+)
+
+// RouteGroups 支持配置限流策略的路由组
+var RouteGroups = []string{GroupSystem, GroupPlatform}
+
+// store 覆盖与租户仓储及按租户、路由组解析后的覆盖缓存
+type store struct {
+	limits    repo.RateLimitRepo
+	tenants   repo.TenantRepo
+	overrides *snapshot.Snapshot[map[uint]map[string]config.RateLimitPolicy]
+}
+
+// current 由 Init 设置；为 nil 时只使用配置文件策略
+var current atomic.Pointer[store]
+
+// Init 注入覆盖与租户仓储；启动时先于 Reload 调用，测试中可传入内存仓储
+func Init(r repo.Repos) {
+	limits := r.RateLimits
+	current.Store(&store{
+		limits:  limits,
+		tenants: r.Tenants,
+		overrides: snapshot.New("租户限流策略", func(ctx context.Context) (map[uint]map[string]config.RateLimitPolicy, error) {
+			items, err := limits.All(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uint]map[string]config.RateLimitPolicy)
+			for _, item := range items {
+				if result[item.TenantID] == nil {
+					result[item.TenantID] = map[string]config.RateLimitPolicy{}
+				}
+				result[item.TenantID][item.RouteGroup] = toPolicy(item)
+			}
+			return result, nil
+		}),
+	})
+}
+
+// IsRouteGroup 判断路由组名称是否有效
+func IsRouteGroup(group string) bool {
+	for _, g := range RouteGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve 返回租户在路由组上生效的限流策略。
+// 配置文件策略每次读取最新值，配置热加载后立即生效；租户覆盖缓存过期时在后台刷新，不阻塞当前请求。
+func Resolve(group string, tenantID uint) config.RateLimitPolicy {
+	if st := current.Load(); st != nil {
+		if policy, ok := st.overrides.Get()[tenantID][group]; ok {
+			return policy
+		}
+	}
+	return config.Current().RateLimitPolicies[group]
+}
+
+// Reload 同步刷新覆盖缓存，用于启动预加载与覆盖变更后；未 Init 时为空操作
+func Reload(ctx context.Context) error {
+	if st := current.Load(); st != nil {
+		return st.overrides.Reload(ctx)
+	}
+	return nil
+}
+
+// RegisterHandlers 订阅租户删除事件，清理该租户的限流覆盖并刷新本实例缓存
+func RegisterHandlers() {
+	event.Subscribe(event.TenantDeleted, func(ctx context.Context, e event.Event) {
+		st := current.Load()
+		if st == nil {
+			return
+		}
+		if err := st.limits.DeleteTenant(ctx, e.TenantID); err != nil {
+			zap.L().Warn("清理已删除租户的限流策略失败", zap.Uint("tenant_id", e.TenantID), zap.Error(err))
+			return
+		}
+		_ = Reload(ctx)
+	})
+}
+
+func toPolicy(item system.SystemTenantRateLimit) config.RateLimitPolicy {
+	return config.RateLimitPolicy{
+		Tenant: config.RateLimitRule{Rate: item.TenantRate, Burst: item.TenantBurst},
+		User:   config.RateLimitRule{Rate: item.UserRate, Burst: item.UserBurst},
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/repo/memory"
+	"api-server/domain/event"
+)
+
+// useOverrides 以内存仓储初始化限流覆盖并预加载
+func useOverrides(t *testing.T, items ...system.SystemTenantRateLimit) repo.Repos {
+	t.Helper()
+	ctx := context.Background()
+	r := memory.New().Repos()
+	for i := range items {
+		if err := r.RateLimits.Save(ctx, &items[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	Init(r)
+	if err := Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { current.Store(nil) })
+	return r
+}
+
+// usePolicies 发布包含给定限流策略的运行时配置，测试结束后恢复
+func usePolicies(t *testing.T, policies map[string]config.RateLimitPolicy) {
+	t.Helper()
+	prev := config.Current()
+	t.Cleanup(func() { config.StoreRuntime(prev) })
+	next := *prev
+	next.RateLimitPolicies = policies
+	config.StoreRuntime(&next)
+}
+
+func TestResolvePrefersTenantOverride(t *testing.T) {
+	usePolicies(t, map[string]config.RateLimitPolicy{
+		GroupSystem: {User: config.RateLimitRule{Rate: 10, Burst: 20}},
+	})
+	useOverrides(t, system.SystemTenantRateLimit{TenantID: 7, RouteGroup: GroupSystem, TenantRate: 1, TenantBurst: 2})
+
+	if got := Resolve(GroupSystem, 7); got.Tenant.Rate != 1 || got.User.Enabled() {
+		t.Fatalf("tenant 7 should use override, got %+v", got)
+	}
+	if got := Resolve(GroupSystem, 8); got.User.Rate != 10 || got.Tenant.Enabled() {
+		t.Fatalf("tenant 8 should use config policy, got %+v", got)
+	}
+
+	// 配置热加载后立即生效
+	usePolicies(t, map[string]config.RateLimitPolicy{
+		GroupSystem: {User: config.RateLimitRule{Rate: 5, Burst: 5}},
+	})
+	if got := Resolve(GroupSystem, 8); got.User.Rate != 5 {
+		t.Fatalf("reloaded config not applied, got %+v", got)
+	}
+	if got := Resolve(GroupPlatform, 8); got.Tenant.Enabled() || got.User.Enabled() {
+		t.Fatalf("unconfigured group should not be limited, got %+v", got)
+	}
+}
+
+func TestSaveOverrideAndTenantDeleted(t *testing.T) {
+	ctx := context.Background()
+	r := useOverrides(t)
+	event.Reset()
+	t.Cleanup(event.Reset)
+	RegisterHandlers()
+
+	input := Override{
+		TenantID:   5,
+		RouteGroup: GroupSystem,
+		Policy:     config.RateLimitPolicy{Tenant: config.RateLimitRule{Rate: 1, Burst: 2}},
+	}
+	if err := SaveOverride(ctx, input); !errors.Is(err, ErrTenantNotFound) {
+		t.Fatalf("SaveOverride() missing tenant error = %v, want ErrTenantNotFound", err)
+	}
+	tenant := system.SystemTenant{Code: "t5"}
+	tenant.ID = 5
+	if err := r.Tenants.Create(ctx, &tenant); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveOverride(ctx, input); err != nil {
+		t.Fatal(err)
+	}
+	if got := Resolve(GroupSystem, 5); got.Tenant.Rate != 1 {
+		t.Fatalf("saved override should take effect immediately, got %+v", got)
+	}
+
+	// 删除租户后清理其覆盖并刷新缓存
+	event.Publish(ctx, event.Event{Type: event.TenantDeleted, TenantID: 5})
+	if items, err := r.RateLimits.ForTenant(ctx, 5); err != nil || len(items) != 0 {
+		t.Fatalf("overrides after tenant deleted = %+v, %v, want none", items, err)
+	}
+	if got := Resolve(GroupSystem, 5); got.Tenant.Enabled() {
+		t.Fatalf("Resolve() after tenant deleted = %+v, want config policy", got)
+	}
+	if err := DeleteOverride(ctx, 5, GroupSystem); !errors.Is(err, ErrOverrideNotFound) {
+		t.Fatalf("DeleteOverride() error = %v, want ErrOverrideNotFound", err)
+	}
+}
+
+func TestValidRule(t *testing.T) {
+	tests := []struct {
+		rule config.RateLimitRule
+		want bool
+	}{
+		{config.RateLimitRule{}, true},
+		{config.RateLimitRule{Rate: 5, Burst: 10}, true},
+		{config.RateLimitRule{Rate: 5}, false},
+		{config.RateLimitRule{Rate: -1, Burst: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := validRule(tt.rule); got != tt.want {
+			t.Fatalf("validRule(%+v) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"api-server/config"
+	"api-server/db/pgdb/system"
+)
+
+// Override 租户限流覆盖
+type Override struct {
+	TenantID   uint
+	RouteGroup string
+	Policy     config.RateLimitPolicy
+}
+
+// EffectivePolicy 路由组当前生效的策略及其来源
+type EffectivePolicy struct {
+	RouteGroup string
+	Policy     config.RateLimitPolicy
+	Overridden bool // true 表示来自平台为该租户设置的覆盖
+}
+
+// ListOverrides 查询租户限流覆盖；tenantID 为 0 时返回所有租户
+func ListOverrides(ctx context.Context, tenantID uint) ([]Override, error) {
+	st := current.Load()
+	if st == nil {
+		return nil, ErrNotInitialized
+	}
+	var items []system.SystemTenantRateLimit
+	var err error
+	if tenantID == 0 {
+		items, err = st.limits.All(ctx)
+	} else {
+		items, err = st.limits.ForTenant(ctx, tenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Override, 0, len(items))
+	for _, item := range items {
+		result = append(result, Override{
+			TenantID:   item.TenantID,
+			RouteGroup: item.RouteGroup,
+			Policy:     toPolicy(item),
+		})
+	}
+	return result, nil
+}
+
+// GetEffectivePolicies 返回租户在各路由组上生效的策略
+func GetEffectivePolicies(ctx context.Context, tenantID uint) ([]EffectivePolicy, error) {
+	st := current.Load()
+	if st == nil {
+		return nil, ErrNotInitialized
+	}
+	items, err := st.limits.ForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	byGroup := make(map[string]config.RateLimitPolicy, len(items))
+	for _, item := range items {
+		byGroup[item.RouteGroup] = toPolicy(item)
+	}
+
+	result := make([]EffectivePolicy, 0, len(RouteGroups))
+	for _, group := range RouteGroups {
+		if policy, ok := byGroup[group]; ok {
+			result = append(result, EffectivePolicy{RouteGroup: group, Policy: policy, Overridden: true})
+			continue
+		}
+		result = append(result, EffectivePolicy{RouteGroup: group, Policy: config.Current().RateLimitPolicies[group]})
+	}
+	return result, nil
+}
+
+// SaveOverride 为租户设置路由组限流覆盖，保存后立即刷新本实例缓存
+func SaveOverride(ctx context.Context, input Override) error {
+	if !IsRouteGroup(input.RouteGroup) {
+		return ErrUnknownRouteGroup
+	}
+	if !validRule(input.Policy.Tenant) || !validRule(input.Policy.User) {
+		return ErrInvalidRule
+	}
+	st := current.Load()
+	if st == nil {
+		return ErrNotInitialized
+	}
+
+	tenant := system.SystemTenant{}
+	tenant.ID = input.TenantID
+	if err := st.tenants.Get(ctx, &tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTenantNotFound
+		}
+		return err
+	}
+
+	item := system.SystemTenantRateLimit{
+		TenantID:    input.TenantID,
+		RouteGroup:  input.RouteGroup,
+		TenantRate:  input.Policy.Tenant.Rate,
+		TenantBurst: input.Policy.Tenant.Burst,
+		UserRate:    input.Policy.User.Rate,
+		UserBurst:   input.Policy.User.Burst,
+	}
+	if err := st.limits.Save(ctx, &item); err != nil {
+		return err
+	}
+	_ = Reload(context.WithoutCancel(ctx))
+	return nil
+}
+
+// DeleteOverride 删除租户路由组限流覆盖，恢复为配置文件中的默认策略
+func DeleteOverride(ctx context.Context, tenantID uint, group string) error {
+	if !IsRouteGroup(group) {
+		return ErrUnknownRouteGroup
+	}
+	st := current.Load()
+	if st == nil {
+		return ErrNotInitialized
+	}
+	affected, err := st.limits.Delete(ctx, tenantID, group)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOverrideNotFound
+	}
+	_ = Reload(context.WithoutCancel(ctx))
+	return nil
+}
+
+// validRule 速率为 0 表示不限流；启用时突发容量至少为 1
+func validRule(rule config.RateLimitRule) bool {
+	if rule.Rate < 0 {
+		return false
+	}
+	return rule.Rate == 0 || rule.Burst >= 1
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"go.uber.org/zap"
@@ -16,20 +19,40 @@ import (
 	"api-server/api/middleware"
 	"api-server/config"
 	"api-server/cron"
-	"api-server/db/pgdb"
-	"api-server/db/pgdb/system"
+	"api-server/db/rdb"
+	"api-server/domain/admin"
+	"api-server/domain/admin/menu"
+	"api-server/domain/admin/repo"
+	"api-server/domain/admin/user"
+	"api-server/domain/cors"
+	"api-server/domain/diagnostics"
+	"api-server/domain/ipaccess"
+	"api-server/domain/ratelimit"
 	"api-server/util/acme"
+	"api-server/util/graceful"
 	"api-server/util/log"
 	pathtool "api-server/util/path-tool"
 	"api-server/util/pidfile"
+	"api-server/util/quic"
+	"api-server/util/realip"
 	runmodel "api-server/util/run-model"
 	"api-server/util/tlsfile"
 )
 
 var CLI struct {
 	Dev     bool `help:"以开发模式运行" short:"d"`
-	Migrate bool `help:"执行数据库迁移后退出"`
 	Version bool `help:"显示版本信息" short:"v"`
+	// LegacyMigrate 兼容旧部署脚本，等同于 migrate up
+	LegacyMigrate bool `name:"migrate" hidden:"" help:"执行数据库迁移后退出（已废弃，请使用 migrate up）"`
+
+	Serve   struct{}   `cmd:"" default:"1" help:"启动 HTTP 服务（默认）"`
+	Migrate MigrateCmd `cmd:"" help:"数据库迁移：up/down/status/to"`
+	User    UserCmd    `cmd:"" help:"用户运维：创建、重置密码、禁用、解除禁用"`
+	Tenant  TenantCmd  `cmd:"" help:"租户运维：创建、列表、禁用"`
+	Menu    MenuCmd    `cmd:"" help:"平台菜单导入导出"`
+	Cache   CacheCmd   `cmd:"" help:"缓存运维"`
+	Token   TokenCmd   `cmd:"" help:"调试用令牌"`
+	Config  ConfigCmd  `cmd:"" help:"配置工具"`
 }
 
 var (
@@ -38,15 +61,6 @@ var (
 	GitCommit = "unknown"
 )
 
-func migrate() error {
-	if err := system.Migrate(pgdb.GetClient()); err != nil {
-		zap.L().Error("数据库迁移失败", zap.Error(err))
-		return err
-	}
-	zap.L().Info("数据库迁移完成")
-	return nil
-}
-
 func main() {
 	ctx := kong.Parse(&CLI,
 		kong.Name("api-server"),
@@ -61,8 +75,18 @@ func main() {
 		os.Exit(0)
 	}
 
-	if err := config.LoadConfig(); err != nil {
-		fmt.Printf("Failed to load configuration: %v\n", err)
+	diagnostics.SetBuildInfo(Version, BuildTime, GitCommit)
+
+	loadErr := config.LoadConfig()
+	if ctx.Command() == "config check" {
+		if err := ctx.Run(configLoadResult{err: loadErr}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			ctx.Exit(1)
+		}
+		ctx.Exit(0)
+	}
+	if loadErr != nil {
+		fmt.Printf("Failed to load configuration: %v\n", loadErr)
 		ctx.Exit(1)
 	}
 
@@ -72,8 +96,8 @@ func main() {
 		runmodel.Detection()
 	}
 
-	// 仅在生产模式创建日志目录，避免测试/子包初始化时散落空 log 目录
-	if runmodel.IsRelease() {
+	// 仅在生产模式且启用文件输出时创建日志目录，避免测试/子包初始化时散落空 log 目录
+	if runmodel.IsRelease() && log.FileSinkEnabled() {
 		if err := pathtool.CreateDir(config.LogDir); err != nil {
 			fmt.Printf("创建日志目录失败: %v\n", err)
 			ctx.Exit(1)
@@ -83,36 +107,77 @@ func main() {
 	log.GetLogger()
 	log.StartMonitor()
 
-	config.WatchConfig(func() {
-		zap.L().Info("配置重载成功",
-			zap.Int("port", config.ListenPort),
-			zap.Duration("jwt_expiration", config.JWTExpiration),
-			zap.Bool("global_rate_limit", config.EnableRateLimit),
-		)
+	config.WatchConfig(func(result config.ReloadResult) {
+		log.ApplyLevels()
+		zap.L().Info("配置重载成功", zap.Strings("changed", result.Changed))
+		if len(result.RestartRequired) > 0 {
+			zap.L().Warn("以下配置已修改但需重启服务后才能生效", zap.Strings("keys", result.RestartRequired))
+		}
 	})
 
-	config.CheckConfig(
-		config.JWTKey,
-		int64(config.JWTExpiration),
-		config.RedisHost,
-		config.RedisPassword,
-		config.PgsqlDSN,
-		config.AdminPassword,
-		config.PWDSalt,
-	)
+	// 一次性列出全部配置问题（键名与对应的环境变量名），避免部署后才在运行时暴露
+	if err := config.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		zap.L().Fatal("配置无效", zap.Error(err))
+	}
+	if err := rdb.ValidateConfig(); err != nil {
+		zap.L().Fatal("Redis 配置无效", zap.Error(err))
+	}
+
+	if CLI.LegacyMigrate || strings.HasPrefix(ctx.Command(), "migrate ") {
+		exitCode := 0
+		if err := runMigrate(ctx, CLI.LegacyMigrate); err != nil {
+			zap.L().Error("数据库迁移失败", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "数据库迁移失败: %v\n", err)
+			exitCode = 1
+		}
+		log.StopMonitor()
+		ctx.Exit(exitCode)
+	}
 
-	if CLI.Migrate {
+	if ctx.Command() != "serve" {
 		exitCode := 0
-		if err := migrate(); err != nil {
+		if err := runAdminCommand(ctx); err != nil {
+			zap.L().Error("命令执行失败", zap.String("command", ctx.Command()), zap.Error(err))
+			fmt.Fprintf(os.Stderr, "命令执行失败: %v\n", err)
 			exitCode = 1
 		}
 		log.StopMonitor()
 		ctx.Exit(exitCode)
 	}
 
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 10*time.Second)
+	warnPendingMigrations(checkCtx)
+	cancelCheck()
+
+	// 跨域来源、IP 名单、限流覆盖与领域服务共用同一组 GORM 仓储
+	repos := repo.NewGormRepos()
+
+	// 用户/角色变更事件增量维护用户缓存，定时任务仅做低频全量对账
+	user.RegisterCacheHandlers()
+	// 租户变更事件刷新租户级跨域来源；启动时预加载，避免首批请求遗漏租户来源
+	cors.Init(repos)
+	cors.RegisterHandlers()
+	corsCtx, cancelCors := context.WithTimeout(context.Background(), 10*time.Second)
+	_ = cors.Reload(corsCtx)
+	cancelCors()
+	// IP 访问控制名单：启动时预加载，避免名单加载前的请求绕过校验；租户删除时清理其名单
+	ipaccess.Init(repos)
+	ipaccess.RegisterHandlers()
+	ipCtx, cancelIP := context.WithTimeout(context.Background(), 10*time.Second)
+	_ = ipaccess.Reload(ipCtx)
+	cancelIP()
+	// 租户限流覆盖：启动时预加载，租户删除时清理其覆盖
+	ratelimit.Init(repos)
+	ratelimit.RegisterHandlers()
+	rlCtx, cancelRL := context.WithTimeout(context.Background(), 10*time.Second)
+	_ = ratelimit.Reload(rlCtx)
+	cancelRL()
 	cron.InitCronJobs()
 
-	r := api.InitApi()
+	// 领域服务统一在此构造：生产环境注入 GORM 仓储与 Redis 菜单树缓存
+	services := admin.NewServices(repos, menu.RedisTreeCache{})
+	r := api.InitApi(services)
 	srv := &http.Server{
 		Addr:           fmt.Sprintf(":%d", config.ListenPort),
 		Handler:        r,
@@ -121,16 +186,53 @@ func main() {
 		IdleTimeout:    config.IdleTimeout,
 		MaxHeaderBytes: config.MaxHeaderBytes,
 	}
+	// 明文部署（TLS 由前置代理终结）时可启用 h2c，与 HTTP/1.1 共用同一端口
+	if config.EnableH2C {
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
 
 	acmeCtx := acme.Setup(srv)
 	tlsFileCtx := tlsfile.Setup(srv)
+	tlsfile.SetupClientAuth(srv)
+	quicCtx := quic.Setup(srv)
 
-	// 监听停止信号（尽早注册，避免启动阶段收到信号时错过清理流程）
+	// 监听停止与升级信号（尽早注册，避免启动阶段收到信号时错过清理流程）
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+	signal.Notify(quit, append([]os.Signal{syscall.SIGTERM, syscall.SIGINT}, graceful.UpgradeSignals...)...)
+
+	// 监听端口：平滑升级启动时复用上一代进程移交的套接字
+	ln, err := graceful.Listen("http", srv.Addr)
+	if err != nil {
+		zap.L().Error("监听端口失败", zap.String("addr", srv.Addr), zap.Error(err))
+		log.StopMonitor()
+		ctx.Exit(1)
+	}
+	// 四层负载均衡通过 PROXY 协议传递客户端地址；graceful 保留原始监听器用于升级移交
+	ln = realip.WrapListener(ln)
+	var acmeLn net.Listener
+	if acmeCtx.Enabled && acmeCtx.HTTPServer != nil {
+		acmeLn, err = graceful.Listen("acme", acmeCtx.HTTPServer.Addr)
+		if err != nil {
+			zap.L().Error("ACME HTTP 挑战服务监听失败", zap.String("addr", acmeCtx.HTTPServer.Addr), zap.Error(err))
+			log.StopMonitor()
+			ctx.Exit(1)
+		}
+	}
+
+	var quicConn net.PacketConn
+	if quicCtx.Enabled {
+		quicConn, err = graceful.ListenPacket("http3", quicCtx.Server.Addr)
+		if err != nil {
+			zap.L().Error("HTTP/3 监听失败", zap.String("addr", quicCtx.Server.Addr), zap.Error(err))
+			log.StopMonitor()
+			ctx.Exit(1)
+		}
+	}
 
 	pidFilePath := config.PidFile
-	// 写入 pid 文件（存在则覆盖，确保每次启动都会刷新）
+	// 写入 pid 文件（存在则覆盖，确保每次启动与平滑升级后都会刷新）
 	if pidFilePath != "" {
 		pid := os.Getpid()
 		if err := pidfile.Write(pidFilePath, pid); err != nil {
@@ -147,24 +249,33 @@ func main() {
 		)
 	}
 
-	if acmeCtx.Enabled && acmeCtx.HTTPServer != nil {
+	if acmeLn != nil {
 		go func() {
 			zap.L().Info("ACME HTTP 挑战服务启动", zap.String("addr", acmeCtx.HTTPServer.Addr))
-			if err := acmeCtx.HTTPServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if err := acmeCtx.HTTPServer.Serve(acmeLn); err != nil && err != http.ErrServerClosed {
 				zap.L().Error("ACME HTTP 挑战服务异常退出", zap.Error(err))
 			}
 		}()
 	}
 
+	if quicConn != nil {
+		go func() {
+			zap.L().Info("HTTP/3 服务启动", zap.String("addr", quicCtx.Server.Addr))
+			if err := quicCtx.Server.Serve(quicConn); err != nil && err != http.ErrServerClosed {
+				zap.L().Error("HTTP/3 服务异常退出", zap.Error(err))
+			}
+		}()
+	}
+
 	serverErrCh := make(chan error, 1)
 	go func() {
 		var err error
 		if acmeCtx.Enabled || tlsFileCtx.Enabled {
 			zap.L().Info("HTTPS 服务启动中", zap.String("addr", srv.Addr))
-			err = srv.ListenAndServeTLS("", "")
+			err = srv.ServeTLS(ln, "", "")
 		} else {
 			zap.L().Info("HTTP 服务启动中", zap.String("addr", srv.Addr))
-			err = srv.ListenAndServe()
+			err = srv.Serve(ln)
 		}
 
 		if err != nil && err != http.ErrServerClosed {
@@ -172,19 +283,49 @@ func main() {
 		}
 	}()
 
+	// 由平滑升级启动时通知上一代进程：监听与 pid 文件已就绪，可以排空退出
+	if err := graceful.Ready(); err != nil {
+		zap.L().Warn("通知上一代进程就绪失败", zap.Error(err))
+	}
+
 	exitCode := 0
-	select {
-	case sig := <-quit:
-		zap.L().Info("接收到停止信号，开始优雅退出", zap.String("signal", sig.String()))
-	case err := <-serverErrCh:
-		exitCode = 1
-		zap.L().Error("HTTP 服务异常退出，开始执行清理与退出",
-			zap.Error(err),
-		)
+	upgraded := false
+wait:
+	for {
+		select {
+		case sig := <-quit:
+			if !graceful.IsUpgradeSignal(sig) {
+				zap.L().Info("接收到停止信号，开始优雅退出", zap.String("signal", sig.String()))
+				break wait
+			}
+			zap.L().Info("接收到升级信号，启动新进程并移交监听套接字", zap.String("signal", sig.String()))
+			pid, err := graceful.Upgrade(config.UpgradeTimeout)
+			if err != nil {
+				zap.L().Error("平滑升级失败，继续由当前进程提供服务", zap.Error(err))
+				continue
+			}
+			zap.L().Info("新进程已就绪，开始排空在途请求并退出", zap.Int("new_pid", pid))
+			upgraded = true
+			break wait
+		case err := <-serverErrCh:
+			exitCode = 1
+			zap.L().Error("HTTP 服务异常退出，开始执行清理与退出",
+				zap.Error(err),
+			)
+			break wait
+		}
 	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
 
+	// 平滑升级后 UDP 套接字与新进程共享，继续读取会抢走新进程连接的数据包，因此先于其它服务立即关闭；
+	// 旧进程上的 QUIC 连接由客户端重连到新进程
+	if quicCtx.Enabled && upgraded {
+		if err := quicCtx.Server.Close(); err != nil {
+			zap.L().Error("HTTP/3 服务关闭失败", zap.Error(err))
+		}
+	}
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		if !errors.Is(err, http.ErrServerClosed) {
 			zap.L().Error("HTTP 服务强制退出", zap.Error(err))
@@ -199,12 +340,18 @@ func main() {
 		}
 	}
 
+	if quicCtx.Enabled && !upgraded {
+		if err := quicCtx.Server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("HTTP/3 服务关闭失败", zap.Error(err))
+		}
+	}
+
 	middleware.CleanupAllLimiters()
 
 	log.StopMonitor()
 
-	// 删除 pid 文件（文件不存在视为成功）
-	if err := pidfile.Remove(pidFilePath); err != nil {
+	// 删除 pid 文件（文件不存在视为成功；平滑升级后文件已记录新进程 PID，予以保留）
+	if err := pidfile.RemoveIfOwner(pidFilePath, os.Getpid()); err != nil {
 		zap.L().Warn("删除 pid 文件失败",
 			zap.String("pid_file", pidFilePath),
 			zap.Error(err),
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/alecthomas/kong"
+	"go.uber.org/zap"
+
+	"api-server/db/pgdb"
+	"api-server/db/pgdb/migration"
+	"api-server/db/pgdb/system"
+)
+
+// MigrateCmd 数据库迁移子命令
+type MigrateCmd struct {
+	Up     MigrateUpCmd     `cmd:"" help:"执行全部未应用的迁移"`
+	Down   MigrateDownCmd   `cmd:"" help:"回滚最近的迁移"`
+	Status MigrateStatusCmd `cmd:"" help:"查看迁移状态"`
+	To     MigrateToCmd     `cmd:"" help:"迁移到指定版本（高于当前版本时执行，低于时回滚）"`
+}
+
+type MigrateUpCmd struct{}
+
+type MigrateDownCmd struct {
+	Steps int `help:"回滚的迁移数量" default:"1"`
+}
+
+type MigrateStatusCmd struct{}
+
+type MigrateToCmd struct {
+	Version int64 `arg:"" help:"目标版本，0 表示回滚全部迁移"`
+}
+
+func (c *MigrateUpCmd) Run(ctx context.Context, m *migration.Migrator) error {
+	done, err := m.Up(ctx)
+	printMigrations("已执行", done)
+	return err
+}
+
+func (c *MigrateDownCmd) Run(ctx context.Context, m *migration.Migrator) error {
+	done, err := m.Down(ctx, c.Steps)
+	printMigrations("已回滚", done)
+	return err
+}
+
+func (c *MigrateToCmd) Run(ctx context.Context, m *migration.Migrator) error {
+	done, err := m.To(ctx, c.Version)
+	printMigrations("已执行", done)
+	return err
+}
+
+func (c *MigrateStatusCmd) Run(ctx context.Context, m *migration.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if s.Unknown {
+			state = "applied (unknown)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+func printMigrations(action string, done []migration.Migration) {
+	if len(done) == 0 {
+		fmt.Println("没有需要执行的迁移")
+		return
+	}
+	for _, mig := range done {
+		fmt.Printf("%s %d_%s\n", action, mig.Version, mig.Name)
+	}
+}
+
+// runMigrate 执行 migrate 子命令；legacy 为 true 时对应已废弃的 --migrate 参数，等同于 migrate up。
+// 收到 SIGINT/SIGTERM 时取消正在执行的迁移（当前步骤的事务会回滚）。
+func runMigrate(kctx *kong.Context, legacy bool) error {
+	m, err := newMigrator()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if legacy {
+		zap.L().Warn("--migrate 参数已废弃，请使用 migrate up")
+		return (&MigrateUpCmd{}).Run(ctx, m)
+	}
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	return kctx.Run(m)
+}
+
+// newMigrator 使用主库连接构造迁移执行器
+func newMigrator() (*migration.Migrator, error) {
+	migrations, err := system.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	db := pgdb.GetClient()
+	if db == nil {
+		return nil, errors.New("数据库连接失败")
+	}
+	return migration.New(db, migrations)
+}
+
+// warnPendingMigrations 服务启动时提示未执行的迁移；迁移需通过 migrate 子命令显式执行
+func warnPendingMigrations(ctx context.Context) {
+	m, err := newMigrator()
+	if err != nil {
+		zap.L().Warn("检查数据库迁移状态失败", zap.Error(err))
+		return
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		zap.L().Warn("检查数据库迁移状态失败", zap.Error(err))
+		return
+	}
+	if pending > 0 {
+		zap.L().Warn("存在未执行的数据库迁移，请执行 migrate up", zap.Int("pending", pending))
+	}
+}
@@ -47,15 +47,15 @@ func PrepareRegisteredClaims(rc *jwt.RegisteredClaims) {
 	if rc.NotBefore == nil {
 		rc.NotBefore = jwt.NewNumericDate(now)
 	}
-	if rc.ExpiresAt == nil && config.JWTExpiration > 0 {
-		rc.ExpiresAt = jwt.NewNumericDate(now.Add(config.JWTExpiration))
+	if expiration := config.Current().JWTExpiration; rc.ExpiresAt == nil && expiration > 0 {
+		rc.ExpiresAt = jwt.NewNumericDate(now.Add(expiration))
 	}
 }
 
 // SignHS256 使用 HS256 对 claims 进行签名
 func SignHS256(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.JWTKey))
+	return token.SignedString([]byte(config.Current().JWTKey))
 }
 
 // ParseHS256 使用 HS256 验证并解析 token，结果写入传入的 claims
@@ -64,7 +64,7 @@ func ParseHS256(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(config.JWTKey), nil
+		return []byte(config.Current().JWTKey), nil
 	})
 }
 
@@ -0,0 +1,241 @@
+// Package graceful 实现不中断服务的二进制升级：收到升级信号后以相同参数启动新版本进程，
+// 通过继承的文件描述符移交正在监听的套接字，等待新进程就绪后由旧进程排空在途请求并退出。
+//
+// 移交约定（环境变量）：
+//   - API_SERVER_LISTENERS：继承的监听器名称，逗号分隔，按顺序对应文件描述符 3、4、…
+//   - API_SERVER_READY_FD：就绪通知管道的文件描述符，新进程就绪后写入一个字节
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	envListeners = "API_SERVER_LISTENERS"
+	envReadyFD   = "API_SERVER_READY_FD"
+
+	// firstInheritedFD exec.Cmd.ExtraFiles 中第一个文件在新进程中的描述符（0~2 为标准输入输出）
+	firstInheritedFD = 3
+)
+
+var (
+	mu        sync.Mutex
+	inherited = map[string]*os.File{} // 上一代进程移交、尚未被认领的套接字
+	readyFile *os.File
+	active    []namedConn // 当前进程登记的监听器，升级时按顺序移交
+)
+
+type namedConn struct {
+	name string
+	conn syscall.Conn // *net.TCPListener、*net.UDPConn 等可取得底层描述符的监听器
+}
+
+func init() {
+	names := os.Getenv(envListeners)
+	if names != "" {
+		for i, name := range strings.Split(names, ",") {
+			fd := firstInheritedFD + i
+			inherited[name] = os.NewFile(uintptr(fd), name)
+		}
+	}
+	if fd, err := strconv.Atoi(os.Getenv(envReadyFD)); err == nil {
+		readyFile = os.NewFile(uintptr(fd), "graceful-ready")
+	}
+	// 避免再次升级或派生的子进程误用本代的继承信息
+	_ = os.Unsetenv(envListeners)
+	_ = os.Unsetenv(envReadyFD)
+}
+
+// IsUpgradeSignal 判断信号是否用于触发平滑升级
+func IsUpgradeSignal(sig os.Signal) bool {
+	for _, s := range UpgradeSignals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// Inherited 当前进程是否由平滑升级启动
+func Inherited() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return readyFile != nil
+}
+
+// Listen 返回名为 name 的 TCP 监听器：上一代进程移交了同名套接字时直接复用，否则在 addr 上新建监听。
+// 返回的监听器会被登记，下次升级时移交给新进程。
+func Listen(name, addr string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		ln  net.Listener
+		err error
+	)
+	if f, ok := claim(name); ok {
+		ln, err = net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("use inherited listener %s: %w", name, err)
+		}
+		zap.L().Info("复用上一代进程移交的监听套接字", zap.String("name", name), zap.String("addr", ln.Addr().String()))
+	} else if ln, err = net.Listen("tcp", addr); err != nil {
+		return nil, err
+	}
+	if err := register(name, ln); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// ListenPacket 与 Listen 相同，用于 UDP 套接字（如 HTTP/3）
+func ListenPacket(name, addr string) (net.PacketConn, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		pc  net.PacketConn
+		err error
+	)
+	if f, ok := claim(name); ok {
+		pc, err = net.FilePacketConn(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("use inherited packet conn %s: %w", name, err)
+		}
+		zap.L().Info("复用上一代进程移交的监听套接字", zap.String("name", name), zap.String("addr", pc.LocalAddr().String()))
+	} else if pc, err = net.ListenPacket("udp", addr); err != nil {
+		return nil, err
+	}
+	if err := register(name, pc); err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+	return pc, nil
+}
+
+// claim 取出上一代进程移交的同名套接字（调用方需持有 mu）
+func claim(name string) (*os.File, bool) {
+	f, ok := inherited[name]
+	if ok {
+		delete(inherited, name)
+	}
+	return f, ok
+}
+
+// register 登记监听器以便升级时移交（调用方需持有 mu）
+func register(name string, l interface{}) error {
+	sc, ok := l.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("listener %s does not expose a file descriptor", name)
+	}
+	active = append(active, namedConn{name: name, conn: sc})
+	return nil
+}
+
+// Ready 通知上一代进程本进程已就绪，可以开始排空退出；非平滑升级启动时不做任何事。
+// 同时关闭未被认领的继承套接字（如新版本不再监听 ACME 挑战端口）。
+func Ready() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for name, f := range inherited {
+		zap.L().Warn("上一代进程移交的监听套接字未被使用，已关闭", zap.String("name", name))
+		_ = f.Close()
+		delete(inherited, name)
+	}
+	if readyFile == nil {
+		return nil
+	}
+	_, err := readyFile.Write([]byte{1})
+	_ = readyFile.Close()
+	readyFile = nil
+	return err
+}
+
+// Upgrade 以当前可执行文件（已被替换为新版本）和相同参数启动新进程并移交全部登记的监听器，
+// 在 timeout 内等待其调用 Ready。新进程启动失败、提前退出或超时未就绪时返回错误并终止新进程，
+// 当前进程继续提供服务。成功时返回新进程的 PID。
+func Upgrade(timeout time.Duration) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("resolve executable: %w", err)
+	}
+
+	conns := make([]syscall.Conn, 0, len(active))
+	names := make([]string, 0, len(active))
+	for _, l := range active {
+		conns = append(conns, l.conn)
+		names = append(names, l.name)
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("create ready pipe: %w", err)
+	}
+	defer readyR.Close()
+
+	argv := append([]string{exe}, os.Args[1:]...)
+	env := append(filterEnv(os.Environ()),
+		envListeners+"="+strings.Join(names, ","),
+		envReadyFD+"="+strconv.Itoa(firstInheritedFD+len(names)),
+	)
+	proc, err := spawn(argv, env, conns, readyW)
+	// 关闭本进程持有的管道写端，新进程退出时读端即可收到 EOF
+	_ = readyW.Close()
+	if err != nil {
+		return 0, fmt.Errorf("start new process: %w", err)
+	}
+
+	if err := waitReady(readyR, timeout); err != nil {
+		_ = proc.Kill()
+		_, _ = proc.Wait()
+		return 0, err
+	}
+	pid := proc.Pid
+	_ = proc.Release()
+	return pid, nil
+}
+
+func waitReady(r *os.File, timeout time.Duration) error {
+	if timeout > 0 {
+		if err := r.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return fmt.Errorf("set ready deadline: %w", err)
+		}
+	}
+	buf := make([]byte, 1)
+	if _, err := r.Read(buf); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return fmt.Errorf("new process not ready within %s", timeout)
+		}
+		return fmt.Errorf("new process exited before ready: %w", err)
+	}
+	return nil
+}
+
+// filterEnv 去掉本代的移交变量，由 Upgrade 重新设置
+func filterEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		if strings.HasPrefix(kv, envListeners+"=") || strings.HasPrefix(kv, envReadyFD+"=") {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out
+}
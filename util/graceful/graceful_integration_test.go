@@ -0,0 +1,177 @@
+package graceful
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upgradeToHelper 以 helper 模式触发升级：新进程只运行 TestGracefulHelperProcess
+func upgradeToHelper(t *testing.T, mode string, timeout time.Duration) (int, error) {
+	t.Helper()
+	t.Setenv("GRACEFUL_HELPER_PROCESS", mode)
+	args := os.Args
+	os.Args = []string{args[0], "-test.run", "^TestGracefulHelperProcess$"}
+	defer func() { os.Args = args }()
+	return Upgrade(timeout)
+}
+
+func TestUpgradeHandsOffListener_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过集成测试（-short）")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持继承监听套接字")
+	}
+
+	ln, err := Listen("http", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		active = nil
+		mu.Unlock()
+	})
+	pc, err := ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "parent")
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	addr := "http://" + ln.Addr().String()
+
+	pid, err := upgradeToHelper(t, "serve", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if p, err := os.FindProcess(pid); err == nil {
+			_ = p.Kill()
+		}
+	})
+
+	// 旧进程关闭自身监听后，同一地址的新连接由新进程接受；关闭不应被新进程阻塞
+	closed := make(chan struct{})
+	go func() {
+		_ = srv.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("closing parent listener blocked after upgrade")
+	}
+	client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(addr)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", addr, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if got := strings.TrimSpace(string(body)); got != "child" {
+		t.Fatalf("response = %q, want %q", got, "child")
+	}
+
+	// UDP 套接字同样移交：旧进程关闭后由新进程应答
+	udpAddr := pc.LocalAddr().String()
+	_ = pc.Close()
+	conn, err := net.Dial("udp", udpAddr)
+	if err != nil {
+		t.Fatalf("Dial udp error = %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("udp write error = %v", err)
+	}
+	buf := make([]byte, 16)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "child" {
+		t.Fatalf("udp response = %q, %v, want %q", buf[:n], err, "child")
+	}
+}
+
+func TestUpgradeFailsWhenChildExitsBeforeReady_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过集成测试（-short）")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持继承监听套接字")
+	}
+
+	if _, err := upgradeToHelper(t, "exit", 10*time.Second); err == nil {
+		t.Fatal("Upgrade() expected error when new process exits before ready")
+	}
+}
+
+func TestUpgradeTimesOut_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过集成测试（-short）")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持继承监听套接字")
+	}
+
+	_, err := upgradeToHelper(t, "hang", 500*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not ready within") {
+		t.Fatalf("Upgrade() error = %v, want timeout", err)
+	}
+}
+
+// TestGracefulHelperProcess 不是实际测试，仅作为升级后的新进程运行
+func TestGracefulHelperProcess(t *testing.T) {
+	mode := os.Getenv("GRACEFUL_HELPER_PROCESS")
+	if mode == "" {
+		return
+	}
+	switch mode {
+	case "exit":
+		os.Exit(1)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	if !Inherited() {
+		os.Exit(2)
+	}
+	ln, err := Listen("http", "")
+	if err != nil {
+		os.Exit(3)
+	}
+	if _, ok := ln.Addr().(*net.TCPAddr); !ok {
+		os.Exit(4)
+	}
+	pc, err := ListenPacket("udp", "")
+	if err != nil {
+		os.Exit(6)
+	}
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			_, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = pc.WriteTo([]byte("child"), addr)
+		}
+	}()
+	go func() {
+		_ = http.Serve(ln, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "child")
+		}))
+	}()
+	if err := Ready(); err != nil {
+		os.Exit(5)
+	}
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package graceful
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// UpgradeSignals 触发平滑升级的信号；非 Unix 平台不支持套接字移交
+var UpgradeSignals []os.Signal
+
+func spawn([]string, []string, []syscall.Conn, *os.File) (*os.Process, error) {
+	return nil, errors.New("graceful upgrade is not supported on this platform")
+}
@@ -0,0 +1,59 @@
+//go:build unix
+
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// UpgradeSignals 触发平滑升级的信号
+var UpgradeSignals = []os.Signal{syscall.SIGUSR2, syscall.SIGHUP}
+
+// spawn 启动新进程，监听套接字依次成为其文件描述符 3、4、…，ready 紧随其后。
+// 不使用 exec.Cmd.ExtraFiles：其内部调用 File.Fd() 会把套接字切换为阻塞模式，
+// 而文件状态标志由新旧进程共享，旧进程的 Accept 将因此阻塞、无法正常关闭。
+func spawn(argv, env []string, listeners []syscall.Conn, ready *os.File) (*os.Process, error) {
+	syscall.ForkLock.RLock()
+	dups := make([]int, 0, len(listeners))
+	defer func() {
+		for _, fd := range dups {
+			_ = syscall.Close(fd)
+		}
+	}()
+	for _, l := range listeners {
+		raw, err := l.SyscallConn()
+		if err != nil {
+			syscall.ForkLock.RUnlock()
+			return nil, err
+		}
+		var dupErr error
+		if err := raw.Control(func(fd uintptr) {
+			var nfd int
+			if nfd, dupErr = syscall.Dup(int(fd)); dupErr == nil {
+				syscall.CloseOnExec(nfd)
+				dups = append(dups, nfd)
+			}
+		}); err != nil {
+			dupErr = err
+		}
+		if dupErr != nil {
+			syscall.ForkLock.RUnlock()
+			return nil, fmt.Errorf("dup listener: %w", dupErr)
+		}
+	}
+	syscall.ForkLock.RUnlock()
+
+	files := []uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd()}
+	for _, fd := range dups {
+		files = append(files, uintptr(fd))
+	}
+	files = append(files, ready.Fd())
+
+	pid, err := syscall.ForkExec(argv[0], argv, &syscall.ProcAttr{Env: env, Files: files})
+	if err != nil {
+		return nil, err
+	}
+	return os.FindProcess(pid)
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type requestIDContextKey struct{}
+
+// WithRequestID 将请求 ID 写入 context.Context，供数据库、Redis 等下游日志关联请求
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext 从 context.Context 中读取请求 ID；传入 *gin.Context 时读取其 Request 的 context
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if gc, ok := ctx.(*gin.Context); ok {
+		if gc.Request == nil {
+			return ""
+		}
+		ctx = gc.Request.Context()
+	}
+	if requestID, ok := ctx.Value(requestIDContextKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// HTTPFormatLoki Loki push API（/loki/api/v1/push）格式
+	HTTPFormatLoki = "loki"
+	// HTTPFormatElasticsearch Elasticsearch _bulk API 格式（NDJSON，逐条附带 index 动作行）
+	HTTPFormatElasticsearch = "elasticsearch"
+)
+
+// httpTransport 将日志批量推送到 Loki / Elasticsearch 兼容的 HTTP 端点
+type httpTransport struct {
+	url     string
+	format  string
+	labels  map[string]string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPTransport(url, format string, labels, headers map[string]string, timeout time.Duration) *httpTransport {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if format == "" {
+		format = HTTPFormatLoki
+	}
+	return &httpTransport{
+		url:     url,
+		format:  format,
+		labels:  labels,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *httpTransport) send(batch []sinkEntry) error {
+	body, contentType, err := t.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) encode(batch []sinkEntry) ([]byte, string, error) {
+	switch t.format {
+	case HTTPFormatElasticsearch:
+		var buf bytes.Buffer
+		for _, entry := range batch {
+			buf.WriteString(`{"index":{}}`)
+			buf.WriteByte('\n')
+			buf.Write(entry.Line)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+	case HTTPFormatLoki:
+		labels := t.labels
+		if len(labels) == 0 {
+			labels = map[string]string{"app": "api-server"}
+		}
+		values := make([][2]string, 0, len(batch))
+		for _, entry := range batch {
+			values = append(values, [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(entry.Line)})
+		}
+		payload := map[string]interface{}{
+			"streams": []map[string]interface{}{
+				{"stream": labels, "values": values},
+			},
+		}
+		body, err := json.Marshal(payload)
+		return body, "application/json", err
+	default:
+		return nil, "", fmt.Errorf("unsupported log http format %q", t.format)
+	}
+}
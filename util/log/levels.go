@@ -0,0 +1,290 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"api-server/config"
+	runmodel "api-server/util/run-model"
+)
+
+// 具名 logger，每个名称拥有独立的 zap.AtomicLevel（原子日志级别），可在运行时单独调整
+const (
+	LoggerBusiness = "business" // 业务日志（zap.L() 全局 logger）
+	LoggerGin      = "gin"      // gin 框架日志
+	LoggerGorm     = "gorm"     // GORM SQL 日志
+	LoggerAccess   = "access"   // HTTP 访问日志
+
+	// defaultLevelKey log.level 下对所有 logger 生效的默认级别键
+	defaultLevelKey = "default"
+)
+
+// LoggerNames 所有支持动态调整级别的 logger 名称
+var LoggerNames = []string{LoggerBusiness, LoggerGin, LoggerGorm, LoggerAccess}
+
+type levelState struct {
+	level         zap.AtomicLevel
+	configured    zapcore.Level
+	overrideUntil time.Time
+	timer         *time.Timer
+}
+
+// LevelStatus 具名 logger 当前级别状态
+type LevelStatus struct {
+	Name            string
+	Level           zapcore.Level
+	ConfiguredLevel zapcore.Level
+	OverrideUntil   time.Time // 零值表示当前未被临时覆盖
+}
+
+var (
+	levelMu     sync.Mutex
+	levelStates = newLevelStates()
+)
+
+func newLevelStates() map[string]*levelState {
+	states := make(map[string]*levelState, len(LoggerNames))
+	for _, name := range LoggerNames {
+		states[name] = &levelState{
+			level:      zap.NewAtomicLevelAt(zap.InfoLevel),
+			configured: zap.InfoLevel,
+		}
+	}
+	return states
+}
+
+// defaultLevel 未配置 log.level 时的默认级别：生产模式 Info，其它模式 Debug
+func defaultLevel() zapcore.Level {
+	if runmodel.IsRelease() {
+		return zap.InfoLevel
+	}
+	return zap.DebugLevel
+}
+
+// ParseLevel 解析日志级别字符串（debug/info/warn/error/dpanic/panic/fatal）
+func ParseLevel(text string) (zapcore.Level, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(text)))); err != nil {
+		return level, fmt.Errorf("invalid log level %q: %w", text, err)
+	}
+	return level, nil
+}
+
+// configuredLevel 计算某个 logger 在 config.LogLevels 中的级别：具名配置 > default > 运行模式默认值
+func configuredLevel(name string) zapcore.Level {
+	raw := config.LogLevels[name]
+	if raw == "" {
+		raw = config.LogLevels[defaultLevelKey]
+	}
+	if raw == "" {
+		return defaultLevel()
+	}
+	level, err := ParseLevel(raw)
+	if err != nil {
+		zap.L().Warn("日志级别配置无效，使用默认级别",
+			zap.String("logger", name),
+			zap.String("level", raw),
+			zap.Error(err),
+		)
+		return defaultLevel()
+	}
+	return level
+}
+
+// ApplyLevels 按 config.LogLevels 刷新所有具名 logger 的级别。
+// 处于临时覆盖期间的 logger 仅更新“配置级别”，到期后回落到最新的配置值。
+func ApplyLevels() {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	for name, state := range levelStates {
+		state.configured = configuredLevel(name)
+		if state.overrideUntil.IsZero() {
+			state.level.SetLevel(state.configured)
+		}
+	}
+}
+
+// AtomicLevel 返回具名 logger 的原子级别；未知名称返回 false
+func AtomicLevel(name string) (zap.AtomicLevel, bool) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	state, ok := levelStates[name]
+	if !ok {
+		return zap.AtomicLevel{}, false
+	}
+	return state.level, true
+}
+
+// SetLevelTemporarily 临时调整具名 logger 的级别，duration 到期后自动恢复为配置级别
+func SetLevelTemporarily(name string, level zapcore.Level, duration time.Duration) (LevelStatus, error) {
+	if duration <= 0 {
+		return LevelStatus{}, fmt.Errorf("override duration must be positive")
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	state, ok := levelStates[name]
+	if !ok {
+		return LevelStatus{}, fmt.Errorf("unknown logger %q", name)
+	}
+	if state.timer != nil {
+		state.timer.Stop()
+	}
+
+	until := time.Now().Add(duration)
+	state.level.SetLevel(level)
+	state.overrideUntil = until
+	state.timer = time.AfterFunc(duration, func() {
+		revertLevel(name, until)
+	})
+
+	zap.L().Warn("日志级别已临时调整",
+		zap.String("logger", name),
+		zap.String("level", level.String()),
+		zap.Time("revert_at", until),
+	)
+	return statusLocked(name, state), nil
+}
+
+// ResetLevel 立即撤销临时覆盖，恢复为配置级别
+func ResetLevel(name string) (LevelStatus, error) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	state, ok := levelStates[name]
+	if !ok {
+		return LevelStatus{}, fmt.Errorf("unknown logger %q", name)
+	}
+	resetLocked(state)
+	return statusLocked(name, state), nil
+}
+
+// revertLevel 定时器到期回调；仅当覆盖未被更新的覆盖替换时才恢复
+func revertLevel(name string, until time.Time) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	state, ok := levelStates[name]
+	if !ok || !state.overrideUntil.Equal(until) {
+		return
+	}
+	resetLocked(state)
+	zap.L().Info("日志级别临时调整已到期，恢复为配置级别",
+		zap.String("logger", name),
+		zap.String("level", state.configured.String()),
+	)
+}
+
+func resetLocked(state *levelState) {
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+	state.overrideUntil = time.Time{}
+	state.level.SetLevel(state.configured)
+}
+
+// ListLevels 返回所有具名 logger 的级别状态（按名称排序）
+func ListLevels() []LevelStatus {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	result := make([]LevelStatus, 0, len(levelStates))
+	for name, state := range levelStates {
+		result = append(result, statusLocked(name, state))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func statusLocked(name string, state *levelState) LevelStatus {
+	return LevelStatus{
+		Name:            name,
+		Level:           state.level.Level(),
+		ConfiguredLevel: state.configured,
+		OverrideUntil:   state.overrideUntil,
+	}
+}
+
+// baseCoreRef 当前的底层 core；SetLogger 每次重建时整体替换，旧引用仍可安全读取
+type baseCoreRef struct {
+	core zapcore.Core
+}
+
+var currentBase atomic.Pointer[baseCoreRef]
+
+// setBaseCore 替换底层 core，已创建的具名 logger 在下一条日志时切换到新 core
+func setBaseCore(core zapcore.Core) {
+	currentBase.Store(&baseCoreRef{core: core})
+}
+
+// boundCore 基于某一代底层 core、附加了 With 字段的 core
+type boundCore struct {
+	base *baseCoreRef
+	core zapcore.Core
+}
+
+// levelCore 在共享底层 core 之上按具名 logger 的原子级别过滤日志。
+// 底层 core 以 Debug 级别构建，真正的级别判断由这里完成；底层 core 每次写入时读取当前值，
+// 日志文件被删除或重命名后 SetLogger 重建的 core 对启动时创建的具名 logger（access、gorm、gin）同样生效。
+type levelCore struct {
+	level  zap.AtomicLevel
+	fields []zapcore.Field
+	bound  atomic.Pointer[boundCore]
+}
+
+func newLevelCore(level zap.AtomicLevel) zapcore.Core {
+	return &levelCore{level: level}
+}
+
+// core 返回附加了 With 字段的当前底层 core，底层 core 未变化时复用上次的结果
+func (c *levelCore) core() zapcore.Core {
+	base := currentBase.Load()
+	if base == nil {
+		return zapcore.NewNopCore()
+	}
+	if b := c.bound.Load(); b != nil && b.base == base {
+		return b.core
+	}
+	core := base.core
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+	c.bound.Store(&boundCore{base: base, core: core})
+	return core
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &levelCore{level: c.level, fields: merged}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.core().Check(ent, ce)
+}
+
+func (c *levelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(ent, fields)
+}
+
+func (c *levelCore) Sync() error {
+	return c.core().Sync()
+}
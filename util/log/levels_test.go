@@ -0,0 +1,91 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"api-server/config"
+)
+
+func TestApplyLevels(t *testing.T) {
+	config.LogLevels = map[string]string{"default": "warn", "gorm": "error"}
+	t.Cleanup(func() { config.LogLevels = map[string]string{} })
+
+	ApplyLevels()
+
+	want := map[string]string{
+		LoggerBusiness: "warn",
+		LoggerGin:      "warn",
+		LoggerGorm:     "error",
+		LoggerAccess:   "warn",
+	}
+	for _, status := range ListLevels() {
+		if got := status.Level.String(); got != want[status.Name] {
+			t.Errorf("%s level = %s, want %s", status.Name, got, want[status.Name])
+		}
+	}
+}
+
+func TestSetLevelTemporarily_Reverts(t *testing.T) {
+	config.LogLevels = map[string]string{"default": "info"}
+	t.Cleanup(func() { config.LogLevels = map[string]string{} })
+	ApplyLevels()
+
+	status, err := SetLevelTemporarily(LoggerGin, zap.DebugLevel, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SetLevelTemporarily() error = %v", err)
+	}
+	if status.Level != zap.DebugLevel || status.OverrideUntil.IsZero() {
+		t.Fatalf("unexpected status after override: %+v", status)
+	}
+
+	// 覆盖期间的配置热加载不应打断临时调整
+	config.LogLevels = map[string]string{"default": "warn"}
+	ApplyLevels()
+	level, _ := AtomicLevel(LoggerGin)
+	if level.Level() != zap.DebugLevel {
+		t.Fatalf("override level = %s, want debug", level.Level())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if level.Level() != zap.WarnLevel {
+		t.Fatalf("level after revert = %s, want warn", level.Level())
+	}
+}
+
+func TestSetLevelTemporarily_UnknownLogger(t *testing.T) {
+	if _, err := SetLevelTemporarily("unknown", zap.DebugLevel, time.Minute); err == nil {
+		t.Fatalf("expected error for unknown logger")
+	}
+}
+
+// TestNamedLoggerFollowsBaseCore 验证启动时创建的具名 logger 在底层 core 重建后写入新 core
+func TestNamedLoggerFollowsBaseCore(t *testing.T) {
+	prev := currentBase.Load()
+	t.Cleanup(func() { currentBase.Store(prev) })
+
+	oldCore, oldLogs := observer.New(zapcore.DebugLevel)
+	setBaseCore(oldCore)
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	named := zap.New(newLevelCore(level)).With(zap.String("logger", LoggerAccess))
+
+	named.Info("before")
+	newCore, newLogs := observer.New(zapcore.DebugLevel)
+	setBaseCore(newCore)
+	named.Info("after")
+	named.Debug("filtered")
+
+	if oldLogs.Len() != 1 || oldLogs.All()[0].Message != "before" {
+		t.Fatalf("old core logs = %v, want only \"before\"", oldLogs.All())
+	}
+	if newLogs.Len() != 1 || newLogs.All()[0].Message != "after" {
+		t.Fatalf("new core logs = %v, want only \"after\"", newLogs.All())
+	}
+	if got := newLogs.All()[0].ContextMap()["logger"]; got != LoggerAccess {
+		t.Fatalf("logger field = %v, want %q", got, LoggerAccess)
+	}
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"strings"
@@ -25,45 +26,89 @@ var (
 // 目前由 middleware.CheckParam 写入，WithRequest 读取，仅用于日志记录。
 const BoundParamsKey = "__bound_params__"
 
-func createDevLogger() *zap.Logger {
+// withSampler 按配置为 core 包裹采样器（每个 tick 内同一消息先记录 initial 条，之后每 thereafter 条记录 1 条）
+func withSampler(core zapcore.Core) zapcore.Core {
+	if !config.LogSamplingEnabled {
+		return core
+	}
+	tick := config.LogSamplingTick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return zapcore.NewSamplerWithOptions(core, tick, config.LogSamplingInitial, config.LogSamplingThereafter)
+}
+
+// 底层 core 统一以 Debug 级别构建，由 levelCore 按具名 logger 的原子级别过滤
+func createDevCore() zapcore.Core {
 	encoder := zap.NewDevelopmentEncoderConfig()
-	core := zapcore.NewTee(
-		zapcore.NewSamplerWithOptions(
-			zapcore.NewCore(zapcore.NewConsoleEncoder(encoder), os.Stdout, zap.DebugLevel), time.Second, 4, 1),
+	return zapcore.NewTee(
+		withSampler(zapcore.NewCore(zapcore.NewConsoleEncoder(encoder), os.Stdout, zap.DebugLevel)),
 	)
-	return zap.New(core, zap.AddCaller())
 }
 
-func createProductLogger(fileName string) *zap.Logger {
+func createProductCore(fileName string) zapcore.Core {
 	fileEncoder := zap.NewProductionEncoderConfig()
 	fileEncoder.EncodeTime = zapcore.ISO8601TimeEncoder
-	fileWriter := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   fileName,
-		MaxSize:    config.LogMaxSize,
-		MaxBackups: config.LogMaxBackups,
-		MaxAge:     config.LogMaxAge,
-	})
-	core := zapcore.NewTee(
-		zapcore.NewSamplerWithOptions(
-			zapcore.NewCore(zapcore.NewJSONEncoder(fileEncoder), fileWriter, zap.InfoLevel), time.Second, 4, 1),
-	)
-	return zap.New(core, zap.AddCaller())
+
+	cores := make([]zapcore.Core, 0, len(config.LogSinks))
+	for _, name := range config.LogSinks {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case SinkFile:
+			fileWriter := zapcore.AddSync(&lumberjack.Logger{
+				Filename:   fileName,
+				MaxSize:    config.LogMaxSize,
+				MaxBackups: config.LogMaxBackups,
+				MaxAge:     config.LogMaxAge,
+			})
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(fileEncoder), fileWriter, zap.DebugLevel))
+		case SinkStdout:
+			// 容器环境下由采集端解析 stdout 的 JSON 行
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(fileEncoder), zapcore.Lock(os.Stdout), zap.DebugLevel))
+		case SinkSyslog, SinkHTTP:
+			if sink := remoteSink(name); sink != nil {
+				cores = append(cores, newRemoteCore(zapcore.NewJSONEncoder(fileEncoder), sink, zap.DebugLevel))
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "unknown log sink %q ignored\n", name)
+		}
+	}
+	if len(cores) == 0 {
+		// 未配置任何可用输出时退回 stdout，避免日志静默丢失
+		cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(fileEncoder), zapcore.Lock(os.Stdout), zap.DebugLevel))
+	}
+	return withSampler(zapcore.NewTee(cores...))
 }
 
 // SetLogger 根据运行模式初始化 logger
 func SetLogger() {
 	switch {
 	case runmodel.IsDev():
-		logger = createDevLogger()
+		setBaseCore(createDevCore())
 	case runmodel.IsRelease():
-		logger = createProductLogger(config.LogPath)
+		setBaseCore(createProductCore(config.LogPath))
 	default:
 		// 默认按开发模式处理，避免测试/包初始化阶段创建文件与目录
-		logger = createDevLogger()
+		setBaseCore(createDevCore())
 	}
+	ApplyLevels()
+	level, _ := AtomicLevel(LoggerBusiness)
+	logger = zap.New(newLevelCore(level), zap.AddCaller())
 	zap.ReplaceGlobals(logger)
 }
 
+// Named 返回具名 logger（gin、gorm、business），其级别由对应的 zap.AtomicLevel 独立控制；
+// 底层输出随 SetLogger 重建自动切换，可在启动时创建后长期持有
+func Named(name string) *zap.Logger {
+	if logger == nil {
+		SetLogger()
+	}
+	level, ok := AtomicLevel(name)
+	if !ok {
+		return logger.With(zap.String("logger", name))
+	}
+	return zap.New(newLevelCore(level), zap.AddCaller()).With(zap.String("logger", name))
+}
+
 func monitorFile() {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -124,9 +169,9 @@ func NewZapWriter(l *zap.Logger, level zapcore.Level) *zapWriter {
 	}
 }
 
-// StartMonitor 仅在生产环境监控日志文件
+// StartMonitor 仅在生产环境且启用文件输出时监控日志文件
 func StartMonitor() {
-	if runmodel.IsRelease() {
+	if runmodel.IsRelease() && FileSinkEnabled() {
 		monitorDone = make(chan struct{})
 		go monitorFile()
 	}
@@ -140,6 +185,7 @@ func StopMonitor() {
 	if logger != nil {
 		_ = logger.Sync()
 	}
+	closeRemoteSinks()
 }
 
 // FromContext 提供带 request 信息的 logger
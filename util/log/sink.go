@@ -0,0 +1,326 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"api-server/config"
+)
+
+const (
+	// defaultSinkBufferSize 远程日志异步队列默认容量（条）
+	defaultSinkBufferSize = 10000
+	// sinkSyncTimeout Sync 等待队列刷新的最长时间，避免远端不可用时阻塞退出流程
+	sinkSyncTimeout = 5 * time.Second
+)
+
+// SinkStats 日志输出目标的运行统计
+type SinkStats struct {
+	Name    string
+	Queued  int
+	Dropped uint64
+	Failed  uint64
+}
+
+// sinkEntry 已编码的单条日志，保留时间与级别供 syslog 优先级、Loki 时间戳使用
+type sinkEntry struct {
+	Time  time.Time
+	Level zapcore.Level
+	Line  []byte
+}
+
+// partialFlushError 批次中只有部分日志发送失败（如 syslog 逐条写入），Failed 为未写入的条数；
+// flush 返回其它错误时视为整批失败
+type partialFlushError struct {
+	Failed int
+	Err    error
+}
+
+func (e *partialFlushError) Error() string {
+	return fmt.Sprintf("%d messages not written: %v", e.Failed, e.Err)
+}
+
+func (e *partialFlushError) Unwrap() error {
+	return e.Err
+}
+
+// asyncSink 为远程日志输出（syslog、HTTP 推送）提供带缓冲的异步写入：
+// 队列满时直接丢弃新日志（drop-on-overload），保证业务请求不会被日志阻塞。
+type asyncSink struct {
+	name      string
+	queue     chan sinkEntry
+	flushReq  chan chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	batchSize int
+	interval  time.Duration
+	flush     func(batch []sinkEntry) error
+	closed    atomic.Bool
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+}
+
+func newAsyncSink(name string, bufferSize, batchSize int, interval time.Duration, flush func(batch []sinkEntry) error) *asyncSink {
+	if bufferSize <= 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	s := &asyncSink{
+		name:      name,
+		queue:     make(chan sinkEntry, bufferSize),
+		flushReq:  make(chan chan struct{}),
+		done:      make(chan struct{}),
+		batchSize: batchSize,
+		interval:  interval,
+		flush:     flush,
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// enqueue 将日志放入队列；队列已满或 sink 已关闭时丢弃并计数
+func (s *asyncSink) enqueue(entry sinkEntry) {
+	if s.closed.Load() {
+		s.dropped.Add(1)
+		return
+	}
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Sync 请求后台协程立即发送队列中的日志，最多等待 sinkSyncTimeout
+func (s *asyncSink) Sync() error {
+	if s.closed.Load() {
+		return nil
+	}
+	reply := make(chan struct{})
+	select {
+	case s.flushReq <- reply:
+	case <-s.done:
+		return nil
+	case <-time.After(sinkSyncTimeout):
+		return fmt.Errorf("log sink %s sync timeout", s.name)
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-time.After(sinkSyncTimeout):
+		return fmt.Errorf("log sink %s sync timeout", s.name)
+	}
+}
+
+// Close 刷新剩余日志并停止后台协程
+func (s *asyncSink) Close() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *asyncSink) stats() SinkStats {
+	return SinkStats{
+		Name:    s.name,
+		Queued:  len(s.queue),
+		Dropped: s.dropped.Load(),
+		Failed:  s.failed.Load(),
+	}
+}
+
+func (s *asyncSink) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	batch := make([]sinkEntry, 0, s.batchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.flush(batch); err != nil {
+			failed := len(batch)
+			var partial *partialFlushError
+			if errors.As(err, &partial) {
+				failed = partial.Failed
+			}
+			s.failed.Add(uint64(failed))
+			// 日志管道自身出错时不能再写回 zap，避免递归
+			fmt.Fprintf(os.Stderr, "log sink %s flush failed: %v\n", s.name, err)
+		}
+		batch = make([]sinkEntry, 0, s.batchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case msg := <-s.queue:
+				batch = append(batch, msg)
+				if len(batch) >= s.batchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case msg := <-s.queue:
+			batch = append(batch, msg)
+			if len(batch) >= s.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case reply := <-s.flushReq:
+			drain()
+			close(reply)
+		case <-s.done:
+			drain()
+			return
+		}
+	}
+}
+
+// remoteCore 将日志编码为 JSON 行后交给 asyncSink 异步发送
+type remoteCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	sink *asyncSink
+}
+
+func newRemoteCore(enc zapcore.Encoder, sink *asyncSink, enab zapcore.LevelEnabler) zapcore.Core {
+	return &remoteCore{LevelEnabler: enab, enc: enc, sink: sink}
+}
+
+func (c *remoteCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &remoteCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), sink: c.sink}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+func (c *remoteCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *remoteCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	line := bytes.TrimRight(buf.Bytes(), "\n")
+	copied := make([]byte, len(line))
+	copy(copied, line)
+	buf.Free()
+
+	c.sink.enqueue(sinkEntry{Time: ent.Time, Level: ent.Level, Line: copied})
+	return nil
+}
+
+func (c *remoteCore) Sync() error {
+	return c.sink.Sync()
+}
+
+// 日志输出目标名称（log.sinks）
+const (
+	SinkFile   = "file"
+	SinkStdout = "stdout"
+	SinkSyslog = "syslog"
+	SinkHTTP   = "http"
+)
+
+var (
+	remoteMu    sync.Mutex
+	remoteSinks = map[string]*asyncSink{}
+)
+
+// FileSinkEnabled 是否启用了文件输出（决定是否创建日志目录、监控日志文件）
+func FileSinkEnabled() bool {
+	for _, name := range config.LogSinks {
+		if strings.EqualFold(strings.TrimSpace(name), SinkFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteSink 返回远程输出对应的 asyncSink。
+// 同一进程内只创建一次：日志文件变更触发 SetLogger 重建底层 core 时复用它，
+// 不会重复建立连接与后台协程。
+func remoteSink(name string) *asyncSink {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+
+	if sink, ok := remoteSinks[name]; ok {
+		return sink
+	}
+
+	var sink *asyncSink
+	switch name {
+	case SinkSyslog:
+		transport := newSyslogTransport(config.LogSyslogNetwork, config.LogSyslogAddress, config.LogSyslogAppName, config.LogSyslogFacility)
+		sink = newAsyncSink(name, config.LogBufferSize, 100, time.Second, transport.send)
+	case SinkHTTP:
+		if config.LogHTTPURL == "" {
+			fmt.Fprintln(os.Stderr, "log sink http enabled but log.http.url is empty, ignored")
+			return nil
+		}
+		transport := newHTTPTransport(config.LogHTTPURL, config.LogHTTPFormat, config.LogHTTPLabels, config.LogHTTPHeaders, config.LogHTTPTimeout)
+		sink = newAsyncSink(name, config.LogBufferSize, config.LogHTTPBatchSize, config.LogHTTPFlushInterval, transport.send)
+	default:
+		return nil
+	}
+	remoteSinks[name] = sink
+	return sink
+}
+
+// SinkStatsList 返回所有远程输出的队列与丢弃统计（按名称排序）
+func SinkStatsList() []SinkStats {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+
+	result := make([]SinkStats, 0, len(remoteSinks))
+	for _, sink := range remoteSinks {
+		result = append(result, sink.stats())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// closeRemoteSinks 进程退出前发送剩余日志并关闭远程输出
+func closeRemoteSinks() {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+
+	for name, sink := range remoteSinks {
+		_ = sink.Close()
+		delete(remoteSinks, name)
+	}
+}
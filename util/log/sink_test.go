@@ -0,0 +1,144 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAsyncSinkDropsWhenFull(t *testing.T) {
+	block := make(chan struct{})
+	sink := newAsyncSink("test", 2, 1, time.Hour, func(batch []sinkEntry) error {
+		<-block
+		return nil
+	})
+
+	// 第一条被后台协程取走并阻塞在 flush 中，随后 2 条填满队列，其余被丢弃
+	for i := 0; i < 10; i++ {
+		sink.enqueue(sinkEntry{Time: time.Now(), Line: []byte("x")})
+		if i == 0 {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	stats := sink.stats()
+	if stats.Dropped != 7 {
+		t.Fatalf("dropped = %d, want 7", stats.Dropped)
+	}
+	close(block)
+	_ = sink.Close()
+}
+
+func TestRemoteCoreHTTPLokiPush(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		payload struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+		authHeader string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		authHeader = r.Header.Get("X-Scope-OrgID")
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	transport := newHTTPTransport(server.URL, HTTPFormatLoki, map[string]string{"app": "test"}, map[string]string{"X-Scope-OrgID": "tenant-a"}, time.Second)
+	sink := newAsyncSink(SinkHTTP, 10, 10, time.Hour, transport.send)
+	core := newRemoteCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), sink, zap.DebugLevel)
+	logger := zap.New(core)
+
+	logger.Info("hello", zap.String("k", "v"))
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	_ = sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if authHeader != "tenant-a" {
+		t.Fatalf("header = %q, want tenant-a", authHeader)
+	}
+	if len(payload.Streams) != 1 || payload.Streams[0].Stream["app"] != "test" {
+		t.Fatalf("unexpected streams: %+v", payload.Streams)
+	}
+	values := payload.Streams[0].Values
+	if len(values) != 1 || !strings.Contains(values[0][1], `"msg":"hello"`) || !strings.Contains(values[0][1], `"k":"v"`) {
+		t.Fatalf("unexpected values: %+v", values)
+	}
+}
+
+func TestFormatRFC5424(t *testing.T) {
+	entry := sinkEntry{
+		Time:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level: zapcore.WarnLevel,
+		Line:  []byte(`{"msg":"hi"}`),
+	}
+	got := formatRFC5424(16, "host", "api-server", 42, entry)
+	want := `<132>1 2024-01-02T03:04:05.000000Z host api-server 42 - - {"msg":"hi"}`
+	if got != want {
+		t.Fatalf("formatRFC5424 = %q, want %q", got, want)
+	}
+}
+
+func TestAsyncSinkCountsOnlyUnwrittenMessages(t *testing.T) {
+	sink := newAsyncSink("test", 10, 3, time.Hour, func(batch []sinkEntry) error {
+		return &partialFlushError{Failed: 1, Err: io.ErrClosedPipe}
+	})
+	for i := 0; i < 3; i++ {
+		sink.enqueue(sinkEntry{Time: time.Now(), Line: []byte("x")})
+	}
+	_ = sink.Close()
+	if got := sink.stats().Failed; got != 1 {
+		t.Fatalf("failed = %d, want 1", got)
+	}
+
+	sink = newAsyncSink("test", 10, 3, time.Hour, func(batch []sinkEntry) error {
+		return io.ErrClosedPipe
+	})
+	for i := 0; i < 3; i++ {
+		sink.enqueue(sinkEntry{Time: time.Now(), Line: []byte("x")})
+	}
+	_ = sink.Close()
+	if got := sink.stats().Failed; got != 3 {
+		t.Fatalf("failed = %d, want 3 for whole-batch error", got)
+	}
+}
+
+func TestSyslogSendStopsBatchAfterFailedDial(t *testing.T) {
+	transport := newSyslogTransport("tcp", "127.0.0.1:514", "api-server", 16)
+	dials := 0
+	transport.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dials++
+		return nil, io.ErrClosedPipe
+	}
+
+	batch := make([]sinkEntry, 5)
+	for i := range batch {
+		batch[i] = sinkEntry{Time: time.Now(), Line: []byte("x")}
+	}
+	var partial *partialFlushError
+	if err := transport.send(batch); !errors.As(err, &partial) || partial.Failed != len(batch) {
+		t.Fatalf("send() error = %v, want all %d entries failed", err, len(batch))
+	}
+	if dials != 1 {
+		t.Fatalf("dials = %d, want 1 per batch while server is down", dials)
+	}
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogSeverity 将 zap 级别映射为 RFC 5424 严重程度
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7 // debug
+	case zapcore.InfoLevel:
+		return 6 // informational
+	case zapcore.WarnLevel:
+		return 4 // warning
+	case zapcore.ErrorLevel:
+		return 3 // error
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return 2 // critical
+	default:
+		return 1 // alert
+	}
+}
+
+// formatRFC5424 按 RFC 5424 组装 syslog 消息：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func formatRFC5424(facility int, hostname, appName string, pid int, entry sinkEntry) string {
+	pri := facility*8 + syslogSeverity(entry.Level)
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		pri,
+		entry.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		nilValue(hostname),
+		nilValue(appName),
+		pid,
+		entry.Line,
+	)
+}
+
+func nilValue(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "_")
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// syslogTransport 负责与 syslog 服务端的连接；写失败时重连一次，每批最多拨号失败一次
+type syslogTransport struct {
+	mu       sync.Mutex
+	network  string
+	address  string
+	facility int
+	hostname string
+	appName  string
+	pid      int
+	conn     net.Conn
+	dial     func(network, address string, timeout time.Duration) (net.Conn, error)
+}
+
+func newSyslogTransport(network, address, appName string, facility int) *syslogTransport {
+	hostname, _ := os.Hostname()
+	if network == "" {
+		network = "udp"
+	}
+	return &syslogTransport{
+		network:  network,
+		address:  address,
+		facility: facility,
+		hostname: hostname,
+		appName:  appName,
+		pid:      os.Getpid(),
+		dial:     net.DialTimeout,
+	}
+}
+
+func (t *syslogTransport) send(batch []sinkEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, entry := range batch {
+		msg := formatRFC5424(t.facility, t.hostname, t.appName, t.pid, entry)
+		connected := t.conn != nil
+		err := t.write(msg)
+		if err != nil && connected {
+			// 已有连接写入失败（如服务端关闭了连接），重连后再试一次
+			t.closeConn()
+			err = t.write(msg)
+		}
+		if err != nil {
+			// 连接或重连失败说明服务端暂不可用，本批剩余日志不再逐条拨号，全部计为失败
+			t.closeConn()
+			return &partialFlushError{Failed: len(batch) - i, Err: err}
+		}
+	}
+	return nil
+}
+
+func (t *syslogTransport) write(msg string) error {
+	if t.conn == nil {
+		conn, err := t.dial(t.network, t.address, 3*time.Second)
+		if err != nil {
+			return err
+		}
+		t.conn = conn
+	}
+	_ = t.conn.SetWriteDeadline(time.Now().Add(3 * time.Second))
+	if t.network == "udp" || t.network == "unixgram" {
+		_, err := t.conn.Write([]byte(msg))
+		return err
+	}
+	// 流式传输使用 RFC 6587 octet-counting 分帧：MSG-LEN SP MSG
+	_, err := fmt.Fprintf(t.conn, "%d %s", len(msg), msg)
+	return err
+}
+
+func (t *syslogTransport) closeConn() {
+	if t.conn != nil {
+		_ = t.conn.Close()
+		t.conn = nil
+	}
+}
@@ -0,0 +1,77 @@
+// Package origin 解析与匹配跨域来源规则。
+// 规则形如 https://admin.example.com（精确匹配）、https://*.example.com（匹配任意层级子域名，不含根域名本身）
+// 或 *（任意来源）；协议与端口必须一致，比较时忽略大小写与末尾的 "/"。
+package origin
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Any 匹配任意来源的规则
+const Any = "*"
+
+// Validate 校验来源规则格式
+func Validate(pattern string) error {
+	if pattern == Any {
+		return nil
+	}
+	u, err := url.Parse(strings.Replace(pattern, "://*.", "://wildcard.", 1))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("invalid origin %q, want scheme://host[:port], https://*.example.com or *", pattern)
+	}
+	if strings.Contains(u.Host, "*") {
+		return fmt.Errorf("invalid origin %q: wildcard is only allowed as the leftmost label", pattern)
+	}
+	return nil
+}
+
+// Normalize 统一规则或来源的大小写并去除末尾的 "/"
+func Normalize(s string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "/"))
+}
+
+// Match 判断来源是否符合规则
+func Match(pattern, origin string) bool {
+	pattern, origin = Normalize(pattern), Normalize(origin)
+	if pattern == Any {
+		return true
+	}
+	if origin == "" {
+		return false
+	}
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return pattern == origin
+	}
+	// 通配规则：协议一致，来源主机（含端口）以 ".<域名>" 结尾且子域名非空
+	prefix := scheme + "://"
+	if !strings.HasPrefix(origin, prefix) {
+		return false
+	}
+	rest := strings.TrimPrefix(origin, prefix)
+	return strings.HasSuffix(rest, "."+host) && len(rest) > len(host)+1 && !strings.ContainsAny(rest, "/@")
+}
+
+// MatchAny 判断来源是否符合任一规则
+func MatchAny(patterns []string, origin string) bool {
+	for _, p := range patterns {
+		if Match(p, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split 解析逗号分隔的来源列表（如 SystemTenant.AllowedOrigins），忽略空项
+func Split(s string) []string {
+	out := []string{}
+	for _, o := range strings.Split(s, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package origin
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, origin string
+		want            bool
+	}{
+		{"*", "https://a.example.com", true},
+		{"https://admin.example.com", "https://admin.example.com", true},
+		{"https://admin.example.com/", "https://ADMIN.example.com", true},
+		{"https://admin.example.com", "http://admin.example.com", false},
+		{"https://admin.example.com", "https://admin.example.com:8443", false},
+		{"https://*.example.com", "https://tenant-a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "https://a.example.com.evil.com", false},
+		{"https://*.example.com", "http://a.example.com", false},
+		{"https://*.example.com:8443", "https://a.example.com:8443", true},
+		{"https://*.example.com:8443", "https://a.example.com", false},
+		{"https://*.example.com", "", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.origin); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{"*", "https://a.example.com", "http://localhost:3000", "https://*.example.com", "https://a.example.com/"}
+	for _, p := range valid {
+		if err := Validate(p); err != nil {
+			t.Errorf("Validate(%q) error = %v", p, err)
+		}
+	}
+	invalid := []string{"", "example.com", "ftp://a.example.com", "https://a.example.com/path", "https://a.*.example.com", "https://*example.com", "https://user@a.example.com"}
+	for _, p := range invalid {
+		if err := Validate(p); err == nil {
+			t.Errorf("Validate(%q) expected error", p)
+		}
+	}
+}
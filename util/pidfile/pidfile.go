@@ -10,6 +10,7 @@ import (
 )
 
 // Write 将 pid 写入指定文件路径；文件存在时会覆盖。
+// 先写临时文件再重命名，平滑升级时新旧进程交替读写也不会读到半写的内容。
 func Write(path string, pid int) error {
 	if strings.TrimSpace(path) == "" {
 		return fmt.Errorf("pid 文件路径为空")
@@ -20,12 +21,30 @@ func Write(path string, pid int) error {
 	}
 
 	content := strconv.Itoa(pid) + "\n"
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+	tmp := fmt.Sprintf("%s.%d.tmp", path, pid)
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("写入 pid 文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
 		return fmt.Errorf("写入 pid 文件失败: %w", err)
 	}
 	return nil
 }
 
+// Read 读取 pid 文件中的进程号。
+func Read(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("pid 文件内容无效: %w", err)
+	}
+	return pid, nil
+}
+
 // Remove 删除 pid 文件；文件不存在视为成功。
 func Remove(path string) error {
 	if strings.TrimSpace(path) == "" {
@@ -36,3 +55,22 @@ func Remove(path string) error {
 	}
 	return nil
 }
+
+// RemoveIfOwner 仅当 pid 文件仍记录 pid 时删除；平滑升级后文件已由新进程改写，旧进程退出时不应删除。
+// 文件不存在视为成功。
+func RemoveIfOwner(path string, pid int) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	got, err := Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if got != pid {
+		return nil
+	}
+	return Remove(path)
+}
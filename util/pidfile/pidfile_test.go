@@ -80,3 +80,31 @@ func TestWrite_WritesNewline(t *testing.T) {
 		t.Fatalf("pid 内容=%q, want %q", strings.TrimSpace(got), strconv.Itoa(pid))
 	}
 }
+
+func TestRemoveIfOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-server.pid")
+
+	if err := Write(path, 100); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// 新进程已改写 pid 文件，旧进程退出时保留
+	if err := Write(path, 200); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := RemoveIfOwner(path, 100); err != nil {
+		t.Fatalf("RemoveIfOwner() error = %v", err)
+	}
+	if pid, err := Read(path); err != nil || pid != 200 {
+		t.Fatalf("Read() = %d, %v, want 200", pid, err)
+	}
+
+	if err := RemoveIfOwner(path, 200); err != nil {
+		t.Fatalf("RemoveIfOwner() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("pid 文件应已删除，Stat() err=%v", err)
+	}
+	if err := RemoveIfOwner(path, 200); err != nil {
+		t.Fatalf("RemoveIfOwner() on missing file error = %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package quic 为主 HTTPS 服务提供可选的 HTTP/3（QUIC over UDP）监听。
+package quic
+
+import (
+	"fmt"
+	"net/http"
+
+	"api-server/config"
+
+	"github.com/quic-go/quic-go/http3"
+	"go.uber.org/zap"
+)
+
+// Context 描述 HTTP/3 相关的运行时信息。
+// Enabled 为 true 表示当前进程启用了 HTTP/3；Server 为对应的 HTTP/3 服务，未启用时为 nil。
+type Context struct {
+	Enabled bool
+	Server  *http3.Server
+}
+
+// Setup 根据全局配置为主 HTTP 服务挂载 HTTP/3 能力，需在 acme.Setup / tlsfile.Setup 之后调用。
+// - 当未启用 HTTP/3 或主服务未配置 TLS 时，仅返回 Disabled 的上下文，不修改传入的 server；
+// - 当启用时：
+//   - 创建与主服务共用 Handler 与证书来源（server.TLSConfig）的 HTTP/3 服务；
+//   - 包装 server.Handler，在 HTTPS 响应中添加 Alt-Svc 头告知客户端可升级到 HTTP/3。
+func Setup(server *http.Server) *Context {
+	ctx := &Context{
+		Enabled: false,
+		Server:  nil,
+	}
+	if !config.EnableHTTP3 {
+		return ctx
+	}
+	if server.TLSConfig == nil {
+		zap.L().Warn("HTTP/3 需要 TLS，当前未启用 ACME 或证书文件模式，已忽略 server.enable_http3")
+		return ctx
+	}
+
+	h3 := &http3.Server{
+		Addr:           fmt.Sprintf(":%d", config.HTTP3Port),
+		Port:           config.HTTP3Port,
+		TLSConfig:      server.TLSConfig,
+		Handler:        server.Handler,
+		MaxHeaderBytes: config.MaxHeaderBytes,
+		IdleTimeout:    config.IdleTimeout,
+	}
+	server.Handler = AltSvc(h3, server.Handler)
+	ctx.Enabled = true
+	ctx.Server = h3
+	zap.L().Info("HTTP/3 已启用", zap.Int("udp_port", config.HTTP3Port))
+	return ctx
+}
+
+// AltSvc 为非 HTTP/3 请求的响应添加 Alt-Svc 头（h3=":<端口>"），HTTP/3 服务尚未开始监听时不添加
+func AltSvc(h3 *http3.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 {
+			_ = h3.SetQUICHeaders(w.Header())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,93 @@
+package quic
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"api-server/config"
+)
+
+func withHTTP3Config(t *testing.T, enabled bool, port int) {
+	t.Helper()
+	originalEnable, originalPort := config.EnableHTTP3, config.HTTP3Port
+	t.Cleanup(func() {
+		config.EnableHTTP3, config.HTTP3Port = originalEnable, originalPort
+	})
+	config.EnableHTTP3, config.HTTP3Port = enabled, port
+}
+
+// TestSetup_Disabled 验证未启用 HTTP/3 或未配置 TLS 时不会修改 server。
+func TestSetup_Disabled(t *testing.T) {
+	withHTTP3Config(t, false, 8443)
+	handler := http.NotFoundHandler()
+	srv := &http.Server{Handler: handler, TLSConfig: &tls.Config{}}
+	if ctx := Setup(srv); ctx.Enabled || ctx.Server != nil {
+		t.Fatalf("Setup() = %+v, want disabled", ctx)
+	}
+
+	config.EnableHTTP3 = true
+	srv = &http.Server{Handler: handler}
+	if ctx := Setup(srv); ctx.Enabled || ctx.Server != nil {
+		t.Fatalf("Setup() without TLS = %+v, want disabled", ctx)
+	}
+}
+
+// TestSetup_AltSvc 验证启用后 HTTPS 响应带上 Alt-Svc，端口取 server.http3_port。
+func TestSetup_AltSvc(t *testing.T) {
+	withHTTP3Config(t, true, 8443)
+	srv := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { _, _ = io.WriteString(w, "ok") }),
+		TLSConfig: &tls.Config{},
+	}
+	ctx := Setup(srv)
+	if !ctx.Enabled || ctx.Server == nil {
+		t.Fatalf("Setup() = %+v, want enabled", ctx)
+	}
+	if ctx.Server.Addr != ":8443" {
+		t.Errorf("Server.Addr = %q, want %q", ctx.Server.Addr, ":8443")
+	}
+
+	// 尚未开始监听 UDP 时不宣告 HTTP/3
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Alt-Svc"); got != "" {
+		t.Fatalf("Alt-Svc before serving = %q, want empty", got)
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	go func() { _ = ctx.Server.Serve(pc) }()
+	t.Cleanup(func() { _ = ctx.Server.Close() })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rec = httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := rec.Header().Get("Alt-Svc"); got != "" {
+			if want := `h3=":8443"; ma=2592000`; got != want {
+				t.Fatalf("Alt-Svc = %q, want %q", got, want)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Alt-Svc not set after HTTP/3 server started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// HTTP/3 请求本身不再重复宣告
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.ProtoMajor = 3
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Alt-Svc"); got != "" {
+		t.Errorf("Alt-Svc on HTTP/3 request = %q, want empty", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Package realip 解析位于反向代理或四层负载均衡之后的客户端真实 IP。
+// 只有直接连接方属于可信代理时才读取转发头，避免客户端伪造来源地址；
+// 转发链从右向左跳过可信代理，取第一个不可信的地址作为客户端 IP。
+package realip
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"api-server/config"
+
+	proxyproto "github.com/pires/go-proxyproto"
+)
+
+// Trusted 判断地址是否属于可信代理
+func Trusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 按 headers 的顺序（优先级）从请求头解析客户端 IP；remote 为直接连接方地址，
+// 不属于可信代理或所有请求头都无法解析时返回 remote
+func ClientIP(remote netip.Addr, header http.Header, trusted []netip.Prefix, headers []string) netip.Addr {
+	remote = remote.Unmap()
+	if !Trusted(remote, trusted) {
+		return remote
+	}
+	for _, name := range headers {
+		var chain []string
+		switch http.CanonicalHeaderKey(name) {
+		case "X-Forwarded-For":
+			chain = splitList(header.Values("X-Forwarded-For"))
+		case "X-Real-Ip":
+			if v := strings.TrimSpace(header.Get("X-Real-IP")); v != "" {
+				chain = []string{v}
+			}
+		case "Forwarded":
+			chain = forwardedFor(header.Values("Forwarded"))
+		}
+		if ip, ok := walkChain(chain, trusted); ok {
+			return ip
+		}
+	}
+	return remote
+}
+
+// walkChain 从右向左跳过可信代理，返回第一个不可信地址；全部可信时返回最左侧地址。
+// 遇到无法解析的地址（如 Forwarded 中的 unknown、混淆标识）视为该请求头不可用。
+func walkChain(chain []string, trusted []netip.Prefix) (netip.Addr, bool) {
+	var last netip.Addr
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, err := parseAddr(chain[i])
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		last = addr
+		if !Trusted(addr, trusted) {
+			return addr, true
+		}
+	}
+	return last, last.IsValid()
+}
+
+// parseAddr 解析 IP，兼容带端口（1.2.3.4:80、[::1]:80）与方括号（[::1]）的写法
+func parseAddr(s string) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr().Unmap(), nil
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
+
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// forwardedFor 按顺序提取 RFC 7239 Forwarded 头中各节点的 for 参数，
+// 如 for=192.0.2.60;proto=http, for="[2001:db8::1]:4711"；缺少 for 的节点记为空串（视为不可解析）
+func forwardedFor(values []string) []string {
+	var out []string
+	for _, element := range splitList(values) {
+		value := ""
+		for _, pair := range strings.Split(element, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(k), "for") {
+				value = strings.Trim(strings.TrimSpace(v), `"`)
+				break
+			}
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+// WrapListener 按 server.proxy_protocol 为监听器开启 PROXY 协议（v1/v2）解析：
+// 来自可信代理的连接使用协议头中的客户端地址（required 模式下必须携带），其它连接携带协议头时拒绝。
+// 关闭时原样返回。可信代理在每个连接建立时读取，随配置热加载生效。
+func WrapListener(ln net.Listener) net.Listener {
+	if config.ProxyProtocol == "" || config.ProxyProtocol == config.ProxyProtocolOff {
+		return ln
+	}
+	trustedPolicy := proxyproto.USE
+	if config.ProxyProtocol == config.ProxyProtocolRequired {
+		trustedPolicy = proxyproto.REQUIRE
+	}
+	return &proxyproto.Listener{
+		Listener: ln,
+		// 策略函数返回错误会使 Accept 失败并终止 http.Server，因此无法解析来源时按不可信处理
+		Policy: func(upstream net.Addr) (proxyproto.Policy, error) {
+			if ap, err := netip.ParseAddrPort(upstream.String()); err == nil && Trusted(ap.Addr(), config.Current().TrustedProxies) {
+				return trustedPolicy, nil
+			}
+			return proxyproto.REJECT, nil
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package realip
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"api-server/config"
+
+	proxyproto "github.com/pires/go-proxyproto"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8:ffff::/48")}
+	all := []string{"X-Forwarded-For", "X-Real-IP", "Forwarded"}
+	tests := []struct {
+		name    string
+		remote  string
+		header  http.Header
+		headers []string
+		want    string
+	}{
+		{"untrusted remote ignores headers", "203.0.113.9", http.Header{"X-Forwarded-For": {"1.1.1.1"}}, all, "203.0.113.9"},
+		{"xff single", "10.0.0.5", http.Header{"X-Forwarded-For": {"198.51.100.7"}}, all, "198.51.100.7"},
+		{"xff skips trusted hops", "10.0.0.5", http.Header{"X-Forwarded-For": {"6.6.6.6, 198.51.100.7, 10.1.2.3"}}, all, "198.51.100.7"},
+		{"xff multiple header lines", "10.0.0.5", http.Header{"X-Forwarded-For": {"6.6.6.6", "198.51.100.7"}}, all, "198.51.100.7"},
+		{"xff all trusted", "10.0.0.5", http.Header{"X-Forwarded-For": {"10.9.9.9, 10.1.2.3"}}, all, "10.9.9.9"},
+		{"xff invalid falls back to x-real-ip", "10.0.0.5", http.Header{"X-Forwarded-For": {"garbage"}, "X-Real-Ip": {"198.51.100.8"}}, all, "198.51.100.8"},
+		{"precedence follows config", "10.0.0.5", http.Header{"X-Forwarded-For": {"198.51.100.7"}, "X-Real-Ip": {"198.51.100.8"}}, []string{"X-Real-IP", "X-Forwarded-For"}, "198.51.100.8"},
+		{"header not enabled", "10.0.0.5", http.Header{"X-Real-Ip": {"198.51.100.8"}}, []string{"X-Forwarded-For"}, "10.0.0.5"},
+		{"forwarded", "10.0.0.5", http.Header{"Forwarded": {`for=192.0.2.60;proto=https;by=10.0.0.5`}}, all, "192.0.2.60"},
+		{"forwarded ipv6 with port", "10.0.0.5", http.Header{"Forwarded": {`for="[2001:db8:cafe::17]:4711", for=10.1.1.1`}}, []string{"Forwarded"}, "2001:db8:cafe::17"},
+		{"forwarded unknown", "10.0.0.5", http.Header{"Forwarded": {`for=unknown`}}, []string{"Forwarded"}, "10.0.0.5"},
+		{"ipv4-mapped remote", "::ffff:10.0.0.5", http.Header{"X-Forwarded-For": {"198.51.100.7:5555"}}, all, "198.51.100.7"},
+		{"ipv6 trusted proxy", "2001:db8:ffff::1", http.Header{"X-Forwarded-For": {"2001:db8:1::1"}}, all, "2001:db8:1::1"},
+	}
+	for _, tt := range tests {
+		got := ClientIP(netip.MustParseAddr(tt.remote), tt.header, trusted, tt.headers)
+		if got.String() != tt.want {
+			t.Errorf("%s: ClientIP() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWrapListenerProxyProtocol(t *testing.T) {
+	prevMode, prevRuntime := config.ProxyProtocol, config.Current()
+	t.Cleanup(func() {
+		config.ProxyProtocol = prevMode
+		config.StoreRuntime(prevRuntime)
+	})
+	config.ProxyProtocol = config.ProxyProtocolOptional
+	rt := *prevRuntime
+	rt.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	config.StoreRuntime(&rt)
+
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ln := WrapListener(raw)
+	defer ln.Close()
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.RemoteAddr)
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	get := func(header *proxyproto.Header) string {
+		t.Helper()
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		if header != nil {
+			if _, err := header.WriteTo(conn); err != nil {
+				t.Fatalf("write PROXY header error = %v", err)
+			}
+		}
+		fmt.Fprint(conn, "GET / HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n")
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("ReadResponse() error = %v", err)
+		}
+		defer resp.Body.Close()
+		buf := make([]byte, 64)
+		n, _ := resp.Body.Read(buf)
+		return string(buf[:n])
+	}
+
+	for _, version := range []byte{1, 2} {
+		header := &proxyproto.Header{
+			Version:           version,
+			Command:           proxyproto.PROXY,
+			TransportProtocol: proxyproto.TCPv4,
+			SourceAddr:        &net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 40000},
+			DestinationAddr:   &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 443},
+		}
+		if got := get(header); got != "198.51.100.7:40000" {
+			t.Errorf("v%d RemoteAddr = %q, want %q", version, got, "198.51.100.7:40000")
+		}
+	}
+	// optional 模式下可信来源也可以不带协议头
+	if got := get(nil); got == "" || got[:10] != "127.0.0.1:" {
+		t.Errorf("RemoteAddr without header = %q, want 127.0.0.1:*", got)
+	}
+}
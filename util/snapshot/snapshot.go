@@ -0,0 +1,68 @@
+// Package snapshot 维护从数据库加载的只读快照：读取时若已超过刷新间隔，在后台重新加载，
+// 不阻塞当前请求；加载失败时保留上一份数据。多实例部署时各实例依靠定期刷新彼此同步。
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RefreshInterval 默认的定期刷新间隔
+const RefreshInterval = 30 * time.Second
+
+// RefreshTimeout 后台刷新单次加载的超时时间
+const RefreshTimeout = 10 * time.Second
+
+// Snapshot 单份快照。Get 返回的数据由调用方只读使用，加载函数每次都应返回新的对象
+type Snapshot[T any] struct {
+	name     string // 用于日志，如“租户跨域来源”
+	load     func(ctx context.Context) (T, error)
+	interval time.Duration
+
+	mu         sync.RWMutex
+	data       T
+	loadedAt   time.Time
+	refreshing atomic.Bool
+}
+
+// New 创建快照；首次 Reload 之前 Get 返回零值，并在后台触发加载
+func New[T any](name string, load func(ctx context.Context) (T, error)) *Snapshot[T] {
+	return &Snapshot[T]{name: name, load: load, interval: RefreshInterval}
+}
+
+// Get 返回当前数据；已过期时在后台刷新
+func (s *Snapshot[T]) Get() T {
+	s.mu.RLock()
+	data := s.data
+	stale := time.Since(s.loadedAt) > s.interval
+	s.mu.RUnlock()
+
+	if stale && s.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer s.refreshing.Store(false)
+			ctx, cancel := context.WithTimeout(context.Background(), RefreshTimeout)
+			defer cancel()
+			_ = s.Reload(ctx)
+		}()
+	}
+	return data
+}
+
+// Reload 立即重新加载；失败时保留旧数据，并在刷新间隔内不再重试
+func (s *Snapshot[T]) Reload(ctx context.Context) error {
+	loaded, err := s.load(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Now()
+	if err != nil {
+		zap.L().Warn("加载"+s.name+"失败，继续使用旧数据", zap.Error(err))
+		return err
+	}
+	s.data = loaded
+	return nil
+}
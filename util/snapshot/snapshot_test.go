@@ -0,0 +1,51 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReloadKeepsOldDataOnError(t *testing.T) {
+	var fail atomic.Bool
+	var version atomic.Int32
+	s := New("测试数据", func(context.Context) (int32, error) {
+		if fail.Load() {
+			return 0, errors.New("db down")
+		}
+		return version.Add(1), nil
+	})
+
+	if err := s.Reload(context.Background()); err != nil || s.Get() != 1 {
+		t.Fatalf("Reload() = %v, Get() = %d, want 1", err, s.Get())
+	}
+	fail.Store(true)
+	if err := s.Reload(context.Background()); err == nil {
+		t.Fatal("Reload() error = nil, want load error")
+	}
+	if got := s.Get(); got != 1 {
+		t.Fatalf("Get() after failed reload = %d, want old value 1", got)
+	}
+}
+
+func TestGetRefreshesStaleDataInBackground(t *testing.T) {
+	var version atomic.Int32
+	s := New("测试数据", func(context.Context) (int32, error) {
+		return version.Add(1), nil
+	})
+	s.interval = 10 * time.Millisecond
+
+	// 首次读取返回零值，同时触发后台加载
+	if got := s.Get(); got != 0 {
+		t.Fatalf("Get() before load = %d, want 0", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for s.Get() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stale snapshot was not refreshed in background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package tlsfile
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"api-server/config"
+
+	"go.uber.org/zap"
+)
+
+// SetupClientAuth 根据 server.client_auth 为已配置 TLS 的服务开启客户端证书校验，
+// 需在 acme.Setup / tlsfile.Setup 之后、quic.Setup 之前调用（HTTP/3 会复制 TLSConfig）。
+// - none 或未启用 TLS 时不做修改，返回 false；
+// - optional：请求客户端证书，提供时必须由 server.client_ca_file 中的 CA 签发；
+// - required：所有 TLS 连接都必须提供有效的客户端证书。
+func SetupClientAuth(server *http.Server) bool {
+	clientAuth, ok := clientAuthType(config.ClientAuth)
+	if !ok {
+		zap.L().Fatal("无效的客户端证书校验模式", zap.String("client_auth", config.ClientAuth))
+	}
+	if clientAuth == tls.NoClientCert {
+		return false
+	}
+	if server.TLSConfig == nil {
+		zap.L().Warn("客户端证书校验需要 TLS，当前未启用 ACME 或证书文件模式，已忽略 server.client_auth")
+		return false
+	}
+
+	pool, err := loadClientCAs(config.ClientCAFile)
+	if err != nil {
+		zap.L().Fatal("加载客户端 CA 证书失败",
+			zap.String("client_ca_file", config.ClientCAFile),
+			zap.Error(err),
+		)
+	}
+	server.TLSConfig.ClientCAs = pool
+	server.TLSConfig.ClientAuth = clientAuth
+	zap.L().Info("客户端证书校验已启用",
+		zap.String("client_auth", config.ClientAuth),
+		zap.String("client_ca_file", config.ClientCAFile),
+	)
+	return true
+}
+
+func clientAuthType(mode string) (tls.ClientAuthType, bool) {
+	switch mode {
+	case "", config.ClientAuthNone:
+		return tls.NoClientCert, true
+	case config.ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, true
+	case config.ClientAuthRequired:
+		return tls.RequireAndVerifyClientCert, true
+	}
+	return tls.NoClientCert, false
+}
+
+// loadClientCAs 读取 PEM 格式的 CA 证书（可包含多张）
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s 中没有有效的 PEM 证书", path)
+	}
+	return pool, nil
+}